@@ -0,0 +1,319 @@
+// logtail 跟踪 zap JSON 日志文件并以便于阅读的彩色格式输出，类似 tail -f。
+//
+// 用法：
+//
+//	logtail [--level warn] [--grep text] [--from-start] <file>
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap/zapcore"
+)
+
+// pollInterval 未收到文件事件时重新检查文件的间隔，用于 fsnotify 不可用的文件系统
+const pollInterval = time.Second
+
+// outputTimeFormat 输出中的时间格式
+const outputTimeFormat = "2006-01-02 15:04:05.000"
+
+// inputTimeLayouts 解析日志中字符串时间时依次尝试的格式
+var inputTimeLayouts = []string{outputTimeFormat, time.RFC3339Nano, time.RFC3339}
+
+// ANSI 颜色
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+	colorGray   = "\x1b[90m"
+)
+
+// 日志中的固定键，与 logger 包的编码配置一致
+const (
+	timeKey    = "time"
+	levelKey   = "level"
+	callerKey  = "caller"
+	messageKey = "msg"
+)
+
+type options struct {
+	minLevel  zapcore.Level
+	grep      string
+	fromStart bool
+	color     bool
+}
+
+func main() {
+	level := flag.String("level", "debug", "minimum level to print (debug, info, warn, error, ...)")
+	grep := flag.String("grep", "", "only print entries whose message or a field value contains this text")
+	fromStart := flag.Bool("from-start", false, "print the existing content before following")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] <file>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	minLevel, err := zapcore.ParseLevel(*level)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "logtail:", err)
+		os.Exit(2)
+	}
+	opts := options{minLevel: minLevel, grep: *grep, fromStart: *fromStart, color: isTerminal(os.Stdout)}
+	if err := follow(flag.Arg(0), opts, bufio.NewWriter(os.Stdout)); err != nil {
+		fmt.Fprintln(os.Stderr, "logtail:", err)
+		os.Exit(1)
+	}
+}
+
+// follow 持续读取 path 的新行并输出；文件被截断时从头读取，被轮转（替换为新文件）时重新打开
+func follow(path string, opts options, out *bufio.Writer) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { f.Close() }()
+	var offset int64
+	if !opts.fromStart {
+		if offset, err = f.Seek(0, io.SeekEnd); err != nil {
+			return err
+		}
+	}
+
+	reader := bufio.NewReader(f)
+	var partial strings.Builder
+	for {
+		chunk, err := reader.ReadString('\n')
+		offset += int64(len(chunk))
+		partial.WriteString(chunk)
+		if err == nil {
+			printLine(out, partial.String(), opts)
+			partial.Reset()
+			continue
+		}
+		if !errors.Is(err, io.EOF) {
+			return err
+		}
+		if err := out.Flush(); err != nil {
+			return err
+		}
+
+		waitForChange(watcher, path)
+
+		reopened, err := reopenIfRotated(f, path)
+		if err != nil {
+			return err
+		}
+		if reopened != nil {
+			// 轮转前未读完的半行属于旧文件，直接输出
+			if partial.Len() > 0 {
+				printLine(out, partial.String(), opts)
+				partial.Reset()
+			}
+			f.Close()
+			f, offset = reopened, 0
+			reader.Reset(f)
+			continue
+		}
+		if info, err := f.Stat(); err == nil && info.Size() < offset {
+			// 文件被截断
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			offset = 0
+			partial.Reset()
+			reader.Reset(f)
+		}
+	}
+}
+
+// waitForChange 等待 path 的文件事件，最多等待 pollInterval
+func waitForChange(watcher *fsnotify.Watcher, path string) {
+	target := filepath.Clean(path)
+	timeout := time.NewTimer(pollInterval)
+	defer timeout.Stop()
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok || filepath.Clean(event.Name) == target {
+				return
+			}
+		case _, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+		case <-timeout.C:
+			return
+		}
+	}
+}
+
+// reopenIfRotated path 已指向另一个文件时打开并返回新文件，否则返回 nil
+func reopenIfRotated(f *os.File, path string) (*os.File, error) {
+	current, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	latest, err := os.Stat(path)
+	if err != nil {
+		// 轮转过程中文件暂时不存在，下次再检查
+		return nil, nil
+	}
+	if os.SameFile(current, latest) {
+		return nil, nil
+	}
+	return os.Open(path)
+}
+
+// printLine 格式化并输出一行日志，非 JSON 行原样输出
+func printLine(out *bufio.Writer, line string, opts options) {
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &fields); err != nil {
+		if opts.grep == "" || strings.Contains(line, opts.grep) {
+			fmt.Fprintln(out, line)
+		}
+		return
+	}
+
+	level, _ := fields[levelKey].(string)
+	if parsed, err := zapcore.ParseLevel(level); err == nil && parsed < opts.minLevel {
+		return
+	}
+	message, _ := fields[messageKey].(string)
+	caller, _ := fields[callerKey].(string)
+	ts := formatTime(fields[timeKey])
+	for _, key := range []string{timeKey, levelKey, callerKey, messageKey} {
+		delete(fields, key)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	matched := opts.grep == "" || strings.Contains(message, opts.grep)
+	for _, key := range keys {
+		value := formatValue(fields[key])
+		if !matched && strings.Contains(value, opts.grep) {
+			matched = true
+		}
+		pairs = append(pairs, key+"="+value)
+	}
+	if !matched {
+		return
+	}
+
+	parts := make([]string, 0, 5)
+	if ts != "" {
+		parts = append(parts, ts)
+	}
+	parts = append(parts, colorize(fmt.Sprintf("%-5s", strings.ToUpper(level)), levelColor(level), opts.color))
+	if caller != "" {
+		parts = append(parts, colorize(caller, colorGray, opts.color))
+	}
+	parts = append(parts, message)
+	if len(pairs) > 0 {
+		parts = append(parts, strings.Join(pairs, " "))
+	}
+	fmt.Fprintln(out, strings.Join(parts, " "))
+}
+
+// formatTime 将日志中的时间转换为本地时间，无法识别时返回原值
+func formatTime(raw interface{}) string {
+	switch v := raw.(type) {
+	case string:
+		for _, layout := range inputTimeLayouts {
+			if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+				return t.Local().Format(outputTimeFormat)
+			}
+		}
+		return v
+	case float64:
+		// 大于 1e12 的值视为纳秒时间戳，否则为秒
+		if v > 1e12 {
+			return time.Unix(0, int64(v)).Local().Format(outputTimeFormat)
+		}
+		sec := int64(v)
+		return time.Unix(sec, int64((v-float64(sec))*1e9)).Local().Format(outputTimeFormat)
+	}
+	return ""
+}
+
+// formatValue 将字段值格式化为 key=value 中的 value，含空白的字符串加引号
+func formatValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+			return fmt.Sprintf("%q", s)
+		}
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// levelColor 返回级别对应的颜色
+func levelColor(level string) string {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case parsed >= zapcore.ErrorLevel:
+		return colorRed
+	case parsed == zapcore.WarnLevel:
+		return colorYellow
+	case parsed == zapcore.InfoLevel:
+		return colorGreen
+	default:
+		return colorGray
+	}
+}
+
+// colorize 在 enabled 时为 s 加上颜色
+func colorize(s, color string, enabled bool) string {
+	if !enabled || color == "" {
+		return s
+	}
+	return color + s + colorReset
+}
+
+// isTerminal 判断 f 是否为终端，输出到管道或文件时不使用颜色
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 使用与输出相同的时间格式，按本地时间解析后输出不变，结果与时区无关
+const testTime = "2024-01-02 03:04:05.678"
+
+func runPrintLine(line string, opts options) string {
+	var buf bytes.Buffer
+	out := bufio.NewWriter(&buf)
+	printLine(out, line, opts)
+	out.Flush()
+	return buf.String()
+}
+
+func TestPrintLine(t *testing.T) {
+	warn := `{"time":"` + testTime + `","level":"warn","caller":"app/main.go:10","msg":"disk low","path":"/tmp","free":10}`
+	info := `{"time":"` + testTime + `","level":"info","msg":"started","user":"alice"}`
+	debugOpts := options{minLevel: zapcore.DebugLevel}
+
+	tests := []struct {
+		name string
+		line string
+		opts options
+		want string
+	}{
+		{"formatted entry", warn, debugOpts,
+			testTime + " WARN  app/main.go:10 disk low free=10 path=/tmp\n"},
+		{"without time and caller", `{"level":"error","msg":"failed","err":"timeout after 5s"}`, debugOpts,
+			`ERROR failed err="timeout after 5s"` + "\n"},
+		{"below min level", info, options{minLevel: zapcore.WarnLevel}, ""},
+		{"at min level", warn, options{minLevel: zapcore.WarnLevel},
+			testTime + " WARN  app/main.go:10 disk low free=10 path=/tmp\n"},
+		{"unknown level is not filtered", `{"level":"custom","msg":"hello"}`, options{minLevel: zapcore.ErrorLevel},
+			"CUSTOM hello\n"},
+		{"grep matches message", warn, options{grep: "disk"},
+			testTime + " WARN  app/main.go:10 disk low free=10 path=/tmp\n"},
+		{"grep matches field value", info, options{grep: "alice"},
+			testTime + " INFO  started user=alice\n"},
+		{"grep does not match field key", info, options{grep: "user"}, ""},
+		{"grep without match", warn, options{grep: "nothing"}, ""},
+		{"non-JSON line", "plain text line\r\n", debugOpts, "plain text line\n"},
+		{"non-JSON line matching grep", "panic: boom", options{grep: "boom"}, "panic: boom\n"},
+		{"non-JSON line without grep match", "panic: boom", options{grep: "other"}, ""},
+		{"empty line", "\n", debugOpts, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runPrintLine(tt.line, tt.opts); got != tt.want {
+				t.Errorf("printLine = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintLineColor(t *testing.T) {
+	line := `{"level":"warn","caller":"app/main.go:10","msg":"disk low"}`
+
+	// 输出不是终端时不包含 ANSI 转义序列
+	if got := runPrintLine(line, options{}); strings.Contains(got, "\x1b") {
+		t.Errorf("uncolored output = %q contains ANSI escapes", got)
+	}
+
+	got := runPrintLine(line, options{color: true})
+	want := colorYellow + "WARN " + colorReset + " " + colorGray + "app/main.go:10" + colorReset + " disk low\n"
+	if got != want {
+		t.Errorf("colored output = %q, want %q", got, want)
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	utc := time.Date(2024, 1, 2, 3, 4, 5, 250000000, time.UTC)
+	local := utc.Local().Format(outputTimeFormat)
+
+	tests := []struct {
+		name string
+		raw  interface{}
+		want string
+	}{
+		{"output format", testTime, testTime},
+		{"RFC3339Nano", "2024-01-02T03:04:05.25Z", local},
+		{"RFC3339", "2024-01-02T03:04:05Z", utc.Truncate(time.Second).Local().Format(outputTimeFormat)},
+		{"epoch seconds", float64(utc.Unix()) + 0.25, local},
+		// float64 只能精确表示整秒的纳秒时间戳
+		{"epoch nanoseconds", float64(utc.Truncate(time.Second).UnixNano()), utc.Truncate(time.Second).Local().Format(outputTimeFormat)},
+		{"unrecognized string", "yesterday", "yesterday"},
+		{"missing", nil, ""},
+		{"other type", true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatTime(tt.raw); got != tt.want {
+				t.Errorf("formatTime(%v) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevelColor(t *testing.T) {
+	tests := []struct {
+		level string
+		want  string
+	}{
+		{"debug", colorGray},
+		{"info", colorGreen},
+		{"INFO", colorGreen},
+		{"warn", colorYellow},
+		{"error", colorRed},
+		{"dpanic", colorRed},
+		{"panic", colorRed},
+		{"fatal", colorRed},
+		{"custom", ""},
+	}
+	for _, tt := range tests {
+		if got := levelColor(tt.level); got != tt.want {
+			t.Errorf("levelColor(%q) = %q, want %q", tt.level, got, tt.want)
+		}
+	}
+}
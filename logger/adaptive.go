@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// adaptiveEntry is either a copy of a written entry or, when flushed is set, a marker used by
+// Sync to wait until everything queued before it has been written
+type adaptiveEntry struct {
+	data    []byte
+	flushed chan struct{}
+}
+
+// adaptiveWriter writes synchronously until more than threshold Write calls are in flight,
+// then queues entries for a background goroutine until the load drops to threshold/2
+type adaptiveWriter struct {
+	w         zapcore.WriteSyncer
+	threshold int32
+	inFlight  atomic.Int32
+
+	// mu guards async and pending transitions and the order of entries entering the queue;
+	// writeMu serialises writes to w between Write callers and the background goroutine
+	mu      sync.Mutex
+	writeMu sync.Mutex
+	async   bool
+	closed  bool
+	pending atomic.Int32
+	queue   chan adaptiveEntry
+	done    chan struct{}
+
+	errMu   sync.Mutex
+	lastErr error
+}
+
+// AdaptiveWriter wraps w so that writes are synchronous under normal load and buffered in a
+// queue of bufSize entries while more than threshold writes are concurrent. Nothing is dropped:
+// a full queue blocks the writer, and a writer only returns to sync mode once the queue is
+// drained. Errors from queued writes are reported by the next Sync. The returned writer also
+// implements io.Closer, which flushes the queue and stops the background goroutine.
+func AdaptiveWriter(w zapcore.WriteSyncer, threshold int, bufSize int) zapcore.WriteSyncer {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	if bufSize <= 0 {
+		bufSize = 1
+	}
+	a := &adaptiveWriter{
+		w:         w,
+		threshold: int32(threshold),
+		queue:     make(chan adaptiveEntry, bufSize),
+		done:      make(chan struct{}),
+	}
+	go a.loop()
+	return a
+}
+
+func (a *adaptiveWriter) Write(p []byte) (int, error) {
+	concurrent := a.inFlight.Add(1)
+	defer a.inFlight.Add(-1)
+
+	a.mu.Lock()
+	switch {
+	case a.closed:
+	case !a.async && concurrent > a.threshold:
+		a.async = true
+	case a.async && concurrent <= a.threshold/2 && a.pending.Load() == 0:
+		// everything queued has been written, so a synchronous write cannot overtake it
+		a.async = false
+	}
+	if a.async && !a.closed {
+		entry := make([]byte, len(p))
+		copy(entry, p)
+		a.pending.Add(1)
+		a.queue <- adaptiveEntry{data: entry}
+		a.mu.Unlock()
+		return len(p), nil
+	}
+	// release mu before waiting for w so a slow write cannot hold back the switch to async;
+	// sync mode is only re-entered once the queue is empty, so this cannot overtake a queued entry
+	a.mu.Unlock()
+	a.writeMu.Lock()
+	defer a.writeMu.Unlock()
+	return a.w.Write(p)
+}
+
+// Sync waits until every queued entry has been written, then syncs w
+func (a *adaptiveWriter) Sync() error {
+	a.flush()
+	a.errMu.Lock()
+	err := a.lastErr
+	a.lastErr = nil
+	a.errMu.Unlock()
+
+	a.writeMu.Lock()
+	defer a.writeMu.Unlock()
+	if syncErr := a.w.Sync(); syncErr != nil {
+		return syncErr
+	}
+	return err
+}
+
+// Close flushes the queue and stops the background goroutine; later writes are synchronous
+func (a *adaptiveWriter) Close() error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		a.async = false
+		close(a.queue)
+	}
+	a.mu.Unlock()
+	<-a.done
+	return a.Sync()
+}
+
+// flush sends a marker through the queue and waits for the background goroutine to reach it
+func (a *adaptiveWriter) flush() {
+	a.mu.Lock()
+	if a.closed || a.pending.Load() == 0 {
+		a.mu.Unlock()
+		return
+	}
+	marker := adaptiveEntry{flushed: make(chan struct{})}
+	a.queue <- marker
+	a.mu.Unlock()
+	<-marker.flushed
+}
+
+func (a *adaptiveWriter) loop() {
+	defer close(a.done)
+	for entry := range a.queue {
+		if entry.flushed != nil {
+			close(entry.flushed)
+			continue
+		}
+		a.writeMu.Lock()
+		_, err := a.w.Write(entry.data)
+		a.writeMu.Unlock()
+		if err != nil {
+			a.errMu.Lock()
+			a.lastErr = err
+			a.errMu.Unlock()
+		}
+		a.pending.Add(-1)
+	}
+}
@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// gatedSyncer records writes; the first Write signals entered and blocks until gate is closed
+type gatedSyncer struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	blocked atomic.Bool
+	gate    chan struct{}
+	entered chan struct{}
+}
+
+func newGatedSyncer() *gatedSyncer {
+	return &gatedSyncer{gate: make(chan struct{}), entered: make(chan struct{}, 1)}
+}
+
+func (s *gatedSyncer) Write(p []byte) (int, error) {
+	if s.blocked.CompareAndSwap(false, true) {
+		s.entered <- struct{}{}
+		<-s.gate
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *gatedSyncer) Sync() error { return nil }
+
+func (s *gatedSyncer) lines() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return strings.Split(strings.TrimSuffix(s.buf.String(), "\n"), "\n")
+}
+
+func (a *adaptiveWriter) isAsync() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.async
+}
+
+func TestAdaptiveWriterSwitchesToAsync(t *testing.T) {
+	ws := newGatedSyncer()
+	w := AdaptiveWriter(ws, 2, 16).(*adaptiveWriter)
+	defer w.Close()
+
+	var wg sync.WaitGroup
+	write := func(s string) {
+		defer wg.Done()
+		if _, err := w.Write([]byte(s + "\n")); err != nil {
+			t.Errorf("Write(%q): %v", s, err)
+		}
+	}
+
+	// the first write blocks inside ws, the second joins it: two in flight, still sync
+	wg.Add(2)
+	go write("a")
+	<-ws.entered
+	go write("b")
+	deadline := time.Now().Add(2 * time.Second)
+	for w.inFlight.Load() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("second write never started")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if w.isAsync() {
+		t.Fatal("async at threshold, want sync")
+	}
+
+	// a third concurrent write exceeds the threshold and is queued without blocking
+	returned := make(chan struct{})
+	go func() {
+		w.Write([]byte("c\n"))
+		close(returned)
+	}()
+	select {
+	case <-returned:
+	case <-time.After(2 * time.Second):
+		t.Fatal("write above threshold blocked, want it queued")
+	}
+	if !w.isAsync() {
+		t.Fatal("not async above threshold")
+	}
+
+	close(ws.gate)
+	wg.Wait()
+	if err := w.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	got := ws.lines()
+	if len(got) != 3 || !strings.Contains(strings.Join(got, ","), "c") {
+		t.Fatalf("written = %q, want a, b and c", got)
+	}
+
+	// load has dropped and the queue is drained, so the next write is synchronous again
+	w.Write([]byte("d\n"))
+	if w.isAsync() {
+		t.Error("still async after load dropped")
+	}
+	if got := ws.lines(); got[len(got)-1] != "d" {
+		t.Errorf("sync write not visible immediately: %q", got)
+	}
+}
+
+func TestAdaptiveWriterFlushesEverythingOnSync(t *testing.T) {
+	ws := newGatedSyncer()
+	close(ws.gate)
+	w := AdaptiveWriter(ws, 4, 8)
+	defer w.(io.Closer).Close()
+
+	const writers, perWriter = 32, 200
+	var wg sync.WaitGroup
+	wg.Add(writers)
+	for i := 0; i < writers; i++ {
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < perWriter; j++ {
+				fmt.Fprintf(w, "%d-%d\n", i, j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if err := w.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, line := range ws.lines() {
+		seen[line] = true
+	}
+	if len(seen) != writers*perWriter {
+		t.Fatalf("%d distinct entries after Sync, want %d", len(seen), writers*perWriter)
+	}
+}
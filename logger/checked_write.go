@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// writeChecked writes ent and fields through core's own Check, so that a wrapper around a Tee
+// (such as the per-level file cores of Logger.Core()) only reaches the sub-cores that accept
+// ent instead of all of them. It returns the error reported by the sub-cores' Write.
+func writeChecked(core zapcore.Core, ent zapcore.Entry, fields []zapcore.Field) error {
+	ce := core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+	capture := &writeErrorCapture{}
+	ce.ErrorOutput = capture
+	ce.Write(fields...)
+	return capture.err
+}
+
+// writeErrorCapture receives the "<time> write error: <err>" line CheckedEntry.Write reports
+// through ErrorOutput and keeps the error part
+type writeErrorCapture struct {
+	err error
+}
+
+func (c *writeErrorCapture) Write(p []byte) (int, error) {
+	msg := strings.TrimSpace(string(p))
+	if _, after, ok := strings.Cut(msg, "write error: "); ok {
+		msg = after
+	}
+	c.err = errors.New(msg)
+	return len(p), nil
+}
+
+func (c *writeErrorCapture) Sync() error {
+	return nil
+}
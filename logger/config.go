@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadZapConfigFromYAML decodes a ZapConfig from YAML without viper. Both a document with a
+// top-level "zap" key (the layout of config.yaml) and a bare ZapConfig document are accepted.
+// Fields missing from the document keep their default values.
+func LoadZapConfigFromYAML(r io.Reader) (*ZapConfig, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read yaml config: %v", err)
+	}
+
+	var probe struct {
+		Zap yaml.Node `yaml:"zap"`
+	}
+	if err := yaml.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("error unmarshalling yaml config: %v", err)
+	}
+
+	cfg := getDefaultConfig()
+	if probe.Zap.Kind != 0 {
+		err = probe.Zap.Decode(&cfg)
+	} else if len(bytes.TrimSpace(data)) > 0 {
+		err = yaml.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshalling yaml config: %v", err)
+	}
+	return &cfg, nil
+}
+
+// Validate checks that the ZapConfig can be used to build a logger
+func (c *ZapConfig) Validate() error {
+	if _, err := zapcore.ParseLevel(c.Level); err != nil {
+		return fmt.Errorf("invalid log level %q: %v", c.Level, err)
+	}
+	for _, format := range []string{c.Format, c.ConsoleFormat} {
+		switch format {
+		case "", "json", "ndjson", "console":
+		default:
+			return fmt.Errorf("invalid log format %q", format)
+		}
+	}
+	switch NamingStrategy(c.FieldNamingStrategy) {
+	case "", NamingOriginal, NamingSnakeCase, NamingCamelCase:
+	default:
+		return fmt.Errorf("invalid field naming strategy %q", c.FieldNamingStrategy)
+	}
+	switch c.FilenameDateFormat {
+	case "", "hourly", "daily", "static":
+	default:
+		return fmt.Errorf("invalid filename date format %q", c.FilenameDateFormat)
+	}
+	if c.ConsoleLevel != "" {
+		if _, err := zapcore.ParseLevel(c.ConsoleLevel); err != nil {
+			return fmt.Errorf("invalid console log level %q: %v", c.ConsoleLevel, err)
+		}
+	}
+	if c.Director == "" {
+		return fmt.Errorf("log director must not be empty")
+	}
+	if c.RetentionDay < 0 {
+		return fmt.Errorf("retention-day must not be negative, got %d", c.RetentionDay)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadZapConfigFromYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"zap section", "zap:\n  level: debug\n  format: console\n  field-order: [trace_id, msg]\n"},
+		{"bare document", "level: debug\nformat: console\nfield-order: [trace_id, msg]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := LoadZapConfigFromYAML(strings.NewReader(tt.yaml))
+			if err != nil {
+				t.Fatalf("LoadZapConfigFromYAML: %v", err)
+			}
+			if cfg.Level != "debug" || cfg.Format != "console" || len(cfg.FieldOrder) != 2 {
+				t.Errorf("cfg = %+v", cfg)
+			}
+			// 未出现的字段保持默认值
+			if cfg.Director != "./logs" || cfg.RetentionDay != 7 || !cfg.LogInConsole {
+				t.Errorf("defaults not kept: %+v", cfg)
+			}
+		})
+	}
+
+	cfg, err := LoadZapConfigFromYAML(strings.NewReader(""))
+	if err != nil || cfg.Level != "info" {
+		t.Errorf("empty document = %+v, %v; want defaults", cfg, err)
+	}
+	if _, err := LoadZapConfigFromYAML(strings.NewReader("zap: [")); err == nil {
+		t.Error("malformed YAML returned no error")
+	}
+}
+
+func TestZapConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*ZapConfig)
+		wantErr bool
+	}{
+		{"defaults", func(*ZapConfig) {}, false},
+		{"invalid level", func(c *ZapConfig) { c.Level = "loud" }, true},
+		{"invalid format", func(c *ZapConfig) { c.Format = "xml" }, true},
+		{"invalid console format", func(c *ZapConfig) { c.ConsoleFormat = "xml" }, true},
+		{"ndjson", func(c *ZapConfig) { c.Format = "ndjson" }, false},
+		{"invalid naming strategy", func(c *ZapConfig) { c.FieldNamingStrategy = "kebab" }, true},
+		{"invalid filename date format", func(c *ZapConfig) { c.FilenameDateFormat = "weekly" }, true},
+		{"invalid console level", func(c *ZapConfig) { c.ConsoleLevel = "loud" }, true},
+		{"empty director", func(c *ZapConfig) { c.Director = "" }, true},
+		{"negative retention", func(c *ZapConfig) { c.RetentionDay = -1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := getDefaultConfig()
+			tt.mutate(&cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
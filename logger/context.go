@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// sugaredChildKey identifies the sugared child of a base logger cached in a contextFields
+type sugaredChildKey struct {
+	base *zap.Logger
+}
+
+// WithFields returns a copy of ctx carrying fields in addition to those already attached.
+// It shares its storage with ContextWithFields; the child logger built from the fields is
+// created on first use by FromContext and cached in the context.
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	return ContextWithFields(ctx, fields...)
+}
+
+// FromContext returns the global logger with the fields attached to ctx, for logging from the
+// caller's own code: the reported caller is the code calling FromContext(ctx).Info and so on.
+// Call it at log time instead of keeping the result, so that a later InitLogger takes effect.
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	d := currentDirect()
+	return sugaredFromContext(ctx, d.direct, d.sugared)
+}
+
+// ctxLogger is FromContext for the *Ctx functions: it keeps the caller skip of the global
+// SugaredLogger, which accounts for the extra frame of the helper
+func ctxLogger(ctx context.Context) *zap.SugaredLogger {
+	EnsureInitialized()
+	return sugaredFromContext(ctx, Logger, SugaredLogger)
+}
+
+// sugaredFromContext returns the sugared child of base with the fields attached to ctx, or
+// sugared itself when ctx carries no fields
+func sugaredFromContext(ctx context.Context, base *zap.Logger, sugared *zap.SugaredLogger) *zap.SugaredLogger {
+	cf, ok := ctx.Value(contextFieldsKey{}).(*contextFields)
+	if !ok || len(cf.fields) == 0 {
+		return sugared
+	}
+	key := sugaredChildKey{base: base}
+	if l, ok := cf.children.Load(key); ok {
+		return l.(*zap.SugaredLogger)
+	}
+	l, _ := cf.children.LoadOrStore(key, base.With(cf.fields...).Sugar())
+	return l.(*zap.SugaredLogger)
+}
+
+func DebugCtx(ctx context.Context, args ...interface{}) {
+	ctxLogger(ctx).Debug(args...)
+}
+
+func DebugfCtx(ctx context.Context, template string, args ...interface{}) {
+	ctxLogger(ctx).Debugf(template, args...)
+}
+
+func InfoCtx(ctx context.Context, args ...interface{}) {
+	ctxLogger(ctx).Info(args...)
+}
+
+func InfofCtx(ctx context.Context, template string, args ...interface{}) {
+	ctxLogger(ctx).Infof(template, args...)
+}
+
+func WarnCtx(ctx context.Context, args ...interface{}) {
+	ctxLogger(ctx).Warn(args...)
+}
+
+func WarnfCtx(ctx context.Context, template string, args ...interface{}) {
+	ctxLogger(ctx).Warnf(template, args...)
+}
+
+func ErrorCtx(ctx context.Context, args ...interface{}) {
+	ctxLogger(ctx).Error(args...)
+}
+
+func ErrorfCtx(ctx context.Context, template string, args ...interface{}) {
+	ctxLogger(ctx).Errorf(template, args...)
+}
+
+func DPanicCtx(ctx context.Context, args ...interface{}) {
+	ctxLogger(ctx).DPanic(args...)
+}
+
+func DPanicfCtx(ctx context.Context, template string, args ...interface{}) {
+	ctxLogger(ctx).DPanicf(template, args...)
+}
+
+func PanicCtx(ctx context.Context, args ...interface{}) {
+	ctxLogger(ctx).Panic(args...)
+}
+
+func PanicfCtx(ctx context.Context, template string, args ...interface{}) {
+	ctxLogger(ctx).Panicf(template, args...)
+}
+
+func FatalCtx(ctx context.Context, args ...interface{}) {
+	ctxLogger(ctx).Fatal(args...)
+}
+
+func FatalfCtx(ctx context.Context, template string, args ...interface{}) {
+	ctxLogger(ctx).Fatalf(template, args...)
+}
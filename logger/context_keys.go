@@ -0,0 +1,44 @@
+package logger
+
+import "context"
+
+// Context keys are unexported struct types so no other package can read or overwrite them;
+// use the typed setters and getters below instead of context.WithValue with string keys.
+type (
+	requestIDKey     struct{}
+	correlationIDKey struct{}
+	actorIDKey       struct{}
+)
+
+// SetRequestID returns a copy of ctx carrying the request ID
+func SetRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// GetRequestID returns the request ID set by SetRequestID
+func GetRequestID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok
+}
+
+// SetCorrelationID returns a copy of ctx carrying the correlation ID shared across services
+func SetCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// GetCorrelationID returns the correlation ID set by SetCorrelationID
+func GetCorrelationID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	return id, ok
+}
+
+// SetActorID returns a copy of ctx carrying the ID of the user or service performing the request
+func SetActorID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, actorIDKey{}, id)
+}
+
+// GetActorID returns the actor ID set by SetActorID
+func GetActorID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(actorIDKey{}).(string)
+	return id, ok
+}
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContextKeysDoNotCollide(t *testing.T) {
+	accessors := []struct {
+		name string
+		set  func(context.Context, string) context.Context
+		get  func(context.Context) (string, bool)
+	}{
+		{"request ID", SetRequestID, GetRequestID},
+		{"correlation ID", SetCorrelationID, GetCorrelationID},
+		{"actor ID", SetActorID, GetActorID},
+	}
+	for i, setter := range accessors {
+		ctx := setter.set(context.Background(), "value")
+		// keys that a string-keyed WithValue elsewhere might use must not reach the typed keys
+		ctx = context.WithValue(ctx, "requestID", "untyped")
+
+		for j, getter := range accessors {
+			id, ok := getter.get(ctx)
+			if i == j {
+				if !ok || id != "value" {
+					t.Errorf("%s: get = %q, %v, want the value set", setter.name, id, ok)
+				}
+				continue
+			}
+			if ok || id != "" {
+				t.Errorf("%s set, %s get = %q, %v, want nothing", setter.name, getter.name, id, ok)
+			}
+		}
+	}
+}
+
+func TestContextKeysAllSet(t *testing.T) {
+	ctx := SetRequestID(context.Background(), "req")
+	ctx = SetCorrelationID(ctx, "corr")
+	ctx = SetActorID(ctx, "actor")
+	ctx = SetRequestID(ctx, "req-2")
+
+	if id, _ := GetRequestID(ctx); id != "req-2" {
+		t.Errorf("request ID = %q, want the latest value", id)
+	}
+	if id, _ := GetCorrelationID(ctx); id != "corr" {
+		t.Errorf("correlation ID = %q", id)
+	}
+	if id, _ := GetActorID(ctx); id != "actor" {
+		t.Errorf("actor ID = %q", id)
+	}
+	if _, ok := GetRequestID(context.Background()); ok {
+		t.Error("GetRequestID on an empty context reported a value")
+	}
+}
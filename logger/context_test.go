@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// assertEntry checks the level, message, caller file and fields of an observed entry
+func assertEntry(t *testing.T, entry observer.LoggedEntry, level zapcore.Level, msg string, fields map[string]interface{}) {
+	t.Helper()
+	if entry.Level != level || entry.Message != msg {
+		t.Errorf("entry = %v %q, want %v %q", entry.Level, entry.Message, level, msg)
+	}
+	if file := filepath.Base(entry.Caller.File); file != "context_test.go" {
+		t.Errorf("caller = %s, want context_test.go", entry.Caller.String())
+	}
+	got := entry.ContextMap()
+	if len(got) != len(fields) {
+		t.Errorf("fields = %v, want %v", got, fields)
+	}
+	for k, v := range fields {
+		if got[k] != v {
+			t.Errorf("field %s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+func TestWithFieldsPropagatesToFromContext(t *testing.T) {
+	logs := ObserveGlobal(t, zapcore.DebugLevel)
+	ctx := WithFields(context.Background(), zap.String("request_id", "r1"))
+	child := WithFields(ctx, zap.String("user_id", "u1"))
+
+	FromContext(child).Infow("handled", "status", 200)
+	FromContext(ctx).Warn("parent")
+	FromContext(context.Background()).Error("plain")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	assertEntry(t, entries[0], zapcore.InfoLevel, "handled",
+		map[string]interface{}{"request_id": "r1", "user_id": "u1", "status": int64(200)})
+	assertEntry(t, entries[1], zapcore.WarnLevel, "parent", map[string]interface{}{"request_id": "r1"})
+	assertEntry(t, entries[2], zapcore.ErrorLevel, "plain", map[string]interface{}{})
+}
+
+func TestFromContextReusesChildForSameContext(t *testing.T) {
+	ObserveGlobal(t, zapcore.DebugLevel)
+	ctx := WithFields(context.Background(), zap.String("request_id", "r1"))
+	if FromContext(ctx) != FromContext(ctx) {
+		t.Error("FromContext built a new child for the same context")
+	}
+	// the *Ctx helpers keep their own child with the helper's caller skip
+	if FromContext(ctx) == ctxLogger(ctx) {
+		t.Error("FromContext and the *Ctx helpers share a child")
+	}
+}
+
+func TestCtxHelpersUseContextFields(t *testing.T) {
+	logs := ObserveGlobal(t, zapcore.DebugLevel)
+	ctx := WithFields(context.Background(), zap.String("request_id", "r1"))
+
+	tests := []struct {
+		log   func()
+		level zapcore.Level
+		msg   string
+	}{
+		{func() { DebugCtx(ctx, "debug") }, zapcore.DebugLevel, "debug"},
+		{func() { DebugfCtx(ctx, "debug %d", 1) }, zapcore.DebugLevel, "debug 1"},
+		{func() { InfoCtx(ctx, "info") }, zapcore.InfoLevel, "info"},
+		{func() { InfofCtx(ctx, "info %d", 2) }, zapcore.InfoLevel, "info 2"},
+		{func() { WarnCtx(ctx, "warn") }, zapcore.WarnLevel, "warn"},
+		{func() { WarnfCtx(ctx, "warn %d", 3) }, zapcore.WarnLevel, "warn 3"},
+		{func() { ErrorCtx(ctx, "error") }, zapcore.ErrorLevel, "error"},
+		{func() { ErrorfCtx(ctx, "error %d", 4) }, zapcore.ErrorLevel, "error 4"},
+	}
+	for _, tt := range tests {
+		tt.log()
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != len(tests) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(tests))
+	}
+	for i, tt := range tests {
+		assertEntry(t, entries[i], tt.level, tt.msg, map[string]interface{}{"request_id": "r1"})
+	}
+}
+
+func TestCtxHelpersWithoutFields(t *testing.T) {
+	logs := ObserveGlobal(t, zapcore.DebugLevel)
+	InfoCtx(context.Background(), "no fields")
+	if logs.Len() != 1 {
+		t.Fatalf("got %d entries, want 1", logs.Len())
+	}
+	assertEntry(t, logs.All()[0], zapcore.InfoLevel, "no fields", map[string]interface{}{})
+}
+
+func TestGormLoggerUsesContextFields(t *testing.T) {
+	gl, logs := newObservedGormLogger()
+	gl = gl.LogMode(gormlogger.Info).(GormLogger)
+	ctx := WithFields(context.Background(), zap.String("request_id", "r1"))
+
+	gl.Info(ctx, "info %d", 1)
+	gl.Trace(ctx, time.Now(), mockFC("SELECT 1", 1), nil)
+	// loggerFor falls back to the plain logger for a nil context
+	gl.Warn(nil, "no context")
+
+	entries := logs.AllUntimed()
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	for _, entry := range entries[:2] {
+		if got := entry.ContextMap()["request_id"]; got != "r1" {
+			t.Errorf("%q request_id = %v, want r1", entry.Message, got)
+		}
+	}
+	if got := entries[2].ContextMap(); len(got) != 0 {
+		t.Errorf("nil context fields = %v, want none", got)
+	}
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// DedupeCore suppresses repeated level+message combinations within a fixed window.
+// The first occurrence in a window is written; later ones are counted, and at the end of
+// the window a goroutine writes "suppressed N identical messages: <message>". Entries and
+// summaries go through the wrapped core's Check, so wrapping a Tee of per-level cores keeps
+// each entry in the cores enabled for its level.
+type DedupeCore struct {
+	zapcore.Core
+	state *dedupeState
+}
+
+type dedupeState struct {
+	mu        sync.Mutex
+	seen      map[[sha256.Size]byte]*dedupeEntry
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// dedupeEntry remembers the first occurrence of a message and the core that wrote it,
+// so the summary carries the same context fields
+type dedupeEntry struct {
+	ent        zapcore.Entry
+	core       zapcore.Core
+	suppressed int
+}
+
+// NewDedupeCore wraps base with deduplication over window. Call Close on the returned core
+// to stop its background goroutine and flush the pending summaries.
+func NewDedupeCore(base zapcore.Core, window time.Duration) *DedupeCore {
+	state := &dedupeState{
+		seen: make(map[[sha256.Size]byte]*dedupeEntry),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go state.loop(window)
+	return &DedupeCore{Core: base, state: state}
+}
+
+func (c *DedupeCore) With(fields []zapcore.Field) zapcore.Core {
+	return &DedupeCore{Core: c.Core.With(fields), state: c.state}
+}
+
+func (c *DedupeCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *DedupeCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	key := sha256.Sum256([]byte(ent.Level.String() + ent.Message))
+
+	c.state.mu.Lock()
+	if seen, ok := c.state.seen[key]; ok {
+		seen.suppressed++
+		c.state.mu.Unlock()
+		return nil
+	}
+	c.state.seen[key] = &dedupeEntry{ent: ent, core: c.Core}
+	c.state.mu.Unlock()
+
+	return writeChecked(c.Core, ent, fields)
+}
+
+// Close stops the window goroutine and writes the summaries of the current window
+func (c *DedupeCore) Close() error {
+	c.state.closeOnce.Do(func() {
+		close(c.state.stop)
+		<-c.state.done
+	})
+	return nil
+}
+
+func (s *dedupeState) loop(window time.Duration) {
+	defer close(s.done)
+	ticker := time.NewTicker(window)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.flush()
+		case <-s.stop:
+			s.flush()
+			return
+		}
+	}
+}
+
+// flush starts a new window and writes a summary for every message that was suppressed in the old one
+func (s *dedupeState) flush() {
+	s.mu.Lock()
+	seen := s.seen
+	s.seen = make(map[[sha256.Size]byte]*dedupeEntry)
+	s.mu.Unlock()
+
+	now := time.Now()
+	for _, e := range seen {
+		if e.suppressed == 0 {
+			continue
+		}
+		summary := e.ent
+		summary.Time = now
+		summary.Stack = ""
+		summary.Message = fmt.Sprintf("suppressed %d identical messages: %s", e.suppressed, e.ent.Message)
+		_ = writeChecked(e.core, summary, nil)
+	}
+}
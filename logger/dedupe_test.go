@@ -0,0 +1,134 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDedupeCoreSuppressesWithinWindow(t *testing.T) {
+	base, logs := observer.New(zapcore.DebugLevel)
+	core := NewDedupeCore(base, time.Hour)
+	l := zap.New(core)
+
+	for i := 0; i < 10; i++ {
+		l.Error("db timeout")
+	}
+	l.Warn("db timeout") // same message at another level is a different key
+	l.Error("cache miss")
+
+	if n := logs.Len(); n != 3 {
+		t.Fatalf("%d entries written within the window, want 3", n)
+	}
+
+	// Close ends the window and writes the pending summaries
+	if err := core.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	summaries := logs.FilterMessage("suppressed 9 identical messages: db timeout").All()
+	if len(summaries) != 1 {
+		t.Fatalf("summaries = %v, want one for 9 suppressed errors", logs.All())
+	}
+	if summaries[0].Level != zapcore.ErrorLevel {
+		t.Errorf("summary level = %v, want error", summaries[0].Level)
+	}
+	if n := logs.Len(); n != 4 {
+		t.Errorf("%d entries after Close, want 4 (no summary for unrepeated messages)", n)
+	}
+}
+
+func TestDedupeCoreEmitsSummaryOnWindowBoundary(t *testing.T) {
+	base, logs := observer.New(zapcore.DebugLevel)
+	const window = 50 * time.Millisecond
+	core := NewDedupeCore(base, window)
+	defer core.Close()
+	l := zap.New(core).With(zap.String("component", "db"))
+
+	for i := 0; i < 5; i++ {
+		l.Info("retrying")
+	}
+
+	deadline := time.Now().Add(20 * window)
+	for logs.FilterMessage("suppressed 4 identical messages: retrying").Len() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("no summary within %v, entries: %v", 20*window, logs.All())
+		}
+		time.Sleep(window / 5)
+	}
+	summary := logs.FilterMessage("suppressed 4 identical messages: retrying").All()[0]
+	if summary.ContextMap()["component"] != "db" {
+		t.Errorf("summary context = %v, want the fields of the original logger", summary.ContextMap())
+	}
+
+	// a new window writes the message again
+	l.Info("retrying")
+	if n := logs.FilterMessage("retrying").Len(); n != 2 {
+		t.Errorf("%d \"retrying\" entries after the window ended, want 2", n)
+	}
+}
+
+// levelTee returns a Tee of cores that each accept exactly one level, like the per-level file
+// cores of Logger.Core(), and the entries recorded by each
+func levelTee(levels ...zapcore.Level) (zapcore.Core, map[zapcore.Level]*observer.ObservedLogs) {
+	cores := make([]zapcore.Core, 0, len(levels))
+	logs := make(map[zapcore.Level]*observer.ObservedLogs, len(levels))
+	for _, level := range levels {
+		level := level
+		core, observed := observer.New(zap.LevelEnablerFunc(func(l zapcore.Level) bool { return l == level }))
+		cores = append(cores, core)
+		logs[level] = observed
+	}
+	return zapcore.NewTee(cores...), logs
+}
+
+func TestDedupeCoreKeepsTeeLevels(t *testing.T) {
+	base, logs := levelTee(zapcore.InfoLevel, zapcore.WarnLevel, zapcore.ErrorLevel)
+	core := NewDedupeCore(base, time.Hour)
+	l := zap.New(core)
+
+	l.Info("started")
+	for i := 0; i < 3; i++ {
+		l.Error("db timeout")
+	}
+	if err := core.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if n := logs[zapcore.WarnLevel].Len(); n != 0 {
+		t.Errorf("warn core got %d entries: %v", n, logs[zapcore.WarnLevel].All())
+	}
+	if info := logs[zapcore.InfoLevel].All(); len(info) != 1 || info[0].Message != "started" {
+		t.Errorf("info core = %v, want only \"started\"", info)
+	}
+	// the summary is routed like the entry it summarises
+	errs := logs[zapcore.ErrorLevel].All()
+	if len(errs) != 2 || errs[0].Message != "db timeout" || errs[1].Message != "suppressed 2 identical messages: db timeout" {
+		t.Errorf("error core = %v, want the entry and its summary", errs)
+	}
+}
+
+// failingCore accepts every entry and fails to write it
+type failingCore struct {
+	zapcore.LevelEnabler
+	err error
+}
+
+func (c failingCore) With([]zapcore.Field) zapcore.Core { return c }
+func (c failingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+func (c failingCore) Write(zapcore.Entry, []zapcore.Field) error { return c.err }
+func (c failingCore) Sync() error                                { return nil }
+
+func TestDedupeCoreReturnsWriteError(t *testing.T) {
+	core := NewDedupeCore(failingCore{LevelEnabler: zapcore.DebugLevel, err: errors.New("disk full")}, time.Hour)
+	defer core.Close()
+	err := core.Write(zapcore.Entry{Level: zapcore.InfoLevel, Message: "m"}, nil)
+	if err == nil || err.Error() != "disk full" {
+		t.Errorf("Write = %v, want the base core's error", err)
+	}
+}
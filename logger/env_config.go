@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// LoadZapConfigFromEnv builds a ZapConfig from environment variables named <PREFIX>_<TAG>,
+// where TAG is the field's mapstructure tag in UPPER_SNAKE_CASE (e.g. LOG_LEVEL, LOG_LOG_IN_CONSOLE
+// for prefix "LOG"). Fields without a variable keep their default values; other variables
+// sharing the prefix are ignored.
+func LoadZapConfigFromEnv(prefix string) (*ZapConfig, error) {
+	cfg := getDefaultConfig()
+	v := reflect.ValueOf(&cfg).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("mapstructure"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := envName(prefix, tag)
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setFromEnv(v.Field(i), raw); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %v", name, err)
+		}
+	}
+	return &cfg, nil
+}
+
+// envName converts a mapstructure tag such as "log-in-console" into PREFIX_LOG_IN_CONSOLE
+func envName(prefix, tag string) string {
+	name := strings.ToUpper(strings.ReplaceAll(tag, "-", "_"))
+	if prefix == "" {
+		return name
+	}
+	return strings.ToUpper(prefix) + "_" + name
+}
+
+// setFromEnv parses raw into the string, bool, int or comma-separated []string field
+func setFromEnv(field reflect.Value, raw string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type %s", field.Type())
+		}
+		var values []string
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		field.Set(reflect.ValueOf(values))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
+
+// InitLoggerFromEnv initializes the global logger from LOG_LEVEL, LOG_FORMAT, LOG_DIR,
+// LOG_IN_CONSOLE and LOG_RETENTION_DAYS without viper or a config file. Unset variables
+// keep the defaults of getDefaultConfig; use LoadZapConfigFromEnv to set any other field.
+func InitLoggerFromEnv() error {
+	cfg := getDefaultConfig()
+	vars := []struct {
+		name  string
+		field interface{}
+	}{
+		{"LOG_LEVEL", &cfg.Level},
+		{"LOG_FORMAT", &cfg.Format},
+		{"LOG_DIR", &cfg.Director},
+		{"LOG_IN_CONSOLE", &cfg.LogInConsole},
+		{"LOG_RETENTION_DAYS", &cfg.RetentionDay},
+	}
+	for _, v := range vars {
+		raw, ok := os.LookupEnv(v.name)
+		if !ok {
+			continue
+		}
+		if err := setFromEnv(reflect.ValueOf(v.field).Elem(), raw); err != nil {
+			return fmt.Errorf("invalid value for %s: %v", v.name, err)
+		}
+	}
+	return InitLoggerFromConfig(&cfg)
+}
@@ -0,0 +1,185 @@
+package logger
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestLoadZapConfigFromEnvDefaults(t *testing.T) {
+	cfg, err := LoadZapConfigFromEnv("TESTLOG")
+	if err != nil {
+		t.Fatalf("LoadZapConfigFromEnv: %v", err)
+	}
+	if want := getDefaultConfig(); !reflect.DeepEqual(*cfg, want) {
+		t.Errorf("cfg = %+v, want the defaults %+v", *cfg, want)
+	}
+}
+
+func TestLoadZapConfigFromEnvOverrides(t *testing.T) {
+	t.Setenv("TESTLOG_LEVEL", "debug")
+	t.Setenv("TESTLOG_DIRECTOR", "/var/log/app")
+	t.Setenv("TESTLOG_FORMAT", "console")
+	t.Setenv("TESTLOG_LOG_IN_CONSOLE", "false")
+	t.Setenv("TESTLOG_RETENTION_DAY", "30")
+	t.Setenv("TESTLOG_FIELD_ORDER", "trace_id, msg,,level")
+	t.Setenv("TESTLOG_NOT_A_FIELD", "ignored")
+
+	cfg, err := LoadZapConfigFromEnv("testlog")
+	if err != nil {
+		t.Fatalf("LoadZapConfigFromEnv: %v", err)
+	}
+	if cfg.Level != "debug" || cfg.Director != "/var/log/app" || cfg.Format != "console" {
+		t.Errorf("string fields = %q %q %q", cfg.Level, cfg.Director, cfg.Format)
+	}
+	if cfg.LogInConsole || cfg.RetentionDay != 30 {
+		t.Errorf("LogInConsole = %v, RetentionDay = %d", cfg.LogInConsole, cfg.RetentionDay)
+	}
+	if want := []string{"trace_id", "msg", "level"}; !reflect.DeepEqual(cfg.FieldOrder, want) {
+		t.Errorf("FieldOrder = %q, want %q", cfg.FieldOrder, want)
+	}
+	// fields without a variable keep their defaults
+	if def := getDefaultConfig(); cfg.Prefix != def.Prefix || cfg.EncodeLevel != def.EncodeLevel {
+		t.Errorf("defaults not kept: %+v", cfg)
+	}
+}
+
+func TestLoadZapConfigFromEnvInvalid(t *testing.T) {
+	tests := []struct{ name, value string }{
+		{"TESTLOG_LOG_IN_CONSOLE", "sometimes"},
+		{"TESTLOG_RETENTION_DAY", "week"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+			if _, err := LoadZapConfigFromEnv("TESTLOG"); err == nil {
+				t.Errorf("%s=%q accepted", tt.name, tt.value)
+			}
+		})
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	tests := []struct{ prefix, tag, want string }{
+		{"LOG", "level", "LOG_LEVEL"},
+		{"log", "log-in-console", "LOG_LOG_IN_CONSOLE"},
+		{"", "stacktrace-key", "STACKTRACE_KEY"},
+	}
+	for _, tt := range tests {
+		if got := envName(tt.prefix, tt.tag); got != tt.want {
+			t.Errorf("envName(%q, %q) = %q, want %q", tt.prefix, tt.tag, got, tt.want)
+		}
+	}
+}
+
+// initFromEnv runs InitLoggerFromEnv with LOG_DIR pointing at a temp dir unless the test set
+// it, and returns the config the global logger was built from
+func initFromEnv(t *testing.T) (*ZapConfig, error) {
+	t.Helper()
+	restoreGlobals(t)
+	t.Cleanup(RemoveInitHooks)
+	if _, ok := os.LookupEnv("LOG_DIR"); !ok {
+		t.Setenv("LOG_DIR", t.TempDir())
+	}
+	var got *ZapConfig
+	RegisterInitHook(func(_ *zap.Logger, cfg *ZapConfig) {
+		c := *cfg
+		got = &c
+	})
+	var err error
+	captureStdout(t, func() { err = InitLoggerFromEnv() })
+	return got, err
+}
+
+func TestInitLoggerFromEnvVariables(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name, value string
+		check       func(cfg *ZapConfig) bool
+	}{
+		{"LOG_LEVEL", "debug", func(cfg *ZapConfig) bool { return cfg.Level == "debug" && GetLevel() == "debug" }},
+		{"LOG_FORMAT", "json", func(cfg *ZapConfig) bool { return cfg.Format == "json" }},
+		{"LOG_DIR", dir, func(cfg *ZapConfig) bool { return cfg.Director == dir }},
+		{"LOG_IN_CONSOLE", "false", func(cfg *ZapConfig) bool { return !cfg.LogInConsole }},
+		{"LOG_RETENTION_DAYS", "14", func(cfg *ZapConfig) bool { return cfg.RetentionDay == 14 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+			cfg, err := initFromEnv(t)
+			if err != nil {
+				t.Fatalf("InitLoggerFromEnv: %v", err)
+			}
+			if !tt.check(cfg) {
+				t.Errorf("%s=%q not applied: %+v", tt.name, tt.value, cfg)
+			}
+		})
+	}
+}
+
+func TestInitLoggerFromEnvDefaults(t *testing.T) {
+	for _, name := range []string{"LOG_LEVEL", "LOG_FORMAT", "LOG_IN_CONSOLE", "LOG_RETENTION_DAYS"} {
+		// t.Setenv restores the variable afterwards; unset it for the duration of the test
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+	cfg, err := initFromEnv(t)
+	if err != nil {
+		t.Fatalf("InitLoggerFromEnv: %v", err)
+	}
+	want := getDefaultConfig()
+	want.Director = cfg.Director
+	if !reflect.DeepEqual(*cfg, want) {
+		t.Errorf("cfg = %+v, want the defaults %+v", *cfg, want)
+	}
+}
+
+func TestInitLoggerFromEnvWritesJSON(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("LOG_DIR", dir)
+	t.Setenv("LOG_FORMAT", "json")
+	t.Setenv("LOG_IN_CONSOLE", "false")
+	cfg, err := initFromEnv(t)
+	if err != nil {
+		t.Fatalf("InitLoggerFromEnv: %v", err)
+	}
+	Logger.Info("from env")
+	Logger.Sync()
+
+	data, err := os.ReadFile(filepath.Join(dir, logFileName(cfg, "info", time.Now())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := strings.TrimSpace(string(data))
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &entry); err != nil || !strings.Contains(line, "from env") {
+		t.Errorf("info log = %q, want a JSON entry (%v)", line, err)
+	}
+}
+
+func TestInitLoggerFromEnvInvalid(t *testing.T) {
+	tests := []struct{ name, value string }{
+		{"LOG_IN_CONSOLE", "sometimes"},
+		{"LOG_RETENTION_DAYS", "week"},
+		{"LOG_LEVEL", "verbose"},
+		{"LOG_FORMAT", "xml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+			original := Logger
+			if _, err := initFromEnv(t); err == nil {
+				t.Errorf("%s=%q accepted", tt.name, tt.value)
+			}
+			if Logger != original {
+				t.Error("global Logger replaced despite the error")
+			}
+		})
+	}
+}
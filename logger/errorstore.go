@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"github.com/kobyt2/common-services/utils/errorstore"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithErrorStore returns a zap.Option that additionally persists every Error-or-above entry,
+// together with its fields, to store. Apply it with Logger.WithOptions.
+func WithErrorStore(store *errorstore.ErrorStore) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, &errorStoreCore{store: store})
+	})
+}
+
+// errorStoreCore is a zapcore.Core that writes entries into an ErrorStore
+type errorStoreCore struct {
+	store  *errorstore.ErrorStore
+	fields []zapcore.Field
+}
+
+func (c *errorStoreCore) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.ErrorLevel
+}
+
+func (c *errorStoreCore) With(fields []zapcore.Field) zapcore.Core {
+	merged := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	merged = append(merged, c.fields...)
+	merged = append(merged, fields...)
+	return &errorStoreCore{store: c.store, fields: merged}
+}
+
+func (c *errorStoreCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *errorStoreCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	all = append(all, c.fields...)
+	all = append(all, fields...)
+	c.store.CaptureEntry(ent, all)
+	return nil
+}
+
+func (c *errorStoreCore) Sync() error {
+	return nil
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/kobyt2/common-services/utils/errorstore"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestWithErrorStore(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormlogger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	defer sqlDB.Close()
+	store, err := errorstore.New(db)
+	if err != nil {
+		t.Fatalf("errorstore.New: %v", err)
+	}
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := zap.New(core).WithOptions(WithErrorStore(store)).With(zap.String("service", "billing"))
+	l.Warn("not persisted")
+	l.Error("charge failed", zap.String("order_id", "o-7"))
+
+	if logs.Len() != 2 {
+		t.Errorf("base core got %d entries, want 2", logs.Len())
+	}
+	records, err := store.List(errorstore.ErrorFilter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("stored %d records, want 1", len(records))
+	}
+	r := records[0]
+	if r.Message != "charge failed" || r.Fields["service"] != "billing" || r.Fields["order_id"] != "o-7" {
+		t.Errorf("record = %q %v", r.Message, r.Fields)
+	}
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"expvar"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	expvarOnce    sync.Once
+	expvarEnabled atomic.Bool
+	// entryCounts counts entries per level once RegisterExpvars has been called
+	entryCounts = new(expvar.Map).Init()
+	// droppedEntries counts entries discarded by asynchronous writers such as WebhookSyncer
+	droppedEntries atomic.Int64
+	// lastSync holds the time of the last Logger.Sync as Unix nanoseconds
+	lastSync atomic.Int64
+)
+
+// RegisterExpvars publishes logger statistics under the "logger" expvar map: level (the level
+// set by SetLevel or the configuration), entries_total (entries per level), drop_count (entries
+// dropped by asynchronous writers) and last_sync (time of the last Sync). Counting is added to
+// the cores of every logger built afterwards by InitLogger, NewWithConfig and the other init
+// functions; an already installed Logger is left as is, so call it before initializing the
+// logger. Calling it again has no effect.
+func RegisterExpvars() {
+	expvarOnce.Do(func() {
+		vars := expvar.NewMap("logger")
+		vars.Set("level", expvar.Func(func() interface{} {
+			return GetLevel()
+		}))
+		vars.Set("entries_total", entryCounts)
+		vars.Set("drop_count", expvar.Func(func() interface{} {
+			return droppedEntries.Load()
+		}))
+		vars.Set("last_sync", expvar.Func(func() interface{} {
+			ns := lastSync.Load()
+			if ns == 0 {
+				return ""
+			}
+			return time.Unix(0, ns).Format(time.RFC3339Nano)
+		}))
+
+		expvarEnabled.Store(true)
+	})
+}
+
+// withExpvarCore tees core with a statsCore, or returns core unchanged before RegisterExpvars
+func withExpvarCore(core zapcore.Core) zapcore.Core {
+	if !expvarEnabled.Load() {
+		return core
+	}
+	return zapcore.NewTee(core, &statsCore{LevelEnabler: core})
+}
+
+// statsCore counts the entries enabled by the core it is teed with and records Sync times
+type statsCore struct {
+	zapcore.LevelEnabler
+}
+
+func (c *statsCore) With([]zapcore.Field) zapcore.Core {
+	return c
+}
+
+func (c *statsCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *statsCore) Write(ent zapcore.Entry, _ []zapcore.Field) error {
+	entryCounts.Add(ent.Level.String(), 1)
+	return nil
+}
+
+func (c *statsCore) Sync() error {
+	lastSync.Store(time.Now().UnixNano())
+	return nil
+}
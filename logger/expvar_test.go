@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"expvar"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// expvarCount returns logger.entries_total for level, or 0 if nothing was counted yet
+func expvarCount(t *testing.T, level string) int64 {
+	t.Helper()
+	vars, ok := expvar.Get("logger").(*expvar.Map)
+	if !ok {
+		t.Fatal(`expvar "logger" is not published`)
+	}
+	entries, ok := vars.Get("entries_total").(*expvar.Map)
+	if !ok {
+		t.Fatalf("entries_total = %v", vars.Get("entries_total"))
+	}
+	n, _ := entries.Get(level).(*expvar.Int)
+	if n == nil {
+		return 0
+	}
+	return n.Value()
+}
+
+func TestRegisterExpvarsCountsEntries(t *testing.T) {
+	restoreGlobals(t)
+	RegisterExpvars()
+	RegisterExpvars() // idempotent, must not panic on a duplicate expvar name
+
+	cfg := testConfig(t)
+	cfg.Level = "info"
+	l, err := NewWithConfig(cfg, WithWriteSyncer(&countingSyncer{}))
+	if err != nil {
+		t.Fatalf("NewWithConfig: %v", err)
+	}
+	before := map[string]int64{}
+	for _, level := range []string{"debug", "info", "warn", "error"} {
+		before[level] = expvarCount(t, level)
+	}
+
+	l.Debug("filtered")
+	l.Info("one")
+	l.Info("two")
+	l.Info("three")
+	l.Warn("careful")
+	l.Error("failed")
+
+	want := map[string]int64{"debug": 0, "info": 3, "warn": 1, "error": 1}
+	for level, n := range want {
+		if got := expvarCount(t, level) - before[level]; got != n {
+			t.Errorf("entries_total[%s] increased by %d, want %d", level, got, n)
+		}
+	}
+}
+
+func TestRegisterExpvarsLevelAndLastSync(t *testing.T) {
+	restoreGlobals(t)
+	RegisterExpvars()
+	l, err := NewWithConfig(testConfig(t), WithWriteSyncer(&countingSyncer{}))
+	if err != nil {
+		t.Fatalf("NewWithConfig: %v", err)
+	}
+	vars := expvar.Get("logger").(*expvar.Map)
+
+	if err := SetLevel("warn"); err != nil {
+		t.Fatal(err)
+	}
+	if got := vars.Get("level").String(); got != `"warn"` {
+		t.Errorf("level = %s, want \"warn\"", got)
+	}
+
+	start := time.Now()
+	if err := l.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	synced, err := time.Parse(time.RFC3339Nano, vars.Get("last_sync").(expvar.Func)().(string))
+	if err != nil {
+		t.Fatalf("last_sync: %v", err)
+	}
+	if synced.Before(start) {
+		t.Errorf("last_sync = %v, before Sync at %v", synced, start)
+	}
+	if _, ok := vars.Get("drop_count").(expvar.Func)().(int64); !ok {
+		t.Errorf("drop_count = %v, want an int64", vars.Get("drop_count"))
+	}
+}
+
+func TestRegisterExpvarsCountsGlobalLoggerInitializedAfterwards(t *testing.T) {
+	restoreGlobals(t)
+	ObserveGlobal(t, zapcore.DebugLevel)
+	installed := Logger
+	RegisterExpvars()
+	// the installed logger is not rewrapped behind the back of concurrent callers
+	if Logger != installed {
+		t.Fatal("RegisterExpvars replaced the installed global Logger")
+	}
+
+	cfg := testConfig(t)
+	cfg.Level = "info"
+	if err := InitLoggerFromConfig(&cfg); err != nil {
+		t.Fatalf("InitLoggerFromConfig: %v", err)
+	}
+	before := expvarCount(t, "warn")
+	Warn("counted")
+	Named("expvar").Warn("counted through a named logger")
+	if got := expvarCount(t, "warn") - before; got != 2 {
+		t.Errorf("entries_total[warn] increased by %d, want 2", got)
+	}
+}
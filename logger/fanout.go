@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+// FanOutError reports the failure of one writer of a FanOutWriter; Writer is its index in the
+// arguments to FanOutWriter
+type FanOutError struct {
+	Writer int
+	Err    error
+}
+
+func (e *FanOutError) Error() string {
+	return fmt.Sprintf("logger: fan-out writer %d: %v", e.Writer, e.Err)
+}
+
+func (e *FanOutError) Unwrap() error { return e.Err }
+
+// fanOutOp is either an entry to write or, when sync is set, a request to sync the writer
+type fanOutOp struct {
+	p    []byte
+	sync bool
+}
+
+type fanOutResult struct {
+	n   int
+	err error
+}
+
+// fanOutWorker owns one writer; its goroutine runs for the lifetime of the fan-out so that an
+// entry costs a channel round trip per writer rather than a new goroutine
+type fanOutWorker struct {
+	w       zapcore.WriteSyncer
+	ops     chan fanOutOp
+	results chan fanOutResult
+}
+
+func (w *fanOutWorker) loop(done *sync.WaitGroup) {
+	defer done.Done()
+	for op := range w.ops {
+		w.results <- w.do(op)
+	}
+}
+
+func (w *fanOutWorker) do(op fanOutOp) fanOutResult {
+	if op.sync {
+		return fanOutResult{err: w.w.Sync()}
+	}
+	n, err := w.w.Write(op.p)
+	if err == nil && n < len(op.p) {
+		err = io.ErrShortWrite
+	}
+	return fanOutResult{n: n, err: err}
+}
+
+// fanOutWriter hands every entry to all of its workers and waits for them to finish.
+// mu serialises Write and Sync calls, so each writer sees entries in the same order.
+type fanOutWriter struct {
+	mu      sync.Mutex
+	closed  bool
+	workers []*fanOutWorker
+	done    sync.WaitGroup
+}
+
+// FanOutWriter returns a WriteSyncer that writes each entry to all writers concurrently and
+// combines their errors as *FanOutError values; a failing writer does not prevent the others from
+// receiving the entry. Unlike zapcore.NewTee, the entry is encoded only once, so use it when
+// all sinks share the same encoder. Each writer gets a long-lived goroutine; the returned
+// writer also implements io.Closer, which stops them and syncs the writers.
+func FanOutWriter(writers ...zapcore.WriteSyncer) zapcore.WriteSyncer {
+	f := &fanOutWriter{workers: make([]*fanOutWorker, len(writers))}
+	f.done.Add(len(writers))
+	for i, w := range writers {
+		worker := &fanOutWorker{
+			w:       w,
+			ops:     make(chan fanOutOp, 1),
+			results: make(chan fanOutResult, 1),
+		}
+		f.workers[i] = worker
+		go worker.loop(&f.done)
+	}
+	return f
+}
+
+// Write returns len(p) only if every writer accepted all of p; otherwise it returns the
+// shortest write together with the combined errors
+func (f *fanOutWriter) Write(p []byte) (int, error) {
+	written := len(p)
+	var errs error
+	for i, r := range f.run(fanOutOp{p: p}) {
+		if r.err != nil {
+			errs = multierr.Append(errs, &FanOutError{Writer: i, Err: r.err})
+			if r.n < written {
+				written = r.n
+			}
+		}
+	}
+	return written, errs
+}
+
+// Sync syncs all writers concurrently and combines their errors
+func (f *fanOutWriter) Sync() error {
+	var errs error
+	for i, r := range f.run(fanOutOp{sync: true}) {
+		if r.err != nil {
+			errs = multierr.Append(errs, &FanOutError{Writer: i, Err: r.err})
+		}
+	}
+	return errs
+}
+
+// Close stops the worker goroutines and syncs the writers; later writes go to the writers one
+// after another from the caller's goroutine
+func (f *fanOutWriter) Close() error {
+	f.mu.Lock()
+	if !f.closed {
+		f.closed = true
+		for _, w := range f.workers {
+			close(w.ops)
+		}
+	}
+	f.mu.Unlock()
+	f.done.Wait()
+	return f.Sync()
+}
+
+// run applies op to every writer and returns the results in writer order. The caller's p is
+// only used until run returns, as zap reuses the buffer after Write.
+func (f *fanOutWriter) run(op fanOutOp) []fanOutResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	results := make([]fanOutResult, len(f.workers))
+	if f.closed {
+		for i, w := range f.workers {
+			results[i] = w.do(op)
+		}
+		return results
+	}
+	for _, w := range f.workers {
+		w.ops <- op
+	}
+	for i, w := range f.workers {
+		results[i] = <-w.results
+	}
+	return results
+}
@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestFanOutWriterStream(t *testing.T) {
+	var a, b, c bytes.Buffer
+	fanout := FanOutWriter(zapcore.AddSync(&a), zapcore.AddSync(&b), zapcore.AddSync(&c))
+	defer fanout.(io.Closer).Close()
+	l := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), fanout, zapcore.DebugLevel))
+	for i := 0; i < 1000; i++ {
+		l.Info("entry", zap.Int("i", i))
+	}
+	if err := l.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	if got := bytes.Count(a.Bytes(), []byte("\n")); got != 1000 {
+		t.Fatalf("first writer got %d lines, want 1000", got)
+	}
+	if !bytes.Equal(a.Bytes(), b.Bytes()) || !bytes.Equal(a.Bytes(), c.Bytes()) {
+		t.Fatal("writers received different bytes")
+	}
+}
+
+// failingSyncer accepts only the first n bytes of each write and fails its Sync
+type failingSyncer struct {
+	n   int
+	err error
+}
+
+func (f failingSyncer) Write(p []byte) (int, error) {
+	if f.n >= len(p) {
+		return len(p), nil
+	}
+	return f.n, f.err
+}
+
+func (f failingSyncer) Sync() error { return f.err }
+
+func TestFanOutWriterErrors(t *testing.T) {
+	errDisk := errors.New("disk full")
+	var ok bytes.Buffer
+	fanout := FanOutWriter(zapcore.AddSync(&ok), failingSyncer{n: 3, err: errDisk})
+	defer fanout.(io.Closer).Close()
+
+	n, err := fanout.Write([]byte("hello world"))
+	if !errors.Is(err, errDisk) || n != 3 {
+		t.Errorf("Write = %d, %v; want 3, disk full", n, err)
+	}
+	var writerErr *FanOutError
+	if !errors.As(err, &writerErr) || writerErr.Writer != 1 {
+		t.Errorf("Write err = %v, want a FanOutError for writer 1", err)
+	}
+	if ok.String() != "hello world" {
+		t.Errorf("healthy writer got %q, want the full entry", ok.String())
+	}
+	if err := fanout.Sync(); !errors.Is(err, errDisk) {
+		t.Errorf("Sync err = %v, want disk full", err)
+	}
+
+	short := FanOutWriter(failingSyncer{n: 2}, zapcore.AddSync(&ok))
+	defer short.(io.Closer).Close()
+	if n, err := short.Write([]byte("abcd")); n != 2 || err == nil {
+		t.Errorf("short write = %d, %v; want 2 and an error", n, err)
+	}
+}
+
+func TestFanOutWriterReportsEachFailingWriter(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	var ok bytes.Buffer
+	fanout := FanOutWriter(failingSyncer{err: errA}, zapcore.AddSync(&ok), failingSyncer{err: errB})
+	defer fanout.(io.Closer).Close()
+
+	_, err := fanout.Write([]byte("entry"))
+	errs := multierr.Errors(err)
+	if len(errs) != 2 {
+		t.Fatalf("Write err = %v, want two writer errors", err)
+	}
+	for i, want := range []struct {
+		writer int
+		err    error
+	}{{0, errA}, {2, errB}} {
+		var writerErr *FanOutError
+		if !errors.As(errs[i], &writerErr) || writerErr.Writer != want.writer || !errors.Is(writerErr, want.err) {
+			t.Errorf("errs[%d] = %v, want writer %d: %v", i, errs[i], want.writer, want.err)
+		}
+	}
+}
+
+// barrierSyncer blocks each write until all writers sharing the barrier are inside Write, so
+// it only succeeds when the fan-out writes to them concurrently
+type barrierSyncer struct {
+	arrived *atomic.Int32
+	total   int32
+	all     chan struct{}
+}
+
+func (b barrierSyncer) Write(p []byte) (int, error) {
+	if b.arrived.Add(1) == b.total {
+		close(b.all)
+	}
+	select {
+	case <-b.all:
+		return len(p), nil
+	case <-time.After(time.Second):
+		return 0, errors.New("writes were not concurrent")
+	}
+}
+
+func (barrierSyncer) Sync() error { return nil }
+
+func TestFanOutWriterWritesConcurrently(t *testing.T) {
+	barrier := barrierSyncer{arrived: new(atomic.Int32), total: 3, all: make(chan struct{})}
+	fanout := FanOutWriter(barrier, barrier, barrier)
+	defer fanout.(io.Closer).Close()
+
+	if n, err := fanout.Write([]byte("entry")); n != 5 || err != nil {
+		t.Fatalf("Write = %d, %v; want 5, nil", n, err)
+	}
+}
+
+func TestFanOutWriterClose(t *testing.T) {
+	var a, b bytes.Buffer
+	fanout := FanOutWriter(zapcore.AddSync(&a), zapcore.AddSync(&b))
+	if _, err := fanout.Write([]byte("before ")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := fanout.(io.Closer).Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := fanout.(io.Closer).Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+
+	// after Close the entry still reaches every writer
+	if _, err := fanout.Write([]byte("after")); err != nil {
+		t.Fatalf("Write after Close: %v", err)
+	}
+	if a.String() != "before after" || b.String() != "before after" {
+		t.Errorf("writers got %q and %q", a.String(), b.String())
+	}
+}
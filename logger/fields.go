@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SafeAny behaves like zap.Any but never panics and never emits an unusable value for types
+// that cannot be serialized (channels, functions, unsafe pointers); those are logged as
+// "[unserializable: <type>]" instead.
+func SafeAny(key string, value interface{}) (field zap.Field) {
+	if value != nil {
+		switch reflect.TypeOf(value).Kind() {
+		case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+			return unserializable(key, value)
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			field = unserializable(key, value)
+		}
+	}()
+	return zap.Any(key, value)
+}
+
+// Err returns an "error" field, or zap.Skip when err is nil, including typed nil pointers
+func Err(err error) zap.Field {
+	if err == nil {
+		return zap.Skip()
+	}
+	if v := reflect.ValueOf(err); v.Kind() == reflect.Ptr && v.IsNil() {
+		return zap.Skip()
+	}
+	return zap.Error(err)
+}
+
+// Duration returns a field holding d in milliseconds as a float
+func Duration(key string, d time.Duration) zap.Field {
+	return zap.Float64(key, float64(d)/float64(time.Millisecond))
+}
+
+func unserializable(key string, value interface{}) zap.Field {
+	return zap.String(key, fmt.Sprintf("[unserializable: %T]", value))
+}
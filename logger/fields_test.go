@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+	"time"
+	"unsafe"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type myErr struct{}
+
+func (*myErr) Error() string { return "my error" }
+
+// encodeFields encodes fields with the JSON encoder of the default config
+func encodeFields(t *testing.T, fields ...zapcore.Field) map[string]interface{} {
+	t.Helper()
+	cfg := getDefaultConfig()
+	return encodeJSON(t, &cfg, zapcore.Entry{Message: "m"}, fields...)
+}
+
+func TestSafeAny(t *testing.T) {
+	x := 1
+	tests := []struct {
+		name  string
+		value interface{}
+		want  interface{}
+	}{
+		{"chan", make(chan int), "[unserializable: chan int]"},
+		{"func", func() {}, "[unserializable: func()]"},
+		{"unsafe pointer", unsafe.Pointer(&x), "[unserializable: unsafe.Pointer]"},
+		{"string", "ok", "ok"},
+		{"nil", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := encodeFields(t, SafeAny("v", tt.value))
+			if got := out["v"]; got != tt.want {
+				t.Errorf("v = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	out := encodeFields(t, SafeAny("m", map[string]int{"a": 1}))
+	if m, ok := out["m"].(map[string]interface{}); !ok || m["a"] == nil {
+		t.Errorf("map field = %v", out["m"])
+	}
+}
+
+func TestErr(t *testing.T) {
+	var typedNil *myErr
+	tests := []struct {
+		name    string
+		err     error
+		present bool
+	}{
+		{"nil", nil, false},
+		{"typed nil pointer", typedNil, false},
+		{"error", errors.New("boom"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := encodeFields(t, Err(tt.err))
+			if _, ok := out["error"]; ok != tt.present {
+				t.Errorf("error field present = %v, want %v (%v)", ok, tt.present, out)
+			}
+		})
+	}
+}
+
+func TestDuration(t *testing.T) {
+	out := encodeFields(t, Duration("elapsed", 1500*time.Microsecond))
+	if got := out["elapsed"].(interface{ String() string }).String(); got != "1.5" {
+		t.Errorf("elapsed = %s, want 1.5", got)
+	}
+}
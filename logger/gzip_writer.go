@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/natefinch/lumberjack"
+)
+
+// megabyte matches lumberjack's MaxSize unit
+const megabyte = 1024 * 1024
+
+// gzipOverhead bounds the bytes a flushed gzip stream adds on top of the input: header,
+// sync marker and trailer; stored blocks add 5 bytes per 64KB
+const gzipOverhead = 64
+
+// GzipWriter compresses log data as it is written to a lumberjack.Logger. Each write is
+// flushed so the file can be decompressed up to the last entry at any time. Every file holds
+// its own gzip stream: the writer tracks the bytes written to the current file and rotates it
+// itself before lumberjack would, finishing the stream first, so lumberjack never switches
+// files underneath it. Use it with Compress disabled on the lumberjack.Logger.
+type GzipWriter struct {
+	mu      sync.Mutex
+	lj      *lumberjack.Logger
+	out     rotatingFile
+	buf     bytes.Buffer
+	gz      *gzip.Writer
+	written int64
+	started bool
+}
+
+// rotatingFile is the part of lumberjack.Logger GzipWriter writes through; syncedFile
+// implements it as well
+type rotatingFile interface {
+	io.Writer
+	Rotate() error
+	Close() error
+}
+
+// NewGzipWriter wraps l
+func NewGzipWriter(l *lumberjack.Logger) *GzipWriter {
+	return newGzipWriter(l, l)
+}
+
+// newGzipWriter writes the compressed output of l through out
+func newGzipWriter(l *lumberjack.Logger, out rotatingFile) *GzipWriter {
+	w := &GzipWriter{lj: l, out: out}
+	w.gz = gzip.NewWriter(&w.buf)
+	return w
+}
+
+// Write compresses p and writes the flushed output to the current file in a single call
+func (w *GzipWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.started {
+		// lumberjack appends to an existing file on its first write, so count what is there
+		w.buf.Reset()
+		w.gz.Reset(&w.buf)
+		w.written = w.fileSize()
+		w.started = true
+	}
+
+	if w.written > 0 && w.written+int64(len(p)+len(p)/(64*1024)*5+gzipOverhead) > lumberjackMaxBytes(w.lj) {
+		if err := w.finish(); err != nil {
+			return 0, err
+		}
+		if err := w.out.Rotate(); err != nil {
+			return 0, err
+		}
+		w.written = 0
+	}
+
+	if _, err := w.gz.Write(p); err != nil {
+		return 0, err
+	}
+	if err := w.gz.Flush(); err != nil {
+		return 0, err
+	}
+	if err := w.flushBuffer(); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Sync is a no-op; every Write already flushes the gzip stream
+func (w *GzipWriter) Sync() error {
+	return nil
+}
+
+// Close finishes the gzip stream of the current file and closes it
+func (w *GzipWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.started {
+		if err := w.finish(); err != nil {
+			return err
+		}
+		w.started = false
+	}
+	return w.out.Close()
+}
+
+// finish writes the gzip trailer to the current file and prepares a new stream
+func (w *GzipWriter) finish() error {
+	if err := w.gz.Close(); err != nil {
+		return err
+	}
+	if err := w.flushBuffer(); err != nil {
+		return err
+	}
+	w.gz.Reset(&w.buf)
+	return nil
+}
+
+// flushBuffer writes the compressed bytes to lumberjack
+func (w *GzipWriter) flushBuffer() error {
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	n, err := w.out.Write(w.buf.Bytes())
+	w.written += int64(n)
+	w.buf.Reset()
+	return err
+}
+
+// fileSize returns the size of the lumberjack file, 0 if it does not exist yet
+func (w *GzipWriter) fileSize() int64 {
+	info, err := os.Stat(w.lj.Filename)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// lumberjackMaxBytes returns the size at which l rotates, including lumberjack's default of
+// 100 MB when MaxSize is unset
+func lumberjackMaxBytes(l *lumberjack.Logger) int64 {
+	if l.MaxSize == 0 {
+		return 100 * megabyte
+	}
+	return int64(l.MaxSize) * megabyte
+}
@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/natefinch/lumberjack"
+)
+
+// gunzipFile decompresses path; a missing trailer is accepted since the current file is still open
+func gunzipFile(t *testing.T, path string, wantComplete bool) []byte {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil && (wantComplete || !errors.Is(err, io.ErrUnexpectedEOF)) {
+		t.Fatalf("%s: %v", path, err)
+	}
+	return data
+}
+
+func TestGzipWriterFlushesEveryWrite(t *testing.T) {
+	lj := &lumberjack.Logger{Filename: filepath.Join(t.TempDir(), "info.log.gz")}
+	w := NewGzipWriter(lj)
+
+	var want strings.Builder
+	for _, line := range []string{"first entry\n", "second entry\n", "third entry\n"} {
+		if _, err := w.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+		want.WriteString(line)
+		// every entry written so far can be read back while the file is open
+		if got := gunzipFile(t, lj.Filename, false); string(got) != want.String() {
+			t.Fatalf("decompressed = %q, want %q", got, want.String())
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := gunzipFile(t, lj.Filename, true); string(got) != want.String() {
+		t.Errorf("after Close decompressed = %q", got)
+	}
+
+	// a new writer appends a second gzip member to the existing file
+	w = NewGzipWriter(lj)
+	w.Write([]byte("after restart\n"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := gunzipFile(t, lj.Filename, true); string(got) != want.String()+"after restart\n" {
+		t.Errorf("after restart decompressed = %q", got)
+	}
+}
+
+func TestGzipWriterRotatesItself(t *testing.T) {
+	dir := t.TempDir()
+	lj := &lumberjack.Logger{Filename: filepath.Join(dir, "info.log.gz"), MaxSize: 1}
+	w := NewGzipWriter(lj)
+
+	// random data does not compress, so 2.8 MB of it needs at least three 1 MB files
+	var input bytes.Buffer
+	chunk := make([]byte, 400*1024)
+	for i := 0; i < 7; i++ {
+		rand.Read(chunk)
+		input.Write(chunk)
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatalf("write %d: %v", i, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "info*"))
+	if len(files) < 3 {
+		t.Fatalf("files = %v, want the writer to have rotated", files)
+	}
+	// backups are named info.log-<timestamp>.gz, so sorted names are in write order with the
+	// current file last
+	var output bytes.Buffer
+	for _, path := range files {
+		info, _ := os.Stat(path)
+		if info.Size() > megabyte {
+			t.Errorf("%s is %d bytes, larger than MaxSize", path, info.Size())
+		}
+		output.Write(gunzipFile(t, path, true))
+	}
+	if !bytes.Equal(output.Bytes(), input.Bytes()) {
+		t.Errorf("decompressed %d bytes, want the %d bytes written", output.Len(), input.Len())
+	}
+}
+
+func TestGzipWriterWithWALSync(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.CompressInPlace = true
+	cfg.WALSync = true
+	ws, err := getLogWriter(&cfg, "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws.Write([]byte("durable entry\n"))
+	if err := ws.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := filepath.Glob(filepath.Join(cfg.Director, "info_*.log.gz"))
+	if len(files) != 1 {
+		t.Fatalf("files = %v, want one", files)
+	}
+	if got := gunzipFile(t, files[0], false); string(got) != "durable entry\n" {
+		t.Errorf("decompressed = %q", got)
+	}
+}
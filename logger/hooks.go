@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// initHooks are called, in registration order, every time the global Logger is initialized
+var initHooks struct {
+	sync.Mutex
+	hooks []func(*zap.Logger, *ZapConfig)
+}
+
+// RegisterInitHook registers hook to be called after each InitLogger variant (and the lazy
+// default initialization) has assigned the global Logger. Integrations such as Sentry or
+// OpenTelemetry use it to get hold of the final logger and config.
+func RegisterInitHook(hook func(*zap.Logger, *ZapConfig)) {
+	initHooks.Lock()
+	defer initHooks.Unlock()
+	initHooks.hooks = append(initHooks.hooks, hook)
+}
+
+// RemoveInitHooks removes all registered init hooks
+func RemoveInitHooks() {
+	initHooks.Lock()
+	defer initHooks.Unlock()
+	initHooks.hooks = nil
+}
+
+// runInitHooks calls the registered hooks; the lock is not held while they run, so a hook
+// may register further hooks
+func runInitHooks(l *zap.Logger, cfg *ZapConfig) {
+	initHooks.Lock()
+	hooks := make([]func(*zap.Logger, *ZapConfig), len(initHooks.hooks))
+	copy(hooks, initHooks.hooks)
+	initHooks.Unlock()
+	for _, hook := range hooks {
+		hook(l, cfg)
+	}
+}
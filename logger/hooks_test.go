@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestInitHooksRunInOrder(t *testing.T) {
+	restoreGlobals(t)
+	t.Cleanup(RemoveInitHooks)
+	dir := t.TempDir()
+	writeEnvConfig(t, dir, "app.yaml", "warn")
+
+	type call struct {
+		name   string
+		logger *zap.Logger
+		global *zap.Logger
+		level  string
+	}
+	var calls []call
+	hook := func(name string) func(*zap.Logger, *ZapConfig) {
+		return func(l *zap.Logger, cfg *ZapConfig) {
+			calls = append(calls, call{name, l, Logger, cfg.Level})
+		}
+	}
+	RegisterInitHook(hook("first"))
+	RegisterInitHook(hook("second"))
+
+	if err := InitLogger(filepath.Join(dir, "app.yaml")); err != nil {
+		t.Fatalf("InitLogger: %v", err)
+	}
+	if len(calls) != 2 || calls[0].name != "first" || calls[1].name != "second" {
+		t.Fatalf("calls = %+v, want first then second", calls)
+	}
+	for _, c := range calls {
+		if c.logger == nil || c.logger != Logger {
+			t.Errorf("%s hook got logger %p, want the global Logger %p", c.name, c.logger, Logger)
+		}
+		if c.global != c.logger {
+			t.Errorf("%s hook ran before the global Logger was assigned", c.name)
+		}
+		if c.level != "warn" {
+			t.Errorf("%s hook got level %q, want the loaded config", c.name, c.level)
+		}
+	}
+
+	RemoveInitHooks()
+	if err := InitLogger(filepath.Join(dir, "app.yaml")); err != nil {
+		t.Fatalf("InitLogger: %v", err)
+	}
+	if len(calls) != 2 {
+		t.Errorf("hooks ran after RemoveInitHooks: %+v", calls)
+	}
+}
+
+func TestInitHookMayRegisterHook(t *testing.T) {
+	restoreGlobals(t)
+	t.Cleanup(RemoveInitHooks)
+	var nested int
+	RegisterInitHook(func(*zap.Logger, *ZapConfig) {
+		RegisterInitHook(func(*zap.Logger, *ZapConfig) { nested++ })
+	})
+
+	cfg := testConfig(t)
+	if err := InitLoggerFromConfig(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if nested != 0 {
+		t.Errorf("hook registered during a run was called in the same run")
+	}
+	if err := InitLoggerFromConfig(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if nested != 1 {
+		t.Errorf("nested hook called %d times on the next init, want 1", nested)
+	}
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RequestIDHeader is the header HTTPMiddleware reads the request ID from and echoes it back in
+const RequestIDHeader = "X-Request-ID"
+
+// HTTPMiddlewareOption configures HTTPMiddleware
+type HTTPMiddlewareOption func(*httpMiddleware)
+
+// WithSkipPaths disables the access log for the given exact paths, e.g. health checks.
+// The request ID is still attached to the context of skipped requests.
+func WithSkipPaths(paths ...string) HTTPMiddlewareOption {
+	return func(m *httpMiddleware) {
+		for _, path := range paths {
+			m.skip[path] = struct{}{}
+		}
+	}
+}
+
+type httpMiddleware struct {
+	next http.Handler
+	skip map[string]struct{}
+}
+
+// HTTPMiddleware logs every request through the global Logger with method, path, status,
+// latency_ms, remote_addr and request_id. The request ID is taken from the X-Request-ID
+// header or generated, and the request context carries it both via SetRequestID and as a
+// field, so handlers can log with FromContext(r.Context()).
+func HTTPMiddleware(next http.Handler, opts ...HTTPMiddlewareOption) http.Handler {
+	m := &httpMiddleware{next: next, skip: make(map[string]struct{})}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+func (m *httpMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Header.Get(RequestIDHeader)
+	if requestID == "" {
+		requestID = uuid.NewString()
+	}
+	w.Header().Set(RequestIDHeader, requestID)
+
+	ctx := SetRequestID(r.Context(), requestID)
+	ctx = WithFields(ctx, zap.String("request_id", requestID))
+	r = r.WithContext(ctx)
+
+	if _, ok := m.skip[r.URL.Path]; ok {
+		m.next.ServeHTTP(w, r)
+		return
+	}
+
+	status, elapsed := serveAndMeasure(m.next, w, r)
+	directLogger().Info("http request",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+		zap.Int("status", status),
+		zap.Float64("latency_ms", elapsedMillis(elapsed)),
+		zap.String("remote_addr", r.RemoteAddr),
+		zap.String("request_id", requestID),
+	)
+}
@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	logs := ObserveGlobal(t, zapcore.DebugLevel)
+
+	var handlerRequestID string
+	h := HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerRequestID, _ = GetRequestID(r.Context())
+		FromContext(r.Context()).Info("handling")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(RequestIDHeader); got != "req-42" {
+		t.Errorf("response %s = %q, want req-42", RequestIDHeader, got)
+	}
+	if handlerRequestID != "req-42" {
+		t.Errorf("GetRequestID in handler = %q, want req-42", handlerRequestID)
+	}
+	// the child logger from the context carries the request ID
+	handling := logs.FilterMessage("handling").All()
+	if len(handling) != 1 || handling[0].ContextMap()["request_id"] != "req-42" {
+		t.Errorf("handler entries = %v", handling)
+	}
+
+	entries := logs.FilterMessage("http request").All()
+	if len(entries) != 1 {
+		t.Fatalf("%d \"http request\" entries, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["method"] != http.MethodPost || fields["path"] != "/orders" || fields["status"] != int64(http.StatusCreated) ||
+		fields["request_id"] != "req-42" || fields["remote_addr"] != req.RemoteAddr {
+		t.Errorf("access log fields = %v", fields)
+	}
+	if latency, ok := fields["latency_ms"].(float64); !ok || latency < 0 {
+		t.Errorf("latency_ms = %v", fields["latency_ms"])
+	}
+	if file := filepath.Base(entries[0].Caller.File); file != "http_middleware.go" {
+		t.Errorf("caller = %s, want http_middleware.go", entries[0].Caller.String())
+	}
+}
+
+func TestHTTPMiddlewareGeneratesRequestID(t *testing.T) {
+	logs := ObserveGlobal(t, zapcore.DebugLevel)
+
+	rec := httptest.NewRecorder()
+	HTTPMiddleware(http.NotFoundHandler()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	id := rec.Header().Get(RequestIDHeader)
+	if id == "" {
+		t.Fatal("no request ID generated")
+	}
+	entries := logs.FilterMessage("http request").All()
+	if len(entries) != 1 || entries[0].ContextMap()["request_id"] != id || entries[0].ContextMap()["status"] != int64(http.StatusNotFound) {
+		t.Errorf("entries = %v, want request_id %q and status 404", logs.All(), id)
+	}
+}
+
+func TestHTTPMiddlewareSkipPaths(t *testing.T) {
+	logs := ObserveGlobal(t, zapcore.DebugLevel)
+
+	var requestID string
+	h := HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID, _ = GetRequestID(r.Context())
+	}), WithSkipPaths("/healthz"))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if logs.FilterMessage("http request").Len() != 0 {
+		t.Errorf("skipped path was logged: %v", logs.All())
+	}
+	if requestID == "" {
+		t.Error("skipped request has no request ID in its context")
+	}
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// lazyInitOnce guards the fallback initialization done by EnsureInitialized
+var lazyInitOnce sync.Once
+
+// IsInitialized reports whether the global Logger and SugaredLogger have been set
+func IsInitialized() bool {
+	return Logger != nil && SugaredLogger != nil
+}
+
+// EnsureInitialized initializes the global logger with the default config if no InitLogger
+// variant has run yet. It is safe to call from multiple goroutines, and the package-level
+// log functions call it so that logging before initialization does not panic.
+func EnsureInitialized() {
+	// checking the globals before Do would race with the goroutine initializing them;
+	// Do itself is a single atomic load once it has run
+	lazyInitOnce.Do(func() {
+		if IsInitialized() {
+			return
+		}
+		if err := initWithDefaults(); err != nil {
+			// the default log directory is not writable; keep logging to stdout only
+			fmt.Printf("Failed to initialize default logger, logging to stdout: %v\n", err)
+			cfg := getDefaultConfig()
+			encoder := zapcore.NewConsoleEncoder(cfg.EncoderConfig())
+			Logger = zap.New(zapcore.NewCore(encoder, zapcore.Lock(os.Stdout), zapcore.InfoLevel), zap.AddCaller(), zap.AddCallerSkip(1))
+			SugaredLogger = Logger.Sugar()
+		}
+	})
+}
+
+// directLoggers pairs a global Logger with its variant without the caller skip
+type directLoggers struct {
+	base, direct *zap.Logger
+	sugared      *zap.SugaredLogger
+}
+
+// directCache holds the variant built for the current global Logger; it is rebuilt whenever
+// Logger is replaced
+var directCache atomic.Pointer[directLoggers]
+
+// directLogger returns the global Logger, initializing it if needed, without the caller skip
+// added for the package-level log functions. Use it when the entry is logged from inside this
+// package or through a wrapper that adds its own skip, so that the reported caller is correct.
+func directLogger() *zap.Logger {
+	return currentDirect().direct
+}
+
+// currentDirect returns the direct variants of the current global Logger
+func currentDirect() *directLoggers {
+	EnsureInitialized()
+	base := Logger
+	if c := directCache.Load(); c != nil && c.base == base {
+		return c
+	}
+	direct := base.WithOptions(zap.AddCallerSkip(-1))
+	c := &directLoggers{base: base, direct: direct, sugared: direct.Sugar()}
+	directCache.Store(c)
+	return c
+}
+
+// L returns the global Logger for packages that log through it directly rather than through
+// the package-level functions: the caller reported is the code calling L().Info and so on.
+// Call it at log time instead of keeping the result, so that a later InitLogger takes effect.
+func L() *zap.Logger {
+	return directLogger()
+}
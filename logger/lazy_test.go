@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// captureStdout redirects os.Stdout while fn runs and returns what was written
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+	defer func() { os.Stdout = stdout }()
+	fn()
+	os.Stdout = stdout
+	w.Close()
+	return <-done
+}
+
+// resetLazyInit makes the globals look uninitialized and runs the test from a temp dir,
+// since the default config logs to ./logs
+func resetLazyInit(t *testing.T) {
+	t.Helper()
+	restoreGlobals(t)
+	Logger, SugaredLogger = nil, nil
+	lazyInitOnce = sync.Once{}
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func TestInfoBeforeInitLogsToStdout(t *testing.T) {
+	resetLazyInit(t)
+	if IsInitialized() {
+		t.Fatal("IsInitialized = true after reset")
+	}
+
+	out := captureStdout(t, func() {
+		Info("hello")
+		Logger.Sync()
+	})
+	if !strings.Contains(out, "hello") {
+		t.Errorf("stdout = %q, want the entry", out)
+	}
+	if !IsInitialized() {
+		t.Error("IsInitialized = false after logging")
+	}
+}
+
+func TestEnsureInitializedConcurrent(t *testing.T) {
+	resetLazyInit(t)
+
+	captureStdout(t, func() {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				EnsureInitialized()
+			}()
+		}
+		wg.Wait()
+	})
+	if !IsInitialized() {
+		t.Fatal("IsInitialized = false after EnsureInitialized")
+	}
+}
+
+func TestEnsureInitializedKeepsExistingLogger(t *testing.T) {
+	ObserveGlobal(t, zapcore.DebugLevel)
+	before := Logger
+	EnsureInitialized()
+	if Logger != before {
+		t.Error("EnsureInitialized replaced an initialized logger")
+	}
+}
@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// atomicLevel is the minimum level shared by every file core and by the console core when
+// ConsoleLevel is empty. Each InitLogger variant resets it to the configured Level;
+// loggers built with NewWithConfig get a level of their own.
+var atomicLevel = zap.NewAtomicLevel()
+
+// SetLevel changes the minimum level of the global Logger at runtime; the change applies to
+// all cores immediately, without re-initializing the logger
+func SetLevel(level string) error {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	atomicLevel.SetLevel(lvl)
+	return nil
+}
+
+// GetLevel returns the current minimum level of the global Logger
+func GetLevel() string {
+	return atomicLevel.Level().String()
+}
+
+// configLevel returns cfg.Level, defaulting to Info when it is empty or invalid
+func configLevel(cfg *ZapConfig) zapcore.Level {
+	lvl, err := zapcore.ParseLevel(cfg.Level)
+	if err != nil || cfg.Level == "" {
+		return zapcore.InfoLevel
+	}
+	return lvl
+}
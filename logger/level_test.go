@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetLevelAppliesToGlobalLogger(t *testing.T) {
+	restoreGlobals(t)
+	cfg := testConfig(t)
+	cfg.Level = "info"
+	cfg.FilenameDateFormat = "static"
+	if err := InitLoggerFromConfig(&cfg); err != nil {
+		t.Fatalf("InitLoggerFromConfig: %v", err)
+	}
+	debugFile := filepath.Join(cfg.Director, logFileName(&cfg, "debug", time.Now()))
+	readDebug := func() string {
+		t.Helper()
+		if err := Logger.Sync(); err != nil {
+			t.Fatalf("Sync: %v", err)
+		}
+		data, err := os.ReadFile(debugFile)
+		if err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	if got := GetLevel(); got != "info" {
+		t.Fatalf("GetLevel = %q, want info", got)
+	}
+	Logger.Debug("before SetLevel")
+	if strings.Contains(readDebug(), "before SetLevel") {
+		t.Fatal("debug entry written at info level")
+	}
+
+	if err := SetLevel("debug"); err != nil {
+		t.Fatalf("SetLevel: %v", err)
+	}
+	if got := GetLevel(); got != "debug" {
+		t.Errorf("GetLevel = %q, want debug", got)
+	}
+	// the same Logger emits debug entries without being re-initialized
+	Logger.Debug("after SetLevel")
+	if !strings.Contains(readDebug(), "after SetLevel") {
+		t.Error("debug entry missing after SetLevel(\"debug\")")
+	}
+}
+
+func TestSetLevelInvalid(t *testing.T) {
+	restoreGlobals(t)
+	if err := SetLevel("warn"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetLevel("verbose"); err == nil {
+		t.Error("SetLevel(\"verbose\") returned nil")
+	}
+	if got := GetLevel(); got != "warn" {
+		t.Errorf("GetLevel after invalid SetLevel = %q, want warn", got)
+	}
+}
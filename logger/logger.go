@@ -2,20 +2,104 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"github.com/spf13/viper"
 	"github.com/natefinch/lumberjack"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
+	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 // GormLogger 定义一个 GORM 自定义日志结构体
 type GormLogger struct {
-	zapLogger *zap.Logger
-	config    logger.Config
+	zapLogger        *zap.Logger
+	config           logger.Config
+	errNotFoundLevel logger.LogLevel
+	structuredTrace  bool
+	slowAlert        *slowQueryAlert // 指针共享，LogMode 复制出的实例使用同一个计数
+}
+
+// SlowQueryAlertHook 连续慢查询告警回调，consecutiveCount 为连续慢查询次数，sql 为触发告警的语句
+type SlowQueryAlertHook func(consecutiveCount int, sql string)
+
+// slowQueryAlert 连续慢查询计数，遇到非慢查询时清零
+type slowQueryAlert struct {
+	mu                   sync.Mutex
+	consecutiveSlowCount int
+	threshold            int
+	hook                 SlowQueryAlertHook
+}
+
+// record 记录一次查询是否为慢查询，连续次数达到阈值时调用 hook（每轮连续慢查询只告警一次）
+func (a *slowQueryAlert) record(slow bool, sql string) {
+	a.mu.Lock()
+	if !slow {
+		a.consecutiveSlowCount = 0
+		a.mu.Unlock()
+		return
+	}
+	a.consecutiveSlowCount++
+	count := a.consecutiveSlowCount
+	a.mu.Unlock()
+	if count == a.threshold {
+		a.hook(count, sql)
+	}
+}
+
+// GormLoggerOption GormLogger 的可选配置
+type GormLoggerOption func(*GormLogger)
+
+// WithSlowThreshold 设置慢查询阈值，耗时达到 d 的查询按 Warn 记录，0 表示不检测慢查询
+func WithSlowThreshold(d time.Duration) GormLoggerOption {
+	return func(l *GormLogger) {
+		l.config.SlowThreshold = d
+	}
+}
+
+// WithLogLevel 设置 gorm 的日志级别，等同于构造后调用 LogMode
+func WithLogLevel(level logger.LogLevel) GormLoggerOption {
+	return func(l *GormLogger) {
+		l.config.LogLevel = level
+	}
+}
+
+// WithIgnoreNotFound 设置是否忽略 gorm.ErrRecordNotFound，忽略时按普通查询记录
+func WithIgnoreNotFound(ignore bool) GormLoggerOption {
+	return func(l *GormLogger) {
+		l.config.IgnoreRecordNotFoundError = ignore
+	}
+}
+
+// WithErrNotFoundLevel 设置 gorm.ErrRecordNotFound 的日志级别（未开启 IgnoreRecordNotFoundError 时生效）
+func WithErrNotFoundLevel(level logger.LogLevel) GormLoggerOption {
+	return func(l *GormLogger) {
+		l.errNotFoundLevel = level
+	}
+}
+
+// WithStructuredTrace 开启后 Trace 以独立的 zap 字段（sql、rows、elapsed、elapsed_ms、slow_query、error）记录，
+// 便于日志平台检索；默认关闭，保持原有的格式化字符串输出
+func WithStructuredTrace(enabled bool) GormLoggerOption {
+	return func(l *GormLogger) {
+		l.structuredTrace = enabled
+	}
+}
+
+// WithSlowQueryAlertHook 连续出现 alertThreshold 次慢查询时调用 hook，单次慢查询只记录 Warn 日志
+func WithSlowQueryAlertHook(alertThreshold int, hook SlowQueryAlertHook) GormLoggerOption {
+	return func(l *GormLogger) {
+		if hook == nil || alertThreshold <= 0 {
+			l.slowAlert = nil
+			return
+		}
+		l.slowAlert = &slowQueryAlert{threshold: alertThreshold, hook: hook}
+	}
 }
 
 // Define global variables
@@ -25,11 +109,11 @@ var (
 )
 
 // NewGormLogger 创建一个新的 GormLogger 实例
-func NewGormLogger(zapLogger *zap.Logger) GormLogger {
+func NewGormLogger(zapLogger *zap.Logger, opts ...GormLoggerOption) GormLogger {
     if zapLogger == nil {
         panic("zapLogger is nil")
     }
-    return GormLogger{
+    l := GormLogger{
         zapLogger: zapLogger,
         config: logger.Config{
             SlowThreshold:             200 * time.Millisecond, // 慢查询的阈值
@@ -37,7 +121,12 @@ func NewGormLogger(zapLogger *zap.Logger) GormLogger {
             IgnoreRecordNotFoundError: false,                  // 忽略没有找到记录的错误
             Colorful:                  false,                  // 禁用彩色打印
         },
+        errNotFoundLevel: logger.Warn, // 记录不存在属于正常业务情况，默认降级为 Warn
+    }
+    for _, opt := range opts {
+        opt(&l)
     }
+    return l
 }
 
 // LogMode 设置日志级别
@@ -50,53 +139,127 @@ func (l GormLogger) LogMode(level logger.LogLevel) logger.Interface {
 // Info 实现 gorm.Logger 的 Info 方法
 func (l GormLogger) Info(ctx context.Context, s string, i ...interface{}) {
 	if l.config.LogLevel >= logger.Info {
-		l.zapLogger.Sugar().Infof(s, i...)
+		l.loggerFor(ctx).Sugar().Infof(s, i...)
 	}
 }
 
 // Warn 实现 gorm.Logger 的 Warn 方法
 func (l GormLogger) Warn(ctx context.Context, s string, i ...interface{}) {
 	if l.config.LogLevel >= logger.Warn {
-		l.zapLogger.Sugar().Warnf(s, i...)
+		l.loggerFor(ctx).Sugar().Warnf(s, i...)
 	}
 }
 
 // Error 实现 gorm.Logger 的 Error 方法
 func (l GormLogger) Error(ctx context.Context, s string, i ...interface{}) {
 	if l.config.LogLevel >= logger.Error {
-		l.zapLogger.Sugar().Errorf(s, i...)
+		l.loggerFor(ctx).Sugar().Errorf(s, i...)
 	}
 }
 
+// since 计算 Trace 中的查询耗时，测试中替换为固定值以检验慢查询阈值
+var since = time.Since
+
 // Trace 实现 gorm.Logger 的 Trace 方法
 func (l GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
 	if l.config.LogLevel > 0 {
-		elapsed := time.Since(begin)
+		elapsed := since(begin)
 		sql, rows := fc()
-		if err != nil {
-			l.zapLogger.Sugar().Errorf("[%.3fms] [rows:%v] %s", float64(elapsed.Nanoseconds())/1e6, rows, sql)
-		} else if elapsed > l.config.SlowThreshold && l.config.SlowThreshold != 0 {
-			l.zapLogger.Sugar().Warnf("[%.3fms] [rows:%v] %s", float64(elapsed.Nanoseconds())/1e6, rows, sql)
-		} else {
-			l.zapLogger.Sugar().Debugf("[%.3fms] [rows:%v] %s", float64(elapsed.Nanoseconds())/1e6, rows, sql)
+		slow := elapsed >= l.config.SlowThreshold && l.config.SlowThreshold != 0
+		if l.slowAlert != nil {
+			l.slowAlert.record(slow, sql)
+		}
+
+		level := zapcore.DebugLevel
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// 记录不存在不视为错误：忽略时按普通查询记录，否则按配置的级别记录
+			if !l.config.IgnoreRecordNotFoundError {
+				var ok bool
+				if level, ok = zapLevel(l.errNotFoundLevel); !ok {
+					return
+				}
+			}
+		} else if err != nil {
+			level = zapcore.ErrorLevel
+		} else if slow {
+			level = zapcore.WarnLevel
+		}
+
+		if l.structuredTrace {
+			fields := []zap.Field{
+				zap.String("sql", sql),
+				zap.Int64("rows", rows),
+				zap.Duration("elapsed", elapsed),
+				zap.Float64("elapsed_ms", float64(elapsed.Nanoseconds())/1e6),
+				zap.Bool("slow_query", slow),
+			}
+			// 忽略的 ErrRecordNotFound 按普通查询记录，不附带 error 字段
+			if err != nil && !(l.config.IgnoreRecordNotFoundError && errors.Is(err, gorm.ErrRecordNotFound)) {
+				fields = append(fields, zap.Error(err))
+			}
+			if ce := l.loggerFor(ctx).Check(level, "gorm trace"); ce != nil {
+				ce.Write(fields...)
+			}
+			return
 		}
+		l.loggerFor(ctx).Sugar().Logf(level, "[%.3fms] [rows:%v] %s", float64(elapsed.Nanoseconds())/1e6, rows, sql)
+	}
+}
+
+// loggerFor 返回附加了 ctx 中字段（ContextWithFields / WithFields）的 zapLogger
+func (l GormLogger) loggerFor(ctx context.Context) *zap.Logger {
+	if ctx == nil {
+		return l.zapLogger
+	}
+	if fields := ExtractContextFields(ctx); len(fields) > 0 {
+		return l.zapLogger.With(fields...)
+	}
+	return l.zapLogger
+}
+
+// zapLevel 将 gorm 的日志级别转换为 zap 的日志级别，Silent 返回 false
+func zapLevel(level logger.LogLevel) (zapcore.Level, bool) {
+	switch level {
+	case logger.Error:
+		return zapcore.ErrorLevel, true
+	case logger.Warn:
+		return zapcore.WarnLevel, true
+	case logger.Info:
+		return zapcore.InfoLevel, true
+	default:
+		return zapcore.DebugLevel, false
 	}
 }
 
 // ZapConfig holds the configuration for the logger
 type ZapConfig struct {
-	Level              string `mapstructure:"level" json:"level" yaml:"level"`
-	Prefix             string `mapstructure:"prefix" json:"prefix" yaml:"prefix"`
-	Format             string `mapstructure:"format" json:"format" yaml:"format"`
-	Director           string `mapstructure:"director" json:"director" yaml:"director"`
-	EncodeLevel        string `mapstructure:"encode-level" json:"encode-level" yaml:"encode-level"`
-	StacktraceKey      string `mapstructure:"stacktrace-key" json:"stacktrace-key" yaml:"stacktrace-key"`
-	ShowLine           bool   `mapstructure:"show-line" json:"show-line" yaml:"show-line"`
-	LogInConsole       bool   `mapstructure:"log-in-console" json:"log-in-console" yaml:"log-in-console"`
-	RetentionDay       int    `mapstructure:"retention-day" json:"retention-day" yaml:"retention-day"`
-	CustomLevelEncoder bool   `mapstructure:"custom-level-encoder" json:"custom-level-encoder"` // New field
+	Level               string   `mapstructure:"level" json:"level" yaml:"level"`
+	Prefix              string   `mapstructure:"prefix" json:"prefix" yaml:"prefix"`
+	Format              string   `mapstructure:"format" json:"format" yaml:"format"`
+	Director            string   `mapstructure:"director" json:"director" yaml:"director"`
+	EncodeLevel         string   `mapstructure:"encode-level" json:"encode-level" yaml:"encode-level"`
+	StacktraceKey       string   `mapstructure:"stacktrace-key" json:"stacktrace-key" yaml:"stacktrace-key"`
+	ShowLine            bool     `mapstructure:"show-line" json:"show-line" yaml:"show-line"`
+	LogInConsole        bool     `mapstructure:"log-in-console" json:"log-in-console" yaml:"log-in-console"`
+	RetentionDay        int      `mapstructure:"retention-day" json:"retention-day" yaml:"retention-day"`
+	CustomLevelEncoder  bool     `mapstructure:"custom-level-encoder" json:"custom-level-encoder" yaml:"custom-level-encoder"` // New field
+	TimeFormat          string   `mapstructure:"time-format" json:"time-format" yaml:"time-format"` // Go layout or unix/unixnano/rfc3339/rfc3339nano
+	AutoSyncOnSignal    bool     `mapstructure:"auto-sync-on-signal" json:"auto-sync-on-signal" yaml:"auto-sync-on-signal"` // Sync on SIGINT/SIGTERM
+	MaxMessageLength    int      `mapstructure:"max-message-length" json:"max-message-length" yaml:"max-message-length"` // 0 = unlimited
+	ConsoleFormat       string   `mapstructure:"console-format" json:"console-format" yaml:"console-format"` // Stdout format, defaults to Format
+	ConsoleLevel        string   `mapstructure:"console-level" json:"console-level" yaml:"console-level"` // Stdout minimum level, defaults to Level
+	FieldNamingStrategy string   `mapstructure:"field-naming-strategy" json:"field-naming-strategy" yaml:"field-naming-strategy"` // original, snake_case or camelCase
+	WALSync             bool     `mapstructure:"wal-sync" json:"wal-sync" yaml:"wal-sync"` // fsync log files after writes
+	WALBatchSize        int      `mapstructure:"wal-batch-size" json:"wal-batch-size" yaml:"wal-batch-size"` // Writes per fsync when WALSync is set, default 1
+	CompressInPlace     bool     `mapstructure:"compress-in-place" json:"compress-in-place" yaml:"compress-in-place"` // gzip the current file while writing (.log.gz)
+	OmitEmpty           bool     `mapstructure:"omit-empty" json:"omit-empty" yaml:"omit-empty"` // Drop empty array/object fields
+	FieldOrder          []string `mapstructure:"field-order" json:"field-order" yaml:"field-order"` // Keys written first, in this order; the rest alphabetically
+	FilenameDateFormat  string   `mapstructure:"filename-date-format" json:"filename-date-format" yaml:"filename-date-format"` // hourly (default), daily or static
 }
 
+// defaultTimeFormat is the layout used when ZapConfig.TimeFormat is empty
+const defaultTimeFormat = "2006-01-02 15:04:05.000"
+
 
 // EncoderConfig returns the encoder configuration based on the ZapConfig
 func (c *ZapConfig) EncoderConfig() zapcore.EncoderConfig {
@@ -140,15 +303,34 @@ func (c *ZapConfig) LevelEncoder() zapcore.LevelEncoder {
 
 // TimeEncoder returns the time encoder based on the ZapConfig
 func (c *ZapConfig) TimeEncoder() zapcore.TimeEncoder {
-	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
-		enc.AppendString(t.Format("2006-01-02 15:04:05.000"))
+	switch c.TimeFormat {
+	case "unix":
+		return zapcore.EpochTimeEncoder
+	case "unixnano":
+		return zapcore.EpochNanosTimeEncoder
+	case "rfc3339":
+		return zapcore.RFC3339TimeEncoder
+	case "rfc3339nano":
+		return zapcore.RFC3339NanoTimeEncoder
 	}
+
+	layout := c.TimeFormat
+	if layout == "" {
+		layout = defaultTimeFormat
+	}
+	// TimeEncoderOfLayout lets encoders that support it append the time without allocating
+	return zapcore.TimeEncoderOfLayout(layout)
 }
 
 // CallerEncoder returns the caller encoder based on the ZapConfig
 func (c *ZapConfig) CallerEncoder() zapcore.CallerEncoder {
 	return func(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
-		enc.AppendString(fmt.Sprintf("%s", caller.TrimmedPath()))
+		// encoders that can append the path in place avoid the string TrimmedPath allocates
+		if ca, ok := enc.(trimmedCallerAppender); ok {
+			ca.appendTrimmedCaller(caller)
+			return
+		}
+		enc.AppendString(caller.TrimmedPath())
 	}
 }
 
@@ -159,17 +341,7 @@ func InitLogger(configFile string) error {
 	// 尝试读取配置文件
 	if err := viper.ReadInConfig(); err != nil {
 		fmt.Println("Config file not found, using default values.")
-		// 使用默认值
-		defaultConfig := getDefaultConfig()
-		cores, err := setupCores(&defaultConfig)
-		if err != nil {
-			return fmt.Errorf("failed to set up cores with default config: %v", err)
-		}
-
-		Logger = zap.New(zapcore.NewTee(cores...), zap.AddCaller())
-		SugaredLogger = Logger.Sugar()
-		fmt.Println("Logger initialized successfully with default values")
-		return nil
+		return initWithDefaults()
 	}
 
 	// 读取配置文件成功，解码配置
@@ -180,28 +352,125 @@ func InitLogger(configFile string) error {
 
 	fmt.Printf("Loaded config: %+v\n", zapConfig)
 
+	return InitLoggerFromConfig(&zapConfig)
+}
+
+// InitLoggerForEnv initializes the logger from <configDir>/<APP_ENV>.yaml (APP_ENV defaults to "dev").
+// When that file does not exist it falls back to <configDir>/default.yaml, then to the built-in defaults.
+func InitLoggerForEnv(configDir string) error {
+	env := os.Getenv("APP_ENV")
+	if env == "" {
+		env = "dev"
+	}
+	for _, name := range []string{env + ".yaml", "default.yaml"} {
+		path := filepath.Join(configDir, name)
+		if ok, _ := PathExists(path); ok {
+			return InitLogger(path)
+		}
+	}
+	fmt.Printf("No config for env %q in %s, using default values.\n", env, configDir)
+	return initWithDefaults()
+}
+
+// initWithDefaults initializes the global logger from getDefaultConfig
+func initWithDefaults() error {
+	// 使用默认值
+	defaultConfig := getDefaultConfig()
+	l, err := NewWithConfig(defaultConfig, withAtomicLevel(atomicLevel))
+	if err != nil {
+		return fmt.Errorf("failed to set up cores with default config: %v", err)
+	}
+
+	setGlobal(l)
+	runInitHooks(Logger, &defaultConfig)
+	fmt.Println("Logger initialized successfully with default values")
+	return nil
+}
+
+// InitLoggerFromConfig initializes the global logger from an already populated ZapConfig,
+// without going through viper or reading any file
+func InitLoggerFromConfig(cfg *ZapConfig) error {
+	l, err := NewWithConfig(*cfg, withAtomicLevel(atomicLevel))
+	if err != nil {
+		return err
+	}
+
+	setGlobal(l)
+	if cfg.AutoSyncOnSignal {
+		startSignalSync()
+	}
+	runInitHooks(Logger, cfg)
+
+	fmt.Println("Logger initialized successfully")
+	return nil
+}
+
+// NewOption configures NewWithConfig
+type NewOption func(*newOptions)
+
+type newOptions struct {
+	writer zapcore.WriteSyncer
+	level  *zap.AtomicLevel
+}
+
+// WithWriteSyncer sends every entry to ws through a single core encoded with cfg.Format,
+// instead of the per-level files and stdout; Director is then neither created nor used
+func WithWriteSyncer(ws zapcore.WriteSyncer) NewOption {
+	return func(o *newOptions) {
+		o.writer = ws
+	}
+}
+
+// withAtomicLevel makes the logger use level, reset to cfg.Level, instead of a level of its own
+func withAtomicLevel(level zap.AtomicLevel) NewOption {
+	return func(o *newOptions) {
+		o.level = &level
+	}
+}
+
+// NewWithConfig builds a logger from cfg with the same cores as InitLoggerFromConfig, but
+// returns it instead of replacing the global Logger and SugaredLogger
+func NewWithConfig(cfg ZapConfig, opts ...NewOption) (*zap.Logger, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	var o newOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	level := zap.NewAtomicLevelAt(configLevel(&cfg))
+	if o.level != nil {
+		level = *o.level
+		level.SetLevel(configLevel(&cfg))
+	}
+
+	if o.writer != nil {
+		core := zapcore.NewCore(newEncoder(&cfg, cfg.Format), o.writer, level)
+		return zap.New(zapcore.NewTee(wrapCores(&cfg, []zapcore.Core{core})...), zap.AddCaller()), nil
+	}
+
 	// 确保日志目录存在
-	if ok, _ := PathExists(zapConfig.Director); !ok {
-		fmt.Printf("Creating %v directory\n", zapConfig.Director)
-		if err := os.Mkdir(zapConfig.Director, os.ModePerm); err != nil {
-			return fmt.Errorf("failed to create directory %s: %v", zapConfig.Director, err)
+	if ok, _ := PathExists(cfg.Director); !ok {
+		fmt.Printf("Creating %v directory\n", cfg.Director)
+		if err := os.Mkdir(cfg.Director, os.ModePerm); err != nil {
+			return nil, fmt.Errorf("failed to create directory %s: %v", cfg.Director, err)
 		}
 	}
 
 	// 设置日志核心
-	cores, err := setupCores(&zapConfig)
+	cores, err := setupCores(&cfg, level)
 	if err != nil {
-		return fmt.Errorf("failed to set up cores with provided config: %v", err)
+		return nil, fmt.Errorf("failed to set up cores with provided config: %v", err)
 	}
+	return zap.New(zapcore.NewTee(cores...), zap.AddCaller()), nil
+}
 
-	// 初始化 Logger
-	//Logger = zap.New(zapcore.NewTee(cores...), zap.AddCaller())
+// setGlobal installs l as the global Logger and SugaredLogger
+func setGlobal(l *zap.Logger) {
 	//zap.AddCallerSkip(1) 会让 zap 在记录 caller 信息时跳过一层栈帧，从而显示出你业务代码中调用 logger.Debug() 或其他日志函数的正确位置
-	Logger = zap.New(zapcore.NewTee(cores...), zap.AddCaller(), zap.AddCallerSkip(1))
+	Logger = l.WithOptions(zap.AddCallerSkip(1))
 	SugaredLogger = Logger.Sugar()
-
-	fmt.Println("Logger initialized successfully")
-	return nil
+	resetNamedLoggers()
 }
 
 
@@ -221,45 +490,130 @@ func getDefaultConfig() ZapConfig {
 }
 
 
-// setupCores sets up the cores for different log levels
-func setupCores(cfg *ZapConfig) ([]zapcore.Core, error) {
+// setupCores sets up the cores for different log levels; entries below level are dropped
+// by every file core and, unless ConsoleLevel is set, by the console core
+func setupCores(cfg *ZapConfig, level zap.AtomicLevel) ([]zapcore.Core, error) {
 	levels := []zapcore.Level{zapcore.DebugLevel, zapcore.InfoLevel, zapcore.WarnLevel, zapcore.ErrorLevel, zapcore.DPanicLevel, zapcore.PanicLevel, zapcore.FatalLevel}
 	cores := make([]zapcore.Core, 0, len(levels))
 
-	encoderConfig := cfg.EncoderConfig()
-	var encoder zapcore.Encoder
-	if cfg.Format == "json" {
-		encoder = zapcore.NewJSONEncoder(encoderConfig)
-	} else {
-		encoder = zapcore.NewConsoleEncoder(encoderConfig)
-	}
-	for _, level := range levels {
-		writer, err := getLogWriter(cfg, level.String())
+	for _, fileLevel := range levels {
+		writer, err := getLogWriter(cfg, fileLevel.String())
 		if err != nil {
-			return nil, fmt.Errorf("failed to create log file for level %s: %v", level.String(), err)
+			return nil, fmt.Errorf("failed to create log file for level %s: %v", fileLevel.String(), err)
 		}
-		core := zapcore.NewCore(encoder, writer, zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
-			return lvl == level
+		core := zapcore.NewCore(newEncoder(cfg, cfg.Format), writer, zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
+			return lvl == fileLevel && level.Enabled(lvl)
 		}))
 		cores = append(cores, core)
 	}
-	return cores, nil
+
+	if cfg.LogInConsole {
+		cores = append(cores, newConsoleCore(cfg, zapcore.Lock(os.Stdout), level))
+	}
+	return wrapCores(cfg, cores), nil
+}
+
+// wrapCores applies the field transformers, naming strategy, message truncation and expvar
+// statistics configured for the logger to cores
+func wrapCores(cfg *ZapConfig, cores []zapcore.Core) []zapcore.Core {
+	if transformers := registeredFieldTransformers(); len(transformers) > 0 {
+		for i, core := range cores {
+			cores[i] = TransformerCore(core, transformers...)
+		}
+	}
+	if strategy := NamingStrategy(cfg.FieldNamingStrategy); strategy != "" && strategy != NamingOriginal {
+		for i, core := range cores {
+			cores[i] = NamingCore(core, strategy)
+		}
+	}
+	if cfg.MaxMessageLength > 0 {
+		for i, core := range cores {
+			cores[i] = TruncatingCore(core, cfg.MaxMessageLength)
+		}
+	}
+	if expvarEnabled.Load() {
+		cores = []zapcore.Core{withExpvarCore(zapcore.NewTee(cores...))}
+	}
+	return cores
+}
+
+// newEncoder creates an encoder of the given format ("json", "ndjson" or console) for one sink
+func newEncoder(cfg *ZapConfig, format string) zapcore.Encoder {
+	var encoder zapcore.Encoder
+	switch format {
+	case "json":
+		encoder = zapcore.NewJSONEncoder(cfg.EncoderConfig())
+	case "ndjson":
+		encoder = newNDJSONEncoder(cfg.EncoderConfig())
+	default:
+		encoder = NewZeroAllocConsoleEncoder(cfg.EncoderConfig())
+	}
+	if cfg.OmitEmpty {
+		encoder = newOmitEmptyEncoder(encoder)
+	}
+	if len(cfg.FieldOrder) > 0 {
+		encoder = NewSortingEncoder(encoder, cfg.FieldOrder)
+	}
+	return encoder
+}
+
+// newConsoleCore creates the stdout core using ConsoleFormat and ConsoleLevel,
+// falling back to Format and the shared level when they are empty
+func newConsoleCore(cfg *ZapConfig, writer zapcore.WriteSyncer, level zap.AtomicLevel) zapcore.Core {
+	format := cfg.ConsoleFormat
+	if format == "" {
+		format = cfg.Format
+	}
+	if cfg.ConsoleLevel == "" {
+		return zapcore.NewCore(newEncoder(cfg, format), writer, level)
+	}
+	consoleLevel, err := zapcore.ParseLevel(cfg.ConsoleLevel)
+	if err != nil {
+		consoleLevel = zapcore.InfoLevel
+	}
+	return zapcore.NewCore(newEncoder(cfg, format), writer, consoleLevel)
 }
 
 // getLogWriter creates a WriteSyncer for the given file
 func getLogWriter(cfg *ZapConfig, level string) (zapcore.WriteSyncer, error) {
-	timestamp := time.Now().Format("2006010215")
-	filepath := filepath.Join(cfg.Director, fmt.Sprintf("%s_%s.log", level, timestamp))
+	filepath := filepath.Join(cfg.Director, logFileName(cfg, level, time.Now()))
 	lumberJackLogger := &lumberjack.Logger{
 		Filename:   filepath,
 		MaxSize:    1, // 每个日志文件最大 1 MB
 		MaxBackups: 24, // 最多保存 24 个备份文件
 		MaxAge:     cfg.RetentionDay, // 最多保存 cfg.RetentionDay 天的日志文件
-		Compress:   true, // 压缩旧日志文件
+		Compress:   !cfg.CompressInPlace, // 压缩旧日志文件，写入时已压缩则不再压缩
+	}
+	if cfg.WALSync {
+		file := newSyncedFile(lumberJackLogger)
+		var writer io.Writer = file
+		if cfg.CompressInPlace {
+			writer = newGzipWriter(lumberJackLogger, file)
+		}
+		return NewSyncingWriter(writer, file.Sync, cfg.WALBatchSize), nil
+	}
+	var writer io.Writer = lumberJackLogger
+	if cfg.CompressInPlace {
+		writer = NewGzipWriter(lumberJackLogger)
+	}
+	return zapcore.AddSync(writer), nil
+}
+// logFileName returns the file name for level according to FilenameDateFormat: hourly and
+// daily names embed the timestamp of now, static names rely solely on lumberjack rotation
+func logFileName(cfg *ZapConfig, level string, now time.Time) string {
+	ext := ".log"
+	if cfg.CompressInPlace {
+		ext = ".log.gz"
+	}
+	switch cfg.FilenameDateFormat {
+	case "static":
+		return level + ext
+	case "daily":
+		return fmt.Sprintf("%s_%s%s", level, now.Format("20060102"), ext)
+	default:
+		return fmt.Sprintf("%s_%s%s", level, now.Format("2006010215"), ext)
 	}
-	return zapcore.AddSync(lumberJackLogger), nil
 }
-
 
 // PathExists checks if a path exists
 func PathExists(path string) (bool, error) {
@@ -274,57 +628,71 @@ func PathExists(path string) (bool, error) {
 }
 
 func Debug(args ...interface{}) {
+	EnsureInitialized()
 	SugaredLogger.Debug(args...)
 }
 
 func Debugf(template string, args ...interface{}) {
+	EnsureInitialized()
 	SugaredLogger.Debugf(template, args...)
 }
 
 func Info(args ...interface{}) {
+	EnsureInitialized()
 	SugaredLogger.Info(args...)
 }
 
 func Infof(template string, args ...interface{}) {
+	EnsureInitialized()
 	SugaredLogger.Infof(template, args...)
 }
 
 func Warn(args ...interface{}) {
+	EnsureInitialized()
 	SugaredLogger.Warn(args...)
 }
 
 func Warnf(template string, args ...interface{}) {
+	EnsureInitialized()
 	SugaredLogger.Warnf(template, args...)
 }
 
 func Error(args ...interface{}) {
+	EnsureInitialized()
 	SugaredLogger.Error(args...)
 }
 
 func Errorf(template string, args ...interface{}) {
+	EnsureInitialized()
 	SugaredLogger.Errorf(template, args...)
 }
 
 func DPanic(args ...interface{}) {
+	EnsureInitialized()
 	SugaredLogger.DPanic(args...)
 }
 
 func DPanicf(template string, args ...interface{}) {
+	EnsureInitialized()
 	SugaredLogger.DPanicf(template, args...)
 }
 
 func Panic(args ...interface{}) {
+	EnsureInitialized()
 	SugaredLogger.Panic(args...)
 }
 
 func Panicf(template string, args ...interface{}) {
+	EnsureInitialized()
 	SugaredLogger.Panicf(template, args...)
 }
 
 func Fatal(args ...interface{}) {
+	EnsureInitialized()
 	SugaredLogger.Fatal(args...)
 }
 
 func Fatalf(template string, args ...interface{}) {
+	EnsureInitialized()
 	SugaredLogger.Fatalf(template, args...)
 }
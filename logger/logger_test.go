@@ -0,0 +1,540 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// restoreGlobals puts back the global loggers and level replaced by an InitLogger variant
+// when the test ends
+func restoreGlobals(t *testing.T) {
+	t.Helper()
+	original, originalSugared, level := Logger, SugaredLogger, atomicLevel.Level()
+	t.Cleanup(func() {
+		Logger, SugaredLogger = original, originalSugared
+		atomicLevel.SetLevel(level)
+	})
+}
+
+// testConfig returns the default config writing into a temporary directory without console output
+func testConfig(t *testing.T) ZapConfig {
+	cfg := getDefaultConfig()
+	cfg.Director = t.TempDir()
+	cfg.LogInConsole = false
+	return cfg
+}
+
+func newObservedGormLogger(opts ...GormLoggerOption) (GormLogger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return NewGormLogger(zap.New(core), opts...), logs
+}
+
+func mockFC(sql string, rows int64) func() (string, int64) {
+	return func() (string, int64) { return sql, rows }
+}
+
+func TestGormLoggerTraceRecordNotFound(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []GormLoggerOption
+		err       error
+		wantLevel zapcore.Level
+		wantLogs  int
+	}{
+		{"not found defaults to warn", nil, gorm.ErrRecordNotFound, zapcore.WarnLevel, 1},
+		{"wrapped not found", nil, errors.Join(errors.New("query"), gorm.ErrRecordNotFound), zapcore.WarnLevel, 1},
+		{"configured level", []GormLoggerOption{WithErrNotFoundLevel(gormlogger.Info)}, gorm.ErrRecordNotFound, zapcore.InfoLevel, 1},
+		{"silent level", []GormLoggerOption{WithErrNotFoundLevel(gormlogger.Silent)}, gorm.ErrRecordNotFound, 0, 0},
+		{"other errors stay error", nil, errors.New("connection reset"), zapcore.ErrorLevel, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, logs := newObservedGormLogger(tt.opts...)
+			l.LogMode(gormlogger.Info).Trace(context.Background(), time.Now(), mockFC("SELECT * FROM users", 0), tt.err)
+			entries := logs.All()
+			if len(entries) != tt.wantLogs {
+				t.Fatalf("got %d entries, want %d", len(entries), tt.wantLogs)
+			}
+			if tt.wantLogs > 0 && entries[0].Level != tt.wantLevel {
+				t.Errorf("level = %v, want %v", entries[0].Level, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestGormLoggerTraceIgnoreRecordNotFound(t *testing.T) {
+	l, logs := newObservedGormLogger()
+	l.config.IgnoreRecordNotFoundError = true
+	l.LogMode(gormlogger.Info).Trace(context.Background(), time.Now(), mockFC("SELECT 1", 0), gorm.ErrRecordNotFound)
+	entries := logs.All()
+	if len(entries) != 1 || entries[0].Level != zapcore.DebugLevel {
+		t.Fatalf("entries = %+v, want one debug entry", entries)
+	}
+}
+
+// encodeJSON encodes a single entry with the JSON encoder built from cfg and decodes the result
+func encodeJSON(t *testing.T, cfg *ZapConfig, ent zapcore.Entry, fields ...zapcore.Field) map[string]interface{} {
+	t.Helper()
+	buf, err := zapcore.NewJSONEncoder(cfg.EncoderConfig()).EncodeEntry(ent, fields)
+	if err != nil {
+		t.Fatalf("EncodeEntry: %v", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(buf.Bytes()))
+	dec.UseNumber()
+	var out map[string]interface{}
+	if err := dec.Decode(&out); err != nil {
+		t.Fatalf("decode %q: %v", buf.String(), err)
+	}
+	return out
+}
+
+func TestTimeFormat(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 123456789, time.UTC)
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"", "2024-01-02 03:04:05.123"},
+		{"unix", "1704164645.1234567"},
+		{"unixnano", "1704164645123456789"},
+		{"rfc3339", "2024-01-02T03:04:05Z"},
+		{"rfc3339nano", "2024-01-02T03:04:05.123456789Z"},
+		{"02/01/2006 15:04", "02/01/2024 03:04"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			cfg := getDefaultConfig()
+			cfg.TimeFormat = tt.format
+			out := encodeJSON(t, &cfg, zapcore.Entry{Time: ts, Message: "m"})
+			got := fmt.Sprint(out["time"])
+			if got != tt.want {
+				t.Errorf("time = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGormLoggerStructuredTrace(t *testing.T) {
+	l, logs := newObservedGormLogger(WithStructuredTrace(true))
+	begin := time.Now().Add(-300 * time.Millisecond)
+	l.Trace(context.Background(), begin, mockFC("SELECT * FROM orders", 3), nil)
+	l.Trace(context.Background(), time.Now(), mockFC("UPDATE orders SET x=1", 0), errors.New("deadlock"))
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	slow := entries[0]
+	if slow.Level != zapcore.WarnLevel || slow.Message != "gorm trace" {
+		t.Errorf("slow entry = %v %q", slow.Level, slow.Message)
+	}
+	fields := slow.ContextMap()
+	if fields["sql"] != "SELECT * FROM orders" || fields["rows"] != int64(3) || fields["slow_query"] != true {
+		t.Errorf("slow entry fields = %v", fields)
+	}
+	if ms, ok := fields["elapsed_ms"].(float64); !ok || ms < 300 {
+		t.Errorf("elapsed_ms = %v, want >= 300", fields["elapsed_ms"])
+	}
+	if _, ok := fields["error"]; ok {
+		t.Error("error field present for a successful query")
+	}
+
+	failed := entries[1]
+	if failed.Level != zapcore.ErrorLevel || failed.ContextMap()["error"] != "deadlock" {
+		t.Errorf("failed entry = %v %v", failed.Level, failed.ContextMap())
+	}
+}
+
+func TestGormLoggerFormattedTraceByDefault(t *testing.T) {
+	l, logs := newObservedGormLogger()
+	l.Trace(context.Background(), time.Now(), mockFC("SELECT 1", 1), errors.New("boom"))
+	entries := logs.All()
+	if len(entries) != 1 || len(entries[0].Context) != 0 {
+		t.Fatalf("entries = %+v, want one entry without fields", entries)
+	}
+	if msg := entries[0].Message; !strings.Contains(msg, "[rows:1] SELECT 1") {
+		t.Errorf("message = %q", msg)
+	}
+}
+
+func TestInitLoggerFromConfig(t *testing.T) {
+	restoreGlobals(t)
+	cfg := testConfig(t)
+	cfg.Director = filepath.Join(cfg.Director, "nested")
+	cfg.Level = "warn"
+
+	if err := InitLoggerFromConfig(&cfg); err != nil {
+		t.Fatalf("InitLoggerFromConfig: %v", err)
+	}
+	Info("dropped")
+	Warn("kept")
+	if err := Logger.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	matches, _ := filepath.Glob(filepath.Join(cfg.Director, "warn_*.log"))
+	if len(matches) != 1 {
+		t.Fatalf("warn log files = %v, want one", matches)
+	}
+	data, _ := os.ReadFile(matches[0])
+	if !strings.Contains(string(data), `"msg":"kept"`) {
+		t.Errorf("warn log = %q", data)
+	}
+	if infos, _ := filepath.Glob(filepath.Join(cfg.Director, "info_*.log")); len(infos) == 1 {
+		if data, _ := os.ReadFile(infos[0]); len(data) > 0 {
+			t.Errorf("info entry written below the configured level: %q", data)
+		}
+	}
+
+	invalid := testConfig(t)
+	invalid.Level = "verbose"
+	if err := InitLoggerFromConfig(&invalid); err == nil {
+		t.Error("InitLoggerFromConfig accepted an invalid level")
+	}
+}
+
+// writeEnvConfig writes <dir>/<name> selecting level and a log directory under dir
+func writeEnvConfig(t *testing.T, dir, name, level string) {
+	t.Helper()
+	content := fmt.Sprintf("zap:\n  level: %s\n  format: json\n  director: %s\n  log-in-console: false\n",
+		level, filepath.Join(dir, "logs-"+strings.TrimSuffix(name, ".yaml")))
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInitLoggerForEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       string
+		files     map[string]string // file name -> level
+		wantLevel string
+		wantDir   string
+	}{
+		{"env file", "test", map[string]string{"test.yaml": "warn", "default.yaml": "error"}, "warn", "logs-test"},
+		{"default file when env file is missing", "test", map[string]string{"prod.yaml": "debug", "default.yaml": "error"}, "error", "logs-default"},
+		{"APP_ENV defaults to dev", "", map[string]string{"dev.yaml": "debug", "default.yaml": "error"}, "debug", "logs-dev"},
+		{"built-in defaults without any file", "test", nil, "info", "logs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restoreGlobals(t)
+			t.Setenv("APP_ENV", tt.env)
+			dir := t.TempDir()
+			for name, level := range tt.files {
+				writeEnvConfig(t, dir, name, level)
+			}
+			// the built-in defaults log to ./logs, keep that inside the temp dir
+			wd, _ := os.Getwd()
+			if err := os.Chdir(dir); err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { os.Chdir(wd) })
+
+			if err := InitLoggerForEnv(dir); err != nil {
+				t.Fatalf("InitLoggerForEnv: %v", err)
+			}
+			if got := GetLevel(); got != tt.wantLevel {
+				t.Errorf("level = %q, want %q", got, tt.wantLevel)
+			}
+			if ok, _ := PathExists(filepath.Join(dir, tt.wantDir)); !ok {
+				t.Errorf("log directory %s was not created", tt.wantDir)
+			}
+		})
+	}
+}
+
+func TestSeparateConsoleFormatAndLevel(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Level = "debug"
+	cfg.Format = "json"
+	cfg.LogInConsole = true
+	cfg.ConsoleFormat = "console"
+	cfg.ConsoleLevel = "info"
+
+	stdout := captureStdout(t, func() {
+		l, err := NewWithConfig(cfg)
+		if err != nil {
+			t.Fatalf("NewWithConfig: %v", err)
+		}
+		l.Debug("file only")
+		l.Info("everywhere", zap.String("k", "v"))
+		l.Sync()
+	})
+
+	readFile := func(level string) map[string]interface{} {
+		t.Helper()
+		matches, _ := filepath.Glob(filepath.Join(cfg.Director, level+"_*.log"))
+		if len(matches) != 1 {
+			t.Fatalf("%s log files = %v, want one", level, matches)
+		}
+		data, _ := os.ReadFile(matches[0])
+		var entry map[string]interface{}
+		if err := json.Unmarshal(data, &entry); err != nil {
+			t.Fatalf("%s log is not JSON: %q", level, data)
+		}
+		return entry
+	}
+	if entry := readFile("debug"); entry["msg"] != "file only" {
+		t.Errorf("debug file entry = %v", entry)
+	}
+	if entry := readFile("info"); entry["msg"] != "everywhere" || entry["k"] != "v" {
+		t.Errorf("info file entry = %v", entry)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("stdout = %q, want only the info entry", stdout)
+	}
+	if strings.HasPrefix(lines[0], "{") || !strings.Contains(lines[0], "everywhere") || !strings.Contains(lines[0], `{"k": "v"}`) {
+		t.Errorf("stdout line = %q, want console format", lines[0])
+	}
+}
+
+func TestGormLoggerSlowQueryAlertHook(t *testing.T) {
+	type alert struct {
+		count int
+		sql   string
+	}
+	var alerts []alert
+	l, _ := newObservedGormLogger(
+		WithSlowThreshold(100*time.Millisecond),
+		WithSlowQueryAlertHook(3, func(count int, sql string) {
+			alerts = append(alerts, alert{count, sql})
+		}),
+	)
+	slow, fast := time.Now().Add(-time.Second), time.Now()
+
+	// two slow queries, then a fast one resets the count
+	for i, begin := range []time.Time{slow, slow, fast, slow, slow, slow, slow} {
+		l.Trace(context.Background(), begin, mockFC(fmt.Sprintf("SELECT %d", i), 1), nil)
+		if i == 5 && len(alerts) != 1 {
+			t.Fatalf("after query %d got %d alerts, want 1", i, len(alerts))
+		}
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("alerts = %v, want exactly one per run of slow queries", alerts)
+	}
+	if alerts[0] != (alert{3, "SELECT 5"}) {
+		t.Errorf("alert = %+v, want count 3 for SELECT 5", alerts[0])
+	}
+
+	// the counter is shared with copies created by LogMode
+	copied := l.LogMode(gormlogger.Info)
+	copied.Trace(context.Background(), fast, mockFC("SELECT fast", 1), nil)
+	for i := 0; i < 3; i++ {
+		copied.Trace(context.Background(), slow, mockFC("SELECT again", 1), nil)
+	}
+	if len(alerts) != 2 || alerts[1].count != 3 {
+		t.Errorf("alerts after LogMode = %v", alerts)
+	}
+}
+
+func TestLogFileName(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.Local)
+	tests := []struct {
+		format   string
+		compress bool
+		want     string
+	}{
+		{"", false, "info_2024050607.log"},
+		{"hourly", false, "info_2024050607.log"},
+		{"daily", false, "info_20240506.log"},
+		{"static", false, "info.log"},
+		{"daily", true, "info_20240506.log.gz"},
+		{"static", true, "info.log.gz"},
+	}
+	for _, tt := range tests {
+		cfg := ZapConfig{FilenameDateFormat: tt.format, CompressInPlace: tt.compress}
+		if got := logFileName(&cfg, "info", now); got != tt.want {
+			t.Errorf("logFileName(%q, compress=%v) = %q, want %q", tt.format, tt.compress, got, tt.want)
+		}
+	}
+}
+
+func TestFilenameDateFormatStaticWritesFixedNames(t *testing.T) {
+	restoreGlobals(t)
+	cfg := testConfig(t)
+	cfg.FilenameDateFormat = "static"
+	if err := InitLoggerFromConfig(&cfg); err != nil {
+		t.Fatalf("InitLoggerFromConfig: %v", err)
+	}
+	Logger.Info("static name")
+	Logger.Sync()
+
+	data, err := os.ReadFile(filepath.Join(cfg.Director, "info.log"))
+	if err != nil {
+		t.Fatalf("read info.log: %v", err)
+	}
+	if !strings.Contains(string(data), "static name") {
+		t.Errorf("info.log = %q, want the logged entry", data)
+	}
+}
+
+func TestNewWithConfigIndependentLoggers(t *testing.T) {
+	restoreGlobals(t)
+	globalLogger, globalSugared, globalLevel := Logger, SugaredLogger, GetLevel()
+
+	newLogger := func(level string, buf *bytes.Buffer) *zap.Logger {
+		t.Helper()
+		cfg := testConfig(t)
+		cfg.Level = level
+		l, err := NewWithConfig(cfg, WithWriteSyncer(zapcore.AddSync(buf)))
+		if err != nil {
+			t.Fatalf("NewWithConfig: %v", err)
+		}
+		return l
+	}
+	var httpBuf, jobsBuf bytes.Buffer
+	httpLogger := newLogger("debug", &httpBuf)
+	jobsLogger := newLogger("warn", &jobsBuf)
+
+	httpLogger.Debug("http debug")
+	jobsLogger.Info("jobs info")
+	jobsLogger.Warn("jobs warn")
+
+	if got := httpBuf.String(); !strings.Contains(got, "http debug") || strings.Contains(got, "jobs") {
+		t.Errorf("http logger output = %q", got)
+	}
+	got := jobsBuf.String()
+	if !strings.Contains(got, "jobs warn") || strings.Contains(got, "jobs info") || strings.Contains(got, "http") {
+		t.Errorf("jobs logger output = %q", got)
+	}
+
+	// building loggers leaves the globals and the global level untouched
+	if Logger != globalLogger || SugaredLogger != globalSugared {
+		t.Error("NewWithConfig replaced the global loggers")
+	}
+	if GetLevel() != globalLevel {
+		t.Errorf("global level = %q, want %q", GetLevel(), globalLevel)
+	}
+	// and SetLevel does not change the level of a logger built with NewWithConfig
+	if err := SetLevel("error"); err != nil {
+		t.Fatal(err)
+	}
+	jobsLogger.Warn("still enabled")
+	if !strings.Contains(jobsBuf.String(), "still enabled") {
+		t.Error("SetLevel changed the level of a NewWithConfig logger")
+	}
+}
+
+// stubSince makes GormLogger.Trace see every query as taking elapsed
+func stubSince(t *testing.T, elapsed time.Duration) {
+	t.Helper()
+	original := since
+	since = func(time.Time) time.Duration { return elapsed }
+	t.Cleanup(func() { since = original })
+}
+
+func TestGormLoggerSlowThresholdBoundary(t *testing.T) {
+	const threshold = 100 * time.Millisecond
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		want    zapcore.Level
+	}{
+		{"below", threshold - time.Nanosecond, zapcore.DebugLevel},
+		{"exactly at threshold", threshold, zapcore.WarnLevel},
+		{"just above", threshold + time.Nanosecond, zapcore.WarnLevel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubSince(t, tt.elapsed)
+			l, logs := newObservedGormLogger(WithSlowThreshold(threshold), WithStructuredTrace(true))
+			l.Trace(context.Background(), time.Now(), mockFC("SELECT 1", 1), nil)
+
+			entries := logs.All()
+			if len(entries) != 1 {
+				t.Fatalf("%d entries, want 1", len(entries))
+			}
+			if entries[0].Level != tt.want {
+				t.Errorf("level = %v, want %v", entries[0].Level, tt.want)
+			}
+			if slow := entries[0].ContextMap()["slow_query"]; slow != (tt.want == zapcore.WarnLevel) {
+				t.Errorf("slow_query = %v", slow)
+			}
+		})
+	}
+}
+
+func TestGormLoggerDefaultOptions(t *testing.T) {
+	l, _ := newObservedGormLogger()
+	if l.config.SlowThreshold != 200*time.Millisecond || l.config.LogLevel != gormlogger.Warn || l.config.IgnoreRecordNotFoundError {
+		t.Errorf("defaults = %+v", l.config)
+	}
+	l, _ = newObservedGormLogger(WithSlowThreshold(2*time.Second), WithLogLevel(gormlogger.Info), WithIgnoreNotFound(true))
+	if l.config.SlowThreshold != 2*time.Second || l.config.LogLevel != gormlogger.Info || !l.config.IgnoreRecordNotFoundError {
+		t.Errorf("options not applied: %+v", l.config)
+	}
+}
+
+func TestGormLoggerWithIgnoreNotFound(t *testing.T) {
+	tests := []struct {
+		ignore bool
+		want   zapcore.Level
+	}{
+		{true, zapcore.DebugLevel},
+		{false, zapcore.WarnLevel},
+	}
+	for _, tt := range tests {
+		l, logs := newObservedGormLogger(WithIgnoreNotFound(tt.ignore), WithStructuredTrace(true))
+		l.Trace(context.Background(), time.Now(), mockFC("SELECT 1", 0), gorm.ErrRecordNotFound)
+
+		entries := logs.All()
+		if len(entries) != 1 || entries[0].Level != tt.want {
+			t.Fatalf("ignore=%v: entries = %+v, want one %v entry", tt.ignore, entries, tt.want)
+		}
+		// an ignored ErrRecordNotFound is logged as a plain query without the error field
+		if _, hasErr := entries[0].ContextMap()["error"]; hasErr == tt.ignore {
+			t.Errorf("ignore=%v: error field present = %v", tt.ignore, hasErr)
+		}
+	}
+}
+
+func TestGormLoggerStructuredTraceErrorFields(t *testing.T) {
+	stubSince(t, 15*time.Millisecond)
+	l, logs := newObservedGormLogger(WithStructuredTrace(true), WithIgnoreNotFound(true))
+	l.Trace(context.Background(), time.Now(), mockFC("INSERT INTO orders", 0), errors.New("duplicate key"))
+	// a wrapped ErrRecordNotFound is suppressed like the bare error
+	l.Trace(context.Background(), time.Now(), mockFC("SELECT 1", 0), fmt.Errorf("find order: %w", gorm.ErrRecordNotFound))
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	failed := entries[0]
+	if failed.Level != zapcore.ErrorLevel {
+		t.Errorf("level = %v, want error", failed.Level)
+	}
+	types := make(map[string]zapcore.FieldType)
+	for _, f := range failed.Context {
+		types[f.Key] = f.Type
+	}
+	// the error and elapsed time are typed zap fields, not part of a formatted message
+	if types["error"] != zapcore.ErrorType || types["elapsed"] != zapcore.DurationType ||
+		types["sql"] != zapcore.StringType || types["rows"] != zapcore.Int64Type {
+		t.Errorf("field types = %v", types)
+	}
+	fields := failed.ContextMap()
+	if fields["error"] != "duplicate key" || fields["elapsed"] != 15*time.Millisecond || fields["sql"] != "INSERT INTO orders" {
+		t.Errorf("fields = %v", fields)
+	}
+
+	notFound := entries[1]
+	if _, ok := notFound.ContextMap()["error"]; ok || notFound.Level != zapcore.DebugLevel {
+		t.Errorf("not found entry = %v %v, want debug without error", notFound.Level, notFound.ContextMap())
+	}
+}
@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// TraceTimingMiddleware starts a server span for every request and, once the handler
+// returns, logs the trace ID, elapsed time and status code at Info level. A panicking
+// handler is recorded as a span event and the panic is re-raised.
+func TraceTimingMiddleware(tracer trace.Tracer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, span := tracer.Start(r.Context(), r.Method+" "+r.URL.Path,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attribute.String("http.method", r.Method),
+					attribute.String("http.path", r.URL.Path),
+				),
+			)
+			defer span.End()
+
+			defer func() {
+				if p := recover(); p != nil {
+					span.AddEvent("panic", trace.WithAttributes(attribute.String("panic.value", fmt.Sprint(p))))
+					span.SetStatus(codes.Error, "panic")
+					panic(p)
+				}
+			}()
+
+			status, elapsed := serveAndMeasure(next, w, r.WithContext(ctx))
+
+			span.SetAttributes(attribute.Int("http.status_code", status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			directLogger().Info("request completed",
+				zap.String("trace_id", span.SpanContext().TraceID().String()),
+				zap.Float64("elapsed_ms", elapsedMillis(elapsed)),
+				zap.Int("status", status),
+			)
+		})
+	}
+}
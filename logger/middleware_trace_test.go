@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap/zapcore"
+)
+
+func newRecordingTracer(t *testing.T) (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	return recorder, provider
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTraceTimingMiddleware(t *testing.T) {
+	logs := ObserveGlobal(t, zapcore.DebugLevel)
+	recorder, provider := newRecordingTracer(t)
+
+	h := TraceTimingMiddleware(provider.Tracer("test"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("%d spans ended, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "POST /orders" {
+		t.Errorf("span name = %q", span.Name())
+	}
+	attrs := spanAttributes(span)
+	if attrs["http.method"].AsString() != http.MethodPost || attrs["http.path"].AsString() != "/orders" {
+		t.Errorf("span attributes = %v", attrs)
+	}
+	if attrs["http.status_code"].AsInt64() != http.StatusAccepted {
+		t.Errorf("http.status_code = %v, want 202", attrs["http.status_code"])
+	}
+
+	entries := logs.FilterMessage("request completed").All()
+	if len(entries) != 1 {
+		t.Fatalf("%d \"request completed\" entries, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["trace_id"] != span.SpanContext().TraceID().String() {
+		t.Errorf("trace_id = %v, want %s", fields["trace_id"], span.SpanContext().TraceID())
+	}
+	if fields["status"] != int64(http.StatusAccepted) {
+		t.Errorf("status = %v, want 202", fields["status"])
+	}
+	if ms, ok := fields["elapsed_ms"].(float64); !ok || ms < 0 {
+		t.Errorf("elapsed_ms = %v", fields["elapsed_ms"])
+	}
+	if file := filepath.Base(entries[0].Caller.File); file != "middleware_trace.go" {
+		t.Errorf("caller = %s, want middleware_trace.go", entries[0].Caller.String())
+	}
+}
+
+func TestTraceTimingMiddlewareServerError(t *testing.T) {
+	ObserveGlobal(t, zapcore.DebugLevel)
+	recorder, provider := newRecordingTracer(t)
+
+	h := TraceTimingMiddleware(provider.Tracer("test"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if status := recorder.Ended()[0].Status(); status.Code != codes.Error {
+		t.Errorf("span status = %v, want error for a 5xx response", status)
+	}
+}
+
+func TestTraceTimingMiddlewarePanic(t *testing.T) {
+	logs := ObserveGlobal(t, zapcore.DebugLevel)
+	recorder, provider := newRecordingTracer(t)
+
+	h := TraceTimingMiddleware(provider.Tracer("test"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	func() {
+		defer func() {
+			if p := recover(); p != "boom" {
+				t.Errorf("recovered %v, want the handler's panic to be re-raised", p)
+			}
+		}()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("%d spans ended, want 1", len(spans))
+	}
+	// the SDK adds its own exception event when a span ends during a panic
+	var panicEvent *sdktrace.Event
+	for i, event := range spans[0].Events() {
+		if event.Name == "panic" {
+			panicEvent = &spans[0].Events()[i]
+		}
+	}
+	if panicEvent == nil {
+		t.Fatalf("span events = %v, want a panic event", spans[0].Events())
+	}
+	if v := panicEvent.Attributes[0]; v.Key != "panic.value" || v.Value.AsString() != "boom" {
+		t.Errorf("panic event attribute = %v", v)
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("span status = %v, want error", spans[0].Status())
+	}
+	if logs.Len() != 0 {
+		t.Errorf("panicking request was logged as completed: %v", logs.All())
+	}
+}
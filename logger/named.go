@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// namedKey identifies a named child of a specific base logger, so re-initializing the
+// global Logger never hands out children of the old one
+type namedKey struct {
+	base *zap.Logger
+	name string
+}
+
+// namedLoggers caches named children of the global Logger
+var namedLoggers sync.Map
+
+// Named returns a child of the global Logger with the given name, initializing the global
+// Logger if needed. The child is created once and reused by subsequent calls. It does not
+// carry the caller skip of the package-level log functions, and its minimum level follows
+// SetNamedLevel.
+func Named(name string) *zap.Logger {
+	EnsureInitialized()
+	base := Logger
+	key := namedKey{base: base, name: name}
+	if l, ok := namedLoggers.Load(key); ok {
+		return l.(*zap.Logger)
+	}
+	child := base.WithOptions(zap.AddCallerSkip(-1)).Named(name).WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &namedLevelCore{Core: core, name: name}
+	}))
+	l, _ := namedLoggers.LoadOrStore(key, child)
+	return l.(*zap.Logger)
+}
+
+// resetNamedLoggers drops the cached children, which all belong to the previous global Logger
+func resetNamedLoggers() {
+	namedLoggers.Range(func(key, _ interface{}) bool {
+		namedLoggers.Delete(key)
+		return true
+	})
+}
+
+// levelNode is a node of the dot-separated logger name trie
+type levelNode struct {
+	level    *zapcore.Level
+	children map[string]*levelNode
+}
+
+// namedLevels holds the levels configured with SetNamedLevel
+var namedLevels = struct {
+	sync.RWMutex
+	root levelNode
+}{}
+
+// SetNamedLevel sets the minimum level of the named logger and of every descendant
+// ("db" also covers "db.mysql") that has no level of its own
+func SetNamedLevel(name, level string) error {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	namedLevels.Lock()
+	defer namedLevels.Unlock()
+	node := &namedLevels.root
+	for _, part := range strings.Split(name, ".") {
+		if node.children == nil {
+			node.children = make(map[string]*levelNode)
+		}
+		child, ok := node.children[part]
+		if !ok {
+			child = &levelNode{}
+			node.children[part] = child
+		}
+		node = child
+	}
+	node.level = &lvl
+	return nil
+}
+
+// NamedLevel returns the effective level of the named logger: its own level if set,
+// otherwise that of the nearest ancestor. ok is false when no level applies.
+func NamedLevel(name string) (zapcore.Level, bool) {
+	namedLevels.RLock()
+	defer namedLevels.RUnlock()
+	var (
+		found *zapcore.Level
+		node  = &namedLevels.root
+	)
+	for _, part := range strings.Split(name, ".") {
+		child, ok := node.children[part]
+		if !ok {
+			break
+		}
+		node = child
+		if node.level != nil {
+			found = node.level
+		}
+	}
+	if found == nil {
+		return zapcore.DebugLevel, false
+	}
+	return *found, true
+}
+
+// namedLevelCore drops entries below the level resolved for its logger name. The level is
+// looked up on every check, so SetNamedLevel also affects loggers created earlier.
+type namedLevelCore struct {
+	zapcore.Core
+	name string
+}
+
+func (c *namedLevelCore) Enabled(level zapcore.Level) bool {
+	if min, ok := NamedLevel(c.name); ok && level < min {
+		return false
+	}
+	return c.Core.Enabled(level)
+}
+
+func (c *namedLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &namedLevelCore{Core: c.Core.With(fields), name: c.name}
+}
+
+func (c *namedLevelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if min, ok := NamedLevel(c.name); ok && ent.Level < min {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
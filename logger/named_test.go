@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// clearNamedLevels empties the level trie for the test and restores it afterwards
+func clearNamedLevels(t *testing.T) {
+	t.Helper()
+	namedLevels.Lock()
+	saved := namedLevels.root
+	namedLevels.root = levelNode{}
+	namedLevels.Unlock()
+	t.Cleanup(func() {
+		namedLevels.Lock()
+		namedLevels.root = saved
+		namedLevels.Unlock()
+	})
+}
+
+func TestNamedLevelInheritance(t *testing.T) {
+	clearNamedLevels(t)
+	for name, level := range map[string]string{
+		"db":             "error",
+		"db.mysql":       "warn",
+		"db.mysql.slow":  "debug",
+		"http.client.v2": "info",
+	} {
+		if err := SetNamedLevel(name, level); err != nil {
+			t.Fatalf("SetNamedLevel(%q, %q): %v", name, level, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		want   zapcore.Level
+		wantOK bool
+	}{
+		{"db", zapcore.ErrorLevel, true},
+		{"db.postgres", zapcore.ErrorLevel, true},         // inherits from db
+		{"db.postgres.replica", zapcore.ErrorLevel, true}, // two levels below db
+		{"db.mysql", zapcore.WarnLevel, true},             // own level overrides db
+		{"db.mysql.pool", zapcore.WarnLevel, true},        // inherits the override
+		{"db.mysql.slow", zapcore.DebugLevel, true},       // third level override
+		{"db.mysql.slow.x", zapcore.DebugLevel, true},
+		{"dbx", zapcore.DebugLevel, false}, // a shared prefix is not an ancestor
+		{"http", zapcore.DebugLevel, false},
+		{"http.client", zapcore.DebugLevel, false}, // intermediate node without a level
+		{"http.client.v2.retry", zapcore.InfoLevel, true},
+		{"", zapcore.DebugLevel, false},
+	}
+	for _, tt := range tests {
+		got, ok := NamedLevel(tt.name)
+		if ok != tt.wantOK || (ok && got != tt.want) {
+			t.Errorf("NamedLevel(%q) = %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	// changing the parent affects children without their own level
+	if err := SetNamedLevel("db", "info"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := NamedLevel("db.postgres"); got != zapcore.InfoLevel {
+		t.Errorf("NamedLevel(db.postgres) after changing db = %v, want info", got)
+	}
+	if got, _ := NamedLevel("db.mysql"); got != zapcore.WarnLevel {
+		t.Errorf("NamedLevel(db.mysql) after changing db = %v, want its own warn", got)
+	}
+}
+
+func TestSetNamedLevelInvalid(t *testing.T) {
+	clearNamedLevels(t)
+	if err := SetNamedLevel("db", "loud"); err == nil {
+		t.Error("SetNamedLevel accepted an invalid level")
+	}
+	if _, ok := NamedLevel("db"); ok {
+		t.Error("invalid level was stored")
+	}
+}
+
+func TestNamedLoggerFollowsHierarchy(t *testing.T) {
+	clearNamedLevels(t)
+	logs := ObserveGlobal(t, zapcore.DebugLevel)
+	l := Named("db.mysql")
+
+	l.Info("before")
+	if err := SetNamedLevel("db", "error"); err != nil {
+		t.Fatal(err)
+	}
+	l.Warn("dropped")
+	l.Error("kept")
+	if err := SetNamedLevel("db.mysql", "warn"); err != nil {
+		t.Fatal(err)
+	}
+	l.Info("dropped")
+	l.Warn("after override")
+
+	var got []string
+	for _, e := range logs.All() {
+		got = append(got, e.Message)
+	}
+	want := []string{"before", "kept", "after override"}
+	if len(got) != len(want) {
+		t.Fatalf("messages = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("messages = %q, want %q", got, want)
+		}
+	}
+}
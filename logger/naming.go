@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"strings"
+	"unicode"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NamingStrategy selects how field keys are rewritten before encoding
+type NamingStrategy string
+
+const (
+	NamingOriginal  NamingStrategy = "original"
+	NamingSnakeCase NamingStrategy = "snake_case"
+	NamingCamelCase NamingStrategy = "camelCase"
+)
+
+// NamingCore wraps base so that every field key is renamed using strategy. Only field keys are
+// affected; the entry keys (time, level, msg, ...) come from the EncoderConfig.
+func NamingCore(base zapcore.Core, strategy NamingStrategy) zapcore.Core {
+	var rename func(string) string
+	switch strategy {
+	case NamingSnakeCase:
+		rename = camelToSnake
+	case NamingCamelCase:
+		rename = func(key string) string { return snakeToCamel(camelToSnake(key)) }
+	default:
+		return base
+	}
+	return TransformerCore(base, func(f zap.Field) zap.Field {
+		f.Key = rename(f.Key)
+		return f
+	})
+}
+
+// camelToSnake converts camelCase or PascalCase to snake_case, keeping runs of capitals
+// together: "RequestID" -> "request_id", "HTTPServer" -> "http_server"
+func camelToSnake(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '_' {
+				prev := runes[i-1]
+				nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+					b.WriteByte('_')
+				}
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// snakeToCamel converts snake_case to camelCase: "request_id" -> "requestId"
+func snakeToCamel(s string) string {
+	parts := strings.Split(s, "_")
+	var b strings.Builder
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		if i == 0 || b.Len() == 0 {
+			b.WriteString(part)
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestCamelToSnake(t *testing.T) {
+	tests := map[string]string{
+		"requestID":  "request_id",
+		"RequestID":  "request_id",
+		"HTTPServer": "http_server",
+		"userName":   "user_name",
+		"already_ok": "already_ok",
+		"id":         "id",
+		"route2Name": "route2_name",
+	}
+	for in, want := range tests {
+		if got := camelToSnake(in); got != want {
+			t.Errorf("camelToSnake(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSnakeToCamel(t *testing.T) {
+	tests := map[string]string{
+		"request_id": "requestId",
+		"user_name":  "userName",
+		"_leading":   "leading",
+		"plain":      "plain",
+	}
+	for in, want := range tests {
+		if got := snakeToCamel(in); got != want {
+			t.Errorf("snakeToCamel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFieldNamingStrategy(t *testing.T) {
+	tests := []struct {
+		strategy string
+		want     string
+	}{
+		{"", "requestID"},
+		{"original", "requestID"},
+		{"snake_case", "request_id"},
+		{"camelCase", "requestId"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.FieldNamingStrategy = tt.strategy
+			var buf bytes.Buffer
+			l, err := NewWithConfig(cfg, WithWriteSyncer(zapcore.AddSync(&buf)))
+			if err != nil {
+				t.Fatalf("NewWithConfig: %v", err)
+			}
+			l.Info("request", zap.String("requestID", "x"))
+
+			var entry map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("decode %q: %v", buf.String(), err)
+			}
+			if entry[tt.want] != "x" {
+				t.Errorf("entry = %v, want key %q", entry, tt.want)
+			}
+		})
+	}
+}
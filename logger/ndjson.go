@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// newlineReplacer turns raw line breaks into spaces
+var newlineReplacer = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// ndjsonEncoder wraps the JSON encoder so that every entry is exactly one line terminated by a
+// single "\n": line breaks in the message, stack and string fields are replaced by spaces
+// before encoding, which keeps multi-line values readable for processors such as Loki or Vector.
+type ndjsonEncoder struct {
+	zapcore.Encoder
+}
+
+func newNDJSONEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	cfg.LineEnding = zapcore.DefaultLineEnding
+	return &ndjsonEncoder{Encoder: zapcore.NewJSONEncoder(cfg)}
+}
+
+func (e *ndjsonEncoder) Clone() zapcore.Encoder {
+	return &ndjsonEncoder{Encoder: e.Encoder.Clone()}
+}
+
+func (e *ndjsonEncoder) AddString(key, value string) {
+	e.Encoder.AddString(key, newlineReplacer.Replace(value))
+}
+
+func (e *ndjsonEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	ent.Message = newlineReplacer.Replace(ent.Message)
+	ent.Stack = newlineReplacer.Replace(ent.Stack)
+	sanitized := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		if f.Type == zapcore.StringType {
+			f.String = newlineReplacer.Replace(f.String)
+		}
+		sanitized[i] = f
+	}
+	buf, err := e.Encoder.EncodeEntry(ent, sanitized)
+	if err != nil {
+		return nil, err
+	}
+	// guarantee exactly one trailing newline
+	trimmed := bytes.TrimRight(buf.Bytes(), "\r\n")
+	if len(trimmed) != buf.Len()-1 {
+		line := append([]byte(nil), trimmed...)
+		buf.Reset()
+		buf.Write(line)
+		buf.AppendByte('\n')
+	}
+	return buf, nil
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNDJSONSingleLine(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Format = "ndjson"
+	var buf bytes.Buffer
+	l, err := NewWithConfig(cfg, WithWriteSyncer(zapcore.AddSync(&buf)))
+	if err != nil {
+		t.Fatalf("NewWithConfig: %v", err)
+	}
+	l = l.WithOptions(zap.AddStacktrace(zapcore.ErrorLevel)).With(zap.String("context", "a\nb"))
+
+	l.Error("first line\nsecond line\r\nthird", zap.String("sql", "SELECT 1\nFROM dual"))
+
+	out := buf.String()
+	if strings.Count(out, "\n") != 1 || !strings.HasSuffix(out, "}\n") {
+		t.Fatalf("output %q is not a single line ending in one newline", out)
+	}
+	if strings.Contains(out, `\n`) || strings.Contains(out, `\r`) {
+		t.Errorf("output %q still contains escaped line breaks", out)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &entry); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if entry["msg"] != "first line second line third" {
+		t.Errorf("msg = %q", entry["msg"])
+	}
+	if entry["sql"] != "SELECT 1 FROM dual" || entry["context"] != "a b" {
+		t.Errorf("fields = sql %q, context %q", entry["sql"], entry["context"])
+	}
+	if stack, _ := entry["stacktrace"].(string); stack == "" {
+		t.Error("stacktrace missing")
+	}
+}
+
+func TestNDJSONEncoderClone(t *testing.T) {
+	enc := newNDJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"})
+	clone := enc.Clone()
+	clone.AddString("k", "v\nw")
+	buf, err := clone.EncodeEntry(zapcore.Entry{Message: "m"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != `{"msg":"m","k":"v w"}`+"\n" {
+		t.Errorf("clone output = %q", got)
+	}
+	if buf, _ := enc.EncodeEntry(zapcore.Entry{Message: "m"}, nil); strings.Contains(buf.String(), `"k"`) {
+		t.Errorf("field added to the clone leaked into the original: %q", buf.String())
+	}
+}
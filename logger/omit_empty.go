@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"reflect"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// omitEmptyEncoder drops fields whose value is an empty array, object, slice or map
+type omitEmptyEncoder struct {
+	zapcore.Encoder
+}
+
+func newOmitEmptyEncoder(enc zapcore.Encoder) zapcore.Encoder {
+	return &omitEmptyEncoder{Encoder: enc}
+}
+
+func (e *omitEmptyEncoder) Clone() zapcore.Encoder {
+	return &omitEmptyEncoder{Encoder: e.Encoder.Clone()}
+}
+
+func (e *omitEmptyEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	if isEmptyArray(arr) {
+		return nil
+	}
+	return e.Encoder.AddArray(key, arr)
+}
+
+func (e *omitEmptyEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	if isEmptyObject(obj) {
+		return nil
+	}
+	return e.Encoder.AddObject(key, obj)
+}
+
+func (e *omitEmptyEncoder) AddReflected(key string, value interface{}) error {
+	if isEmptyReflected(value) {
+		return nil
+	}
+	return e.Encoder.AddReflected(key, value)
+}
+
+// EncodeEntry filters the entry's own fields; the wrapped encoder adds them to an internal
+// clone, which would bypass the Add* overrides above
+func (e *omitEmptyEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	kept := make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		if !isEmptyField(f) {
+			kept = append(kept, f)
+		}
+	}
+	return e.Encoder.EncodeEntry(ent, kept)
+}
+
+// isEmptyField reports whether f holds an empty array, object, slice or map
+func isEmptyField(f zapcore.Field) bool {
+	switch f.Type {
+	case zapcore.ArrayMarshalerType:
+		arr, ok := f.Interface.(zapcore.ArrayMarshaler)
+		return ok && isEmptyArray(arr)
+	case zapcore.ObjectMarshalerType:
+		obj, ok := f.Interface.(zapcore.ObjectMarshaler)
+		return ok && isEmptyObject(obj)
+	case zapcore.ReflectType:
+		return isEmptyReflected(f.Interface)
+	default:
+		return false
+	}
+}
+
+// isEmptyArray marshals arr into a map encoder to see whether it appends any element
+func isEmptyArray(arr zapcore.ArrayMarshaler) bool {
+	enc := zapcore.NewMapObjectEncoder()
+	if err := enc.AddArray("v", arr); err != nil {
+		return false
+	}
+	values, ok := enc.Fields["v"].([]interface{})
+	return ok && len(values) == 0
+}
+
+// isEmptyObject marshals obj into a map encoder to see whether it adds any field
+func isEmptyObject(obj zapcore.ObjectMarshaler) bool {
+	enc := zapcore.NewMapObjectEncoder()
+	if err := obj.MarshalLogObject(enc); err != nil {
+		return false
+	}
+	return len(enc.Fields) == 0
+}
+
+func isEmptyReflected(value interface{}) bool {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	default:
+		return false
+	}
+}
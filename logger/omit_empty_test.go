@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestOmitEmpty(t *testing.T) {
+	emptyArray := zapcore.ArrayMarshalerFunc(func(zapcore.ArrayEncoder) error { return nil })
+	emptyObject := zapcore.ObjectMarshalerFunc(func(zapcore.ObjectEncoder) error { return nil })
+
+	for _, omit := range []bool{true, false} {
+		t.Run(fmt.Sprintf("OmitEmpty=%v", omit), func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.OmitEmpty = omit
+			var buf bytes.Buffer
+			l, err := NewWithConfig(cfg, WithWriteSyncer(zapcore.AddSync(&buf)))
+			if err != nil {
+				t.Fatalf("NewWithConfig: %v", err)
+			}
+			l.With(zap.Array("context_tags", emptyArray)).Info("entry",
+				zap.Array("tags", emptyArray),
+				zap.Strings("ids", nil),
+				zap.Object("meta", emptyObject),
+				zap.Any("list", []int{}),
+				zap.Strings("kept", []string{"a"}),
+			)
+
+			var entry map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("decode %q: %v", buf.String(), err)
+			}
+			for _, key := range []string{"context_tags", "tags", "ids", "meta", "list"} {
+				if _, ok := entry[key]; ok == omit {
+					t.Errorf("field %q present = %v in %s", key, ok, buf.String())
+				}
+			}
+			if _, ok := entry["kept"]; !ok {
+				t.Errorf("non-empty field dropped: %s", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"math/rand"
+	"path"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// requestContextKey is the field key used by RequestContextField
+const requestContextKey = "request"
+
+// RequestContext carries the HTTP request attributes a PathSampler decides on.
+// Attach it to an entry with RequestContextField.
+type RequestContext struct {
+	Path       string
+	StatusCode int
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler
+func (rc RequestContext) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("path", rc.Path)
+	if rc.StatusCode != 0 {
+		enc.AddInt("status", rc.StatusCode)
+	}
+	return nil
+}
+
+// RequestContextField returns a field carrying the request path and status code
+func RequestContextField(path string, statusCode int) zap.Field {
+	return zap.Object(requestContextKey, RequestContext{Path: path, StatusCode: statusCode})
+}
+
+// SamplingRule keeps SampleRate (0.0-1.0) of the entries whose request path matches
+// PathGlob (path.Match syntax) and whose status equals StatusCode (0 matches any status)
+type SamplingRule struct {
+	PathGlob   string
+	StatusCode int
+	SampleRate float64
+}
+
+func (r SamplingRule) matches(rc RequestContext) bool {
+	if r.StatusCode != 0 && r.StatusCode != rc.StatusCode {
+		return false
+	}
+	ok, err := path.Match(r.PathGlob, rc.Path)
+	return err == nil && ok
+}
+
+// NoMatchPolicy decides what happens to request entries that match no rule
+type NoMatchPolicy int
+
+const (
+	// NoMatchAccept writes entries that match no rule (the default)
+	NoMatchAccept NoMatchPolicy = iota
+	// NoMatchDrop drops entries that match no rule
+	NoMatchDrop
+)
+
+// PathSamplerOption configures NewPathSampler
+type PathSamplerOption func(*pathSampler)
+
+// WithNoMatch sets the policy for request entries that match no rule
+func WithNoMatch(policy NoMatchPolicy) PathSamplerOption {
+	return func(s *pathSampler) {
+		s.noMatch = policy
+	}
+}
+
+// pathSampler is a zapcore.Core that samples entries by their RequestContext
+type pathSampler struct {
+	zapcore.Core
+	rules   []SamplingRule
+	noMatch NoMatchPolicy
+	// rc is set when a RequestContext was attached with Logger.With
+	rc *RequestContext
+}
+
+// NewPathSampler wraps base with per-path sampling. The first rule matching an entry's
+// RequestContext wins; entries without a RequestContext are always written.
+func NewPathSampler(base zapcore.Core, rules []SamplingRule, opts ...PathSamplerOption) zapcore.Core {
+	s := &pathSampler{Core: base, rules: rules}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *pathSampler) With(fields []zapcore.Field) zapcore.Core {
+	clone := *s
+	clone.Core = s.Core.With(fields)
+	if rc, ok := findRequestContext(fields); ok {
+		clone.rc = &rc
+	}
+	return &clone
+}
+
+func (s *pathSampler) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if s.Enabled(ent.Level) {
+		return ce.AddCore(ent, s)
+	}
+	return ce
+}
+
+func (s *pathSampler) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	rc, ok := findRequestContext(fields)
+	if !ok {
+		if s.rc == nil {
+			return writeChecked(s.Core, ent, fields)
+		}
+		rc = *s.rc
+	}
+	if !s.keep(rc) {
+		return nil
+	}
+	return writeChecked(s.Core, ent, fields)
+}
+
+// keep applies the first matching rule, or the no-match policy
+func (s *pathSampler) keep(rc RequestContext) bool {
+	for _, rule := range s.rules {
+		if rule.matches(rc) {
+			return rule.SampleRate >= 1 || (rule.SampleRate > 0 && rand.Float64() < rule.SampleRate)
+		}
+	}
+	return s.noMatch == NoMatchAccept
+}
+
+// findRequestContext returns the last RequestContext carried by fields
+func findRequestContext(fields []zapcore.Field) (RequestContext, bool) {
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Type != zapcore.ObjectMarshalerType {
+			continue
+		}
+		switch rc := fields[i].Interface.(type) {
+		case RequestContext:
+			return rc, true
+		case *RequestContext:
+			if rc != nil {
+				return *rc, true
+			}
+		}
+	}
+	return RequestContext{}, false
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestPathSampler(t *testing.T) {
+	rules := []SamplingRule{
+		{PathGlob: "/health*", StatusCode: 200, SampleRate: 0},
+		{PathGlob: "/api/*", SampleRate: 1},
+	}
+	tests := []struct {
+		name    string
+		path    string
+		status  int
+		noMatch NoMatchPolicy
+		want    int
+	}{
+		{"drops healthy health checks", "/healthz", 200, NoMatchAccept, 0},
+		{"keeps failing health checks", "/healthz", 503, NoMatchAccept, 100},
+		{"keeps api", "/api/orders", 500, NoMatchAccept, 100},
+		{"accepts no match by default", "/metrics", 200, NoMatchAccept, 100},
+		{"drops no match when configured", "/metrics", 200, NoMatchDrop, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.DebugLevel)
+			l := zap.New(NewPathSampler(core, rules, WithNoMatch(tt.noMatch)))
+			for i := 0; i < 100; i++ {
+				l.Info("request", RequestContextField(tt.path, tt.status))
+			}
+			if n := logs.Len(); n != tt.want {
+				t.Errorf("wrote %d of 100 entries, want %d", n, tt.want)
+			}
+		})
+	}
+}
+
+func TestPathSamplerRateAndContext(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := zap.New(NewPathSampler(core, []SamplingRule{{PathGlob: "/search", SampleRate: 0.5}}, WithNoMatch(NoMatchDrop)))
+
+	// the RequestContext can also come from With
+	reqLogger := l.With(RequestContextField("/search", 200))
+	for i := 0; i < 2000; i++ {
+		reqLogger.Info("request")
+	}
+	if n := logs.Len(); n < 800 || n > 1200 {
+		t.Errorf("kept %d of 2000 entries at rate 0.5", n)
+	}
+
+	// entries without a RequestContext are always written, even with NoMatchDrop
+	before := logs.Len()
+	l.Info("startup")
+	if logs.Len() != before+1 {
+		t.Error("entry without a RequestContext was dropped")
+	}
+	if fields := logs.All()[0].ContextMap(); fields["request"] == nil {
+		t.Errorf("request field not encoded: %v", fields)
+	}
+}
+
+func TestPathSamplerKeepsTeeLevels(t *testing.T) {
+	base, logs := levelTee(zapcore.InfoLevel, zapcore.ErrorLevel)
+	rules := []SamplingRule{{PathGlob: "/healthz", SampleRate: 0}, {PathGlob: "/api/*", SampleRate: 1}}
+	l := zap.New(NewPathSampler(base, rules))
+
+	l.Info("request", RequestContextField("/api/orders", 200))
+	l.Info("request", RequestContextField("/healthz", 200))
+	l.Error("request failed", RequestContextField("/api/orders", 500))
+	l.Error("no request context")
+
+	if info := logs[zapcore.InfoLevel].All(); len(info) != 1 || info[0].Message != "request" {
+		t.Errorf("info core = %v, want the kept info entry only", info)
+	}
+	errs := logs[zapcore.ErrorLevel].All()
+	if len(errs) != 2 || errs[0].Message != "request failed" || errs[1].Message != "no request context" {
+		t.Errorf("error core = %v, want the two error entries only", errs)
+	}
+}
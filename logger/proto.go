@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"encoding/json"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Proto returns a field holding msg as a nested object, marshalled with protojson so that
+// field names and well-known types follow the protobuf JSON mapping. Nil messages are skipped.
+func Proto(key string, msg proto.Message) zap.Field {
+	data, ok, err := marshalProto(msg)
+	if !ok {
+		return zap.Skip()
+	}
+	if err != nil {
+		return zap.NamedError(key, err)
+	}
+	return zap.Reflect(key, json.RawMessage(data))
+}
+
+// ProtoJSON returns a field holding msg as a protojson-encoded string. Nil messages are skipped.
+func ProtoJSON(key string, msg proto.Message) zap.Field {
+	data, ok, err := marshalProto(msg)
+	if !ok {
+		return zap.Skip()
+	}
+	if err != nil {
+		return zap.NamedError(key, err)
+	}
+	return zap.String(key, string(data))
+}
+
+// marshalProto encodes msg on a single line; ok is false for nil messages, including typed nil pointers
+func marshalProto(msg proto.Message) ([]byte, bool, error) {
+	if msg == nil || !msg.ProtoReflect().IsValid() {
+		return nil, false, nil
+	}
+	data, err := protojson.MarshalOptions{Multiline: false}.Marshal(msg)
+	return data, true, err
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+type contextFieldsKey struct{}
+
+// contextFields is the immutable set of fields attached to a context, plus a cache of the
+// loggers derived from it. A new set is created whenever fields are added, so a cached
+// child is always built from the current fields.
+type contextFields struct {
+	fields   []zap.Field
+	children sync.Map // *zap.Logger -> *zap.Logger
+}
+
+// ContextWithFields returns a copy of ctx carrying fields in addition to those already attached
+func ContextWithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	parent := ExtractContextFields(ctx)
+	merged := make([]zap.Field, 0, len(parent)+len(fields))
+	merged = append(merged, parent...)
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, contextFieldsKey{}, &contextFields{fields: merged})
+}
+
+// ExtractContextFields returns the fields attached to ctx by ContextWithFields
+func ExtractContextFields(ctx context.Context) []zap.Field {
+	if cf, ok := ctx.Value(contextFieldsKey{}).(*contextFields); ok {
+		return cf.fields
+	}
+	return nil
+}
+
+// Request returns the named logger carrying the fields attached to ctx. The derived logger
+// is cached in the context, so repeated calls with the same context and name return the
+// same *zap.Logger without calling With again.
+func Request(ctx context.Context, name string) *zap.Logger {
+	named := Named(name)
+	cf, ok := ctx.Value(contextFieldsKey{}).(*contextFields)
+	if !ok || len(cf.fields) == 0 {
+		return named
+	}
+	if l, ok := cf.children.Load(named); ok {
+		return l.(*zap.Logger)
+	}
+	l, _ := cf.children.LoadOrStore(named, named.With(cf.fields...))
+	return l.(*zap.Logger)
+}
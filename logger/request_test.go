@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRequestReusesChildForSameContext(t *testing.T) {
+	logs := ObserveGlobal(t, zapcore.DebugLevel)
+	ctx := ContextWithFields(context.Background(), zap.String("request_id", "r-1"))
+
+	first := Request(ctx, "orders")
+	if second := Request(ctx, "orders"); second != first {
+		t.Error("repeated Request calls with the same context returned different loggers")
+	}
+	if other := Request(ctx, "payments"); other == first {
+		t.Error("different names returned the same logger")
+	}
+	// 新增字段后得到新的上下文，不能复用旧的子 logger
+	extended := ContextWithFields(ctx, zap.String("user", "u-1"))
+	if Request(extended, "orders") == first {
+		t.Error("context with more fields reused the cached child")
+	}
+	if Request(context.Background(), "orders") != Named("orders") {
+		t.Error("context without fields should return the named logger itself")
+	}
+
+	first.Info("placed")
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	e := entries[0]
+	if e.LoggerName != "orders" || e.ContextMap()["request_id"] != "r-1" {
+		t.Errorf("entry = %q %v", e.LoggerName, e.ContextMap())
+	}
+	if file := filepath.Base(e.Caller.File); file != "request_test.go" {
+		t.Errorf("caller = %s, want request_test.go", e.Caller.String())
+	}
+}
+
+func TestNamedCacheResetOnReinit(t *testing.T) {
+	ObserveGlobal(t, zapcore.DebugLevel)
+	before := Named("jobs")
+	if Named("jobs") != before {
+		t.Fatal("Named did not cache the child")
+	}
+
+	ObserveGlobal(t, zapcore.DebugLevel)
+	if Named("jobs") == before {
+		t.Error("Named returned a child of the previous global Logger")
+	}
+	count := 0
+	namedLoggers.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+	if count != 1 {
+		t.Errorf("cache holds %d children after re-initialization, want 1", count)
+	}
+}
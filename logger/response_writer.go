@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"net/http"
+	"time"
+)
+
+// statusRecorder captures the status code written by an HTTP handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func newStatusRecorder(w http.ResponseWriter) *statusRecorder {
+	return &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// serveAndMeasure runs next with a status recorder and returns the status it wrote and
+// how long it took; the logging middlewares share it
+func serveAndMeasure(next http.Handler, w http.ResponseWriter, r *http.Request) (status int, elapsed time.Duration) {
+	start := time.Now()
+	rec := newStatusRecorder(w)
+	next.ServeHTTP(rec, r)
+	return rec.status, time.Since(start)
+}
+
+// elapsedMillis converts d to fractional milliseconds for the latency fields
+func elapsedMillis(d time.Duration) float64 {
+	return float64(d.Nanoseconds()) / 1e6
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StartRetentionJob removes .log and .log.gz files under director whose modification time is
+// older than retentionDays days. Unlike lumberjack's MaxAge it also covers the files that are
+// no longer written to because their hour or day has passed. The first sweep runs immediately, then
+// every interval. The returned function stops the job; calling it more than once is safe.
+func StartRetentionJob(director string, retentionDays int, interval time.Duration) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			removeExpiredLogs(director, time.Duration(retentionDays)*24*time.Hour)
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-done
+		})
+	}
+}
+
+// removeExpiredLogs deletes the log files in director older than maxAge
+func removeExpiredLogs(director string, maxAge time.Duration) {
+	cutoff := time.Now().Add(-maxAge)
+	filepath.WalkDir(director, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !isLogFile(d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			Warnf("retention: failed to remove %s: %v", path, err)
+			return nil
+		}
+		Infof("retention: removed expired log file %s", path)
+		return nil
+	})
+}
+
+// isLogFile reports whether name is a plain or compressed log file, including lumberjack
+// backups of in-place compressed files ("info_2024010112.log-<timestamp>.gz")
+func isLogFile(name string) bool {
+	return strings.HasSuffix(name, ".log") || strings.HasSuffix(name, ".log.gz") ||
+		(strings.HasSuffix(name, ".gz") && strings.Contains(name, ".log-"))
+}
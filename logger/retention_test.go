@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// writeLogFile creates dir/name with the given age
+func writeLogFile(t *testing.T, dir, name string, age time.Duration) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("entry\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-age)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// waitFor polls cond until it holds or the timeout expires
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return true
+}
+
+func TestRetentionJob(t *testing.T) {
+	logs := ObserveGlobal(t, zapcore.DebugLevel)
+	dir := t.TempDir()
+	const day = 24 * time.Hour
+
+	expired := []string{
+		writeLogFile(t, dir, "info_2024010112.log", 10*day),
+		writeLogFile(t, dir, "error_2024010112.log.gz", 8*day),
+		writeLogFile(t, dir, "nested/warn_2024010112.log-2024-01-01T12-00-00.000.gz", 30*day),
+	}
+	kept := []string{
+		writeLogFile(t, dir, "info_2024013012.log", 2*day),
+		writeLogFile(t, dir, "warn.log", time.Hour),
+		writeLogFile(t, dir, "notes.txt", 30*day),
+	}
+
+	stop := StartRetentionJob(dir, 7, 20*time.Millisecond)
+	defer stop()
+
+	if !waitFor(t, 2*time.Second, func() bool {
+		for _, path := range expired {
+			if exists(path) {
+				return false
+			}
+		}
+		return true
+	}) {
+		t.Fatal("expired log files were not removed by the first run")
+	}
+	for _, path := range kept {
+		if !exists(path) {
+			t.Errorf("%s was removed, want it kept", path)
+		}
+	}
+
+	// a file that expires later is picked up by the next run
+	late := writeLogFile(t, dir, "debug_2024010112.log", 9*day)
+	if !waitFor(t, 2*time.Second, func() bool { return !exists(late) }) {
+		t.Fatal("expired log file created after start was not removed by a later run")
+	}
+
+	stop()
+	removed := logs.FilterMessageSnippet("retention: removed expired log file").All()
+	if len(removed) != len(expired)+1 {
+		t.Errorf("%d removal entries, want %d", len(removed), len(expired)+1)
+	}
+	for _, entry := range removed {
+		if entry.Level != zapcore.InfoLevel {
+			t.Errorf("removal logged at %v, want info", entry.Level)
+		}
+	}
+
+	// no runs after stop
+	after := writeLogFile(t, dir, "fatal_2024010112.log", 9*day)
+	time.Sleep(60 * time.Millisecond)
+	if !exists(after) {
+		t.Error("a file was removed after stop")
+	}
+}
+
+func TestIsLogFile(t *testing.T) {
+	tests := map[string]bool{
+		"info_2024010112.log":                            true,
+		"info_2024010112.log.gz":                         true,
+		"info_2024010112.log-2024-01-01T12-00-00.000.gz": true,
+		"info_2024010112.log-2024-01-01T12-00-00.000":    false,
+		"archive.tar.gz":                                 false,
+		"readme.txt":                                     false,
+	}
+	for name, want := range tests {
+		if got := isLogFile(name); got != want {
+			t.Errorf("isLogFile(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"os"
+	"sync"
+
+	"github.com/natefinch/lumberjack"
+)
+
+// WatchedWriter is a zapcore.WriteSyncer around a lumberjack.Logger that calls the registered
+// hooks whenever lumberjack starts a new file. lumberjack keeps Filename unchanged and renames
+// the old file, so a rotation is detected by the file at Filename no longer being the same
+// file (os.SameFile) as after the previous write.
+type WatchedWriter struct {
+	mu      sync.Mutex
+	lj      *lumberjack.Logger
+	current os.FileInfo
+	hooks   []func(newPath string)
+}
+
+// NewWatchedWriter wraps l
+func NewWatchedWriter(l *lumberjack.Logger) *WatchedWriter {
+	return &WatchedWriter{lj: l}
+}
+
+// AddRotationHook registers hook, called with the path of the newly opened file after a rotation
+func (w *WatchedWriter) AddRotationHook(hook func(newPath string)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.hooks = append(w.hooks, hook)
+}
+
+// Write writes p and fires the hooks if the write made lumberjack open a new file
+func (w *WatchedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	n, err := w.lj.Write(p)
+	rotated := w.checkRotation()
+	var hooks []func(string)
+	if rotated {
+		hooks = make([]func(string), len(w.hooks))
+		copy(hooks, w.hooks)
+	}
+	w.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook(w.lj.Filename)
+	}
+	return n, err
+}
+
+// Sync is a no-op; lumberjack writes directly to the file
+func (w *WatchedWriter) Sync() error {
+	return nil
+}
+
+// Close closes the underlying lumberjack.Logger
+func (w *WatchedWriter) Close() error {
+	return w.lj.Close()
+}
+
+// checkRotation reports whether the file at Filename changed since the last write; the first
+// write only records the file
+func (w *WatchedWriter) checkRotation() bool {
+	info, err := os.Stat(w.lj.Filename)
+	if err != nil {
+		return false
+	}
+	previous := w.current
+	w.current = info
+	return previous != nil && !os.SameFile(previous, info)
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/natefinch/lumberjack"
+)
+
+func TestWatchedWriterRotationHook(t *testing.T) {
+	dir := t.TempDir()
+	lj := &lumberjack.Logger{Filename: filepath.Join(dir, "info.log"), MaxSize: 1}
+	w := NewWatchedWriter(lj)
+	defer w.Close()
+
+	var rotations []string
+	w.AddRotationHook(func(newPath string) { rotations = append(rotations, newPath) })
+
+	chunk := bytes.Repeat([]byte("x"), 600*1024)
+	if _, err := w.Write(chunk); err != nil {
+		t.Fatal(err)
+	}
+	if len(rotations) != 0 {
+		t.Fatalf("hook fired for the first file: %v", rotations)
+	}
+	// the second chunk no longer fits in 1 MB, so lumberjack moves to a new file
+	if _, err := w.Write(chunk); err != nil {
+		t.Fatal(err)
+	}
+	if len(rotations) != 1 || rotations[0] != lj.Filename {
+		t.Fatalf("rotations = %v, want one for %s", rotations, lj.Filename)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "info*.log")); len(files) != 2 {
+		t.Errorf("files = %v, want the current file and one backup", files)
+	}
+
+	w.Write([]byte("small\n"))
+	if len(rotations) != 1 {
+		t.Errorf("hook fired without a rotation: %v", rotations)
+	}
+}
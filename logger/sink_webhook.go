@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultWebhookBatchSize is used when NewWebhookSyncer receives a non-positive batch size
+const defaultWebhookBatchSize = 100
+
+// WebhookSyncer is a zapcore.WriteSyncer that batches log entries and POSTs them
+// as a JSON array to an HTTP endpoint (Logtail, Datadog HTTP intake, ...).
+// Entries from a failed POST are kept for exactly one retry on the next flush.
+type WebhookSyncer struct {
+	url       string
+	batchSize int
+	headers   map[string]string
+	client    *http.Client
+
+	mu      sync.Mutex
+	pending [][]byte
+	retry   [][]byte
+
+	flushMu   sync.Mutex
+	flushCh   chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewWebhookSyncer creates a WebhookSyncer that flushes when batchSize entries are buffered
+// or every flushInterval (a non-positive interval disables timed flushes).
+func NewWebhookSyncer(url string, batchSize int, flushInterval time.Duration, headers map[string]string) *WebhookSyncer {
+	if batchSize <= 0 {
+		batchSize = defaultWebhookBatchSize
+	}
+	s := &WebhookSyncer{
+		url:       url,
+		batchSize: batchSize,
+		headers:   headers,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		flushCh:   make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go s.loop(flushInterval)
+	return s
+}
+
+// Write buffers a copy of p; zap reuses the buffer after Write returns
+func (s *WebhookSyncer) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	s.mu.Lock()
+	s.pending = append(s.pending, entry)
+	full := len(s.pending) >= s.batchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// Sync forces a flush of all buffered entries
+func (s *WebhookSyncer) Sync() error {
+	return s.flush()
+}
+
+// Close stops the background flusher and flushes the remaining entries
+func (s *WebhookSyncer) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+	return s.flush()
+}
+
+func (s *WebhookSyncer) loop(flushInterval time.Duration) {
+	defer close(s.done)
+	var tick <-chan time.Time
+	if flushInterval > 0 {
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-tick:
+			s.flush()
+		case <-s.flushCh:
+			s.flush()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// flush sends the retry entries together with the pending ones. On failure the pending
+// entries are kept for one more attempt and the already-retried entries are dropped.
+func (s *WebhookSyncer) flush() error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	batch, retry := s.pending, s.retry
+	s.pending, s.retry = nil, nil
+	s.mu.Unlock()
+
+	if len(batch) == 0 && len(retry) == 0 {
+		return nil
+	}
+	if err := s.post(append(retry, batch...)); err != nil {
+		s.mu.Lock()
+		s.retry = batch
+		s.mu.Unlock()
+		droppedEntries.Add(int64(len(retry)))
+		return err
+	}
+	return nil
+}
+
+func (s *WebhookSyncer) post(entries [][]byte) error {
+	items := make([]json.RawMessage, 0, len(entries))
+	for _, entry := range entries {
+		entry = bytes.TrimRight(entry, "\r\n")
+		if !json.Valid(entry) {
+			// console-encoded entries are sent as JSON strings
+			quoted, err := json.Marshal(string(entry))
+			if err != nil {
+				return err
+			}
+			entry = quoted
+		}
+		items = append(items, entry)
+	}
+	body, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
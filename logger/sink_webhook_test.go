@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// webhookRecorder is an HTTP intake that records every delivered entry and can be told to fail
+type webhookRecorder struct {
+	mu      sync.Mutex
+	entries []json.RawMessage
+	headers []http.Header
+	fail    int
+}
+
+func (rec *webhookRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.fail > 0 {
+		rec.fail--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var batch []json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	rec.entries = append(rec.entries, batch...)
+	rec.headers = append(rec.headers, r.Header.Clone())
+}
+
+func (rec *webhookRecorder) delivered() []json.RawMessage {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]json.RawMessage(nil), rec.entries...)
+}
+
+func TestWebhookSyncerDeliversAllOnSync(t *testing.T) {
+	rec := &webhookRecorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	s := NewWebhookSyncer(srv.URL, 10, 0, map[string]string{"DD-API-KEY": "secret"})
+	defer s.Close()
+	for i := 0; i < 25; i++ {
+		fmt.Fprintf(s, `{"msg":"entry %d"}`+"\n", i)
+	}
+	s.Write([]byte("console line\n"))
+	if err := s.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	entries := rec.delivered()
+	if len(entries) != 26 {
+		t.Fatalf("delivered %d entries, want 26", len(entries))
+	}
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		seen[string(e)] = true
+	}
+	for i := 0; i < 25; i++ {
+		if want := fmt.Sprintf(`{"msg":"entry %d"}`, i); !seen[want] {
+			t.Errorf("entry %s not delivered", want)
+		}
+	}
+	if !seen[`"console line"`] {
+		t.Error("console entry not delivered as a JSON string")
+	}
+	for _, h := range rec.headers {
+		if h.Get("DD-API-KEY") != "secret" || h.Get("Content-Type") != "application/json" {
+			t.Errorf("headers = %v", h)
+		}
+	}
+}
+
+func TestWebhookSyncerRetriesOnce(t *testing.T) {
+	rec := &webhookRecorder{fail: 1}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	s := NewWebhookSyncer(srv.URL, 100, 0, nil)
+	defer s.Close()
+	s.Write([]byte(`{"msg":"first"}`))
+	if err := s.Sync(); err == nil {
+		t.Fatal("Sync succeeded although the webhook failed")
+	}
+	s.Write([]byte(`{"msg":"second"}`))
+	if err := s.Sync(); err != nil {
+		t.Fatalf("Sync after recovery: %v", err)
+	}
+	if got := rec.delivered(); len(got) != 2 || string(got[0]) != `{"msg":"first"}` {
+		t.Fatalf("delivered %s, want first entry retried before second", got)
+	}
+}
+
+func TestWebhookSyncerDropsAfterSecondFailure(t *testing.T) {
+	rec := &webhookRecorder{fail: 2}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	s := NewWebhookSyncer(srv.URL, 100, 0, nil)
+	defer s.Close()
+	before := droppedEntries.Load()
+	s.Write([]byte(`{"msg":"lost"}`))
+	s.Sync()
+	s.Sync()
+	if err := s.Sync(); err != nil {
+		t.Fatalf("third Sync: %v", err)
+	}
+	if got := rec.delivered(); len(got) != 0 {
+		t.Errorf("delivered %s, want the entry dropped after its retry failed", got)
+	}
+	if dropped := droppedEntries.Load() - before; dropped != 1 {
+		t.Errorf("drop count increased by %d, want 1", dropped)
+	}
+}
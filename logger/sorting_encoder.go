@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// SortingEncoder buffers the fields added via With instead of encoding them immediately, and
+// writes them together with the entry's fields in a fixed order: the keys in order first, in
+// that order, then all other keys alphabetically. Fields after a namespace keep their
+// relative order, since moving them would change which namespace they belong to.
+type SortingEncoder struct {
+	base   zapcore.Encoder
+	rank   map[string]int
+	fields []zapcore.Field
+}
+
+// NewSortingEncoder wraps base; base must not have fields of its own
+func NewSortingEncoder(base zapcore.Encoder, order []string) *SortingEncoder {
+	rank := make(map[string]int, len(order))
+	for i, key := range order {
+		if _, ok := rank[key]; !ok {
+			rank[key] = i
+		}
+	}
+	return &SortingEncoder{base: base, rank: rank}
+}
+
+func (e *SortingEncoder) Clone() zapcore.Encoder {
+	fields := make([]zapcore.Field, len(e.fields), len(e.fields)+4)
+	copy(fields, e.fields)
+	return &SortingEncoder{base: e.base, rank: e.rank, fields: fields}
+}
+
+func (e *SortingEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	all := make([]zapcore.Field, 0, len(e.fields)+len(fields))
+	all = append(all, e.fields...)
+	all = append(all, fields...)
+
+	sortable := len(all)
+	for i, f := range all {
+		if f.Type == zapcore.NamespaceType {
+			sortable = i
+			break
+		}
+	}
+	head := all[:sortable]
+	sort.SliceStable(head, func(i, j int) bool {
+		ri, iok := e.rank[head[i].Key]
+		rj, jok := e.rank[head[j].Key]
+		switch {
+		case iok && jok:
+			return ri < rj
+		case iok != jok:
+			return iok
+		default:
+			return head[i].Key < head[j].Key
+		}
+	})
+	return e.base.EncodeEntry(ent, all)
+}
+
+func (e *SortingEncoder) add(f zapcore.Field) {
+	e.fields = append(e.fields, f)
+}
+
+func (e *SortingEncoder) AddArray(key string, v zapcore.ArrayMarshaler) error {
+	e.add(zap.Array(key, v))
+	return nil
+}
+
+func (e *SortingEncoder) AddObject(key string, v zapcore.ObjectMarshaler) error {
+	e.add(zap.Object(key, v))
+	return nil
+}
+
+func (e *SortingEncoder) AddBinary(key string, v []byte)          { e.add(zap.Binary(key, v)) }
+func (e *SortingEncoder) AddByteString(key string, v []byte)      { e.add(zap.ByteString(key, v)) }
+func (e *SortingEncoder) AddBool(key string, v bool)              { e.add(zap.Bool(key, v)) }
+func (e *SortingEncoder) AddComplex128(key string, v complex128)  { e.add(zap.Complex128(key, v)) }
+func (e *SortingEncoder) AddComplex64(key string, v complex64)    { e.add(zap.Complex64(key, v)) }
+func (e *SortingEncoder) AddDuration(key string, v time.Duration) { e.add(zap.Duration(key, v)) }
+func (e *SortingEncoder) AddFloat64(key string, v float64)        { e.add(zap.Float64(key, v)) }
+func (e *SortingEncoder) AddFloat32(key string, v float32)        { e.add(zap.Float32(key, v)) }
+func (e *SortingEncoder) AddInt(key string, v int)                { e.add(zap.Int(key, v)) }
+func (e *SortingEncoder) AddInt64(key string, v int64)            { e.add(zap.Int64(key, v)) }
+func (e *SortingEncoder) AddInt32(key string, v int32)            { e.add(zap.Int32(key, v)) }
+func (e *SortingEncoder) AddInt16(key string, v int16)            { e.add(zap.Int16(key, v)) }
+func (e *SortingEncoder) AddInt8(key string, v int8)              { e.add(zap.Int8(key, v)) }
+func (e *SortingEncoder) AddString(key, v string)                 { e.add(zap.String(key, v)) }
+func (e *SortingEncoder) AddTime(key string, v time.Time)         { e.add(zap.Time(key, v)) }
+func (e *SortingEncoder) AddUint(key string, v uint)              { e.add(zap.Uint(key, v)) }
+func (e *SortingEncoder) AddUint64(key string, v uint64)          { e.add(zap.Uint64(key, v)) }
+func (e *SortingEncoder) AddUint32(key string, v uint32)          { e.add(zap.Uint32(key, v)) }
+func (e *SortingEncoder) AddUint16(key string, v uint16)          { e.add(zap.Uint16(key, v)) }
+func (e *SortingEncoder) AddUint8(key string, v uint8)            { e.add(zap.Uint8(key, v)) }
+func (e *SortingEncoder) AddUintptr(key string, v uintptr)        { e.add(zap.Uintptr(key, v)) }
+func (e *SortingEncoder) OpenNamespace(key string)                { e.add(zap.Namespace(key)) }
+
+func (e *SortingEncoder) AddReflected(key string, v interface{}) error {
+	e.add(zap.Reflect(key, v))
+	return nil
+}
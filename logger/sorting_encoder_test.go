@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// fieldsSuffix returns the encoded fields after the message key
+func fieldsSuffix(t *testing.T, line string) string {
+	t.Helper()
+	i := strings.Index(line, `"msg":"entry",`)
+	if i < 0 {
+		t.Fatalf("no message in %q", line)
+	}
+	return strings.TrimSpace(line[i+len(`"msg":"entry",`):])
+}
+
+func TestSortingEncoderFieldOrder(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.FieldOrder = []string{"request_id", "user", "path"}
+	var buf bytes.Buffer
+	l, err := NewWithConfig(cfg, WithWriteSyncer(zapcore.AddSync(&buf)))
+	if err != nil {
+		t.Fatalf("NewWithConfig: %v", err)
+	}
+	want := `"request_id":"r1","user":"alice","path":"/orders","alpha":1,"zeta":2}`
+
+	// the same fields supplied in different orders through With and the log call
+	ctx := l.With(zap.String("path", "/orders"), zap.Int("zeta", 2))
+	ctx.Info("entry", zap.Int("alpha", 1), zap.String("user", "alice"), zap.String("request_id", "r1"))
+	l.With(zap.String("request_id", "r1")).With(zap.Int("alpha", 1)).Info("entry",
+		zap.Int("zeta", 2), zap.String("path", "/orders"), zap.String("user", "alice"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines: %q", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		if got := fieldsSuffix(t, line); got != want {
+			t.Errorf("line %d fields = %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestSortingEncoderNamespace(t *testing.T) {
+	enc := NewSortingEncoder(zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), []string{"b"})
+	clone := enc.Clone()
+	clone.AddString("z", "1")
+	buf, err := clone.EncodeEntry(zapcore.Entry{Message: "entry"}, []zapcore.Field{
+		zap.String("b", "2"),
+		zap.Namespace("ns"),
+		zap.String("y", "3"),
+		zap.String("a", "4"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// fields after the namespace keep their order
+	if got, want := fieldsSuffix(t, buf.String()), `"b":"2","z":"1","ns":{"y":"3","a":"4"}}`; got != want {
+		t.Errorf("fields = %s, want %s", got, want)
+	}
+
+	// fields added to the clone do not leak into the original
+	buf, _ = enc.EncodeEntry(zapcore.Entry{Message: "entry"}, nil)
+	if strings.Contains(buf.String(), `"z"`) {
+		t.Errorf("original encoder output = %s", buf.String())
+	}
+}
@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"log"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewStdLogger returns a *log.Logger that forwards every line to the global Logger at the given level.
+// Use it for third-party libraries that only accept the standard library logger.
+func NewStdLogger(level zapcore.Level) *log.Logger {
+	base := directLogger()
+	stdLogger, err := zap.NewStdLogAt(base, level)
+	if err != nil {
+		// NewStdLogAt only fails for unknown levels; fall back to Info
+		return zap.NewStdLog(base)
+	}
+	return stdLogger
+}
+
+// ReplaceStdLogger makes the global Logger the zap global logger and redirects the output
+// of the standard library's package-level log functions to it at the given level.
+// The returned function restores the previous state.
+func ReplaceStdLogger(level zapcore.Level) (func(), error) {
+	base := directLogger()
+	undoGlobals := zap.ReplaceGlobals(base)
+	undoRedirect, err := zap.RedirectStdLogAt(base, level)
+	if err != nil {
+		undoGlobals()
+		return nil, err
+	}
+	return func() {
+		undoRedirect()
+		undoGlobals()
+	}, nil
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"log"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewStdLogger(t *testing.T) {
+	logs := ObserveGlobal(t, zapcore.DebugLevel)
+
+	NewStdLogger(zapcore.WarnLevel).Println("disk almost full")
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	if entries[0].Level != zapcore.WarnLevel || entries[0].Message != "disk almost full" {
+		t.Errorf("entry = %v %q, want warn %q", entries[0].Level, entries[0].Message, "disk almost full")
+	}
+	if file := filepath.Base(entries[0].Caller.File); file != "stdlib_test.go" {
+		t.Errorf("caller = %s, want stdlib_test.go", entries[0].Caller.String())
+	}
+}
+
+func TestReplaceStdLogger(t *testing.T) {
+	logs := ObserveGlobal(t, zapcore.DebugLevel)
+	flags, prefix := log.Flags(), log.Prefix()
+
+	undo, err := ReplaceStdLogger(zapcore.ErrorLevel)
+	if err != nil {
+		t.Fatalf("ReplaceStdLogger: %v", err)
+	}
+	log.Println("legacy failure")
+	zap.L().Info("through zap global")
+	undo()
+	log.SetFlags(flags)
+	log.SetPrefix(prefix)
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].Level != zapcore.ErrorLevel || entries[0].Message != "legacy failure" {
+		t.Errorf("std log entry = %v %q", entries[0].Level, entries[0].Message)
+	}
+	for _, e := range entries {
+		if file := filepath.Base(e.Caller.File); file != "stdlib_test.go" {
+			t.Errorf("caller of %q = %s, want stdlib_test.go", e.Message, e.Caller.String())
+		}
+	}
+	if zap.L() == Logger {
+		t.Error("zap global not restored")
+	}
+}
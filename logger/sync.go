@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// signalSyncOnce makes sure only one signal handler is installed however often the logger is initialized
+var signalSyncOnce sync.Once
+
+// StartPeriodicSync starts a goroutine that calls Logger.Sync every interval. The returned
+// function stops the goroutine and performs a final sync; calling it more than once is safe.
+func StartPeriodicSync(interval time.Duration) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				syncLogger()
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-done
+			syncLogger()
+		})
+	}
+}
+
+// startSignalSync syncs the logger when the process receives SIGINT or SIGTERM, then
+// re-raises the signal so the process keeps its normal exit behaviour
+func startSignalSync() {
+	signalSyncOnce.Do(func() {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			sig := <-ch
+			syncLogger()
+			signal.Stop(ch)
+			if p, err := os.FindProcess(os.Getpid()); err == nil {
+				p.Signal(sig)
+			}
+		}()
+	})
+}
+
+// syncLogger flushes the global Logger if it has been initialized
+func syncLogger() {
+	if Logger != nil {
+		_ = Logger.Sync()
+	}
+}
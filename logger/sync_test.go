@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingSyncer counts Sync calls and discards writes
+type countingSyncer struct {
+	syncs int32
+}
+
+func (s *countingSyncer) Write(p []byte) (int, error) { return len(p), nil }
+
+func (s *countingSyncer) Sync() error {
+	atomic.AddInt32(&s.syncs, 1)
+	return nil
+}
+
+func (s *countingSyncer) count() int32 { return atomic.LoadInt32(&s.syncs) }
+
+func TestStartPeriodicSync(t *testing.T) {
+	restoreGlobals(t)
+	ws := &countingSyncer{}
+	l, err := NewWithConfig(testConfig(t), WithWriteSyncer(ws))
+	if err != nil {
+		t.Fatalf("NewWithConfig: %v", err)
+	}
+	setGlobal(l)
+
+	const interval = 20 * time.Millisecond
+	stop := StartPeriodicSync(interval)
+	time.Sleep(5*interval + interval/2)
+	ticks := ws.count()
+	if ticks < 3 || ticks > 6 {
+		stop()
+		t.Fatalf("%d syncs after 5.5 intervals, want about 5", ticks)
+	}
+
+	stop()
+	final := ws.count()
+	if final <= ticks {
+		t.Errorf("stop did not perform a final sync: %d syncs before, %d after", ticks, final)
+	}
+
+	stop()
+	time.Sleep(3 * interval)
+	if n := ws.count(); n != final {
+		t.Errorf("syncs continued after stop: %d, want %d", n, final)
+	}
+}
+
+func TestSyncLoggerWithoutInit(t *testing.T) {
+	restoreGlobals(t)
+	Logger = nil
+	// must not panic before the logger is initialized
+	syncLogger()
+}
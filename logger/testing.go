@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// SilenceBelow replaces the global logger for the duration of the test with one that only
+// writes entries at level or above. Suppressed entries are buffered and written to t.Log if
+// the test has failed by the time it is cleaned up; the original logger is then restored.
+func SilenceBelow(t testing.TB, level zapcore.Level) {
+	t.Helper()
+	EnsureInitialized()
+	original, originalSugared := Logger, SugaredLogger
+
+	suppressedCore, suppressed := observer.New(zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l < level
+	}))
+	Logger = original.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		increased, err := zapcore.NewIncreaseLevelCore(core, level)
+		if err != nil {
+			// level is below the core's own level, nothing is suppressed by the filter
+			increased = core
+		}
+		return zapcore.NewTee(increased, suppressedCore)
+	}))
+	SugaredLogger = Logger.Sugar()
+
+	t.Cleanup(func() {
+		Logger, SugaredLogger = original, originalSugared
+		if !t.Failed() {
+			return
+		}
+		for _, entry := range suppressed.AllUntimed() {
+			t.Logf("[suppressed %s] %s %v", entry.Level.CapitalString(), entry.Message, entry.ContextMap())
+		}
+	})
+}
+
+// ObserveGlobal replaces the global logger for the duration of the test with one recording the
+// entries enabled by level, and returns them. The original logger, or the uninitialized state,
+// is restored on cleanup.
+func ObserveGlobal(t testing.TB, level zapcore.LevelEnabler) *observer.ObservedLogs {
+	t.Helper()
+	original, originalSugared := Logger, SugaredLogger
+	core, logs := observer.New(level)
+	setGlobal(zap.New(core, zap.AddCaller()))
+	t.Cleanup(func() {
+		Logger, SugaredLogger = original, originalSugared
+		resetNamedLoggers()
+		if original == nil {
+			// the observed logger may have satisfied EnsureInitialized; let it initialize again
+			lazyInitOnce = sync.Once{}
+		}
+	})
+	return logs
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// fakeTB lets a test decide whether it failed and runs its cleanups on demand
+type fakeTB struct {
+	testing.TB
+	failed   bool
+	logs     []string
+	cleanups []func()
+}
+
+func (f *fakeTB) Helper()           {}
+func (f *fakeTB) Failed() bool      { return f.failed }
+func (f *fakeTB) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+
+func (f *fakeTB) Logf(format string, args ...interface{}) {
+	f.logs = append(f.logs, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) runCleanups() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestSilenceBelow(t *testing.T) {
+	for _, failed := range []bool{false, true} {
+		t.Run(fmt.Sprintf("failed=%v", failed), func(t *testing.T) {
+			logs := ObserveGlobal(t, zapcore.DebugLevel)
+			original := Logger
+			tb := &fakeTB{}
+
+			SilenceBelow(tb, zapcore.InfoLevel)
+			Debug("debug details")
+			Infof("visible %d", 1)
+
+			if entries := logs.All(); len(entries) != 1 || entries[0].Message != "visible 1" {
+				t.Fatalf("written entries = %v, want only the info entry", entries)
+			}
+
+			tb.failed = failed
+			tb.runCleanups()
+			if Logger != original {
+				t.Error("the original logger was not restored")
+			}
+			if !failed {
+				if len(tb.logs) != 0 {
+					t.Errorf("suppressed entries logged for a passing test: %q", tb.logs)
+				}
+				return
+			}
+			if len(tb.logs) != 1 || !strings.Contains(tb.logs[0], "[suppressed DEBUG] debug details") {
+				t.Errorf("t.Log output = %q, want the suppressed debug entry", tb.logs)
+			}
+		})
+	}
+}
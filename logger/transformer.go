@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// FieldTransformer rewrites a field before it is encoded, e.g. to mask or shorten a value
+type FieldTransformer func(zap.Field) zap.Field
+
+// fieldTransformers are applied by the cores built in setupCores
+var fieldTransformers struct {
+	sync.Mutex
+	list []FieldTransformer
+}
+
+// RegisterFieldTransformer adds transformer to the chain applied to every field of the loggers
+// created by subsequent InitLogger calls. Transformers run in registration order.
+func RegisterFieldTransformer(transformer FieldTransformer) {
+	fieldTransformers.Lock()
+	defer fieldTransformers.Unlock()
+	fieldTransformers.list = append(fieldTransformers.list, transformer)
+}
+
+// registeredFieldTransformers returns a copy of the registered transformers
+func registeredFieldTransformers() []FieldTransformer {
+	fieldTransformers.Lock()
+	defer fieldTransformers.Unlock()
+	list := make([]FieldTransformer, len(fieldTransformers.list))
+	copy(list, fieldTransformers.list)
+	return list
+}
+
+type transformerCore struct {
+	zapcore.Core
+	transformers []FieldTransformer
+}
+
+// TransformerCore wraps base so that every field, including those added with With, passes
+// through transformers in order before reaching base
+func TransformerCore(base zapcore.Core, transformers ...FieldTransformer) zapcore.Core {
+	return &transformerCore{Core: base, transformers: transformers}
+}
+
+func (c *transformerCore) With(fields []zapcore.Field) zapcore.Core {
+	return &transformerCore{Core: c.Core.With(c.transform(fields)), transformers: c.transformers}
+}
+
+func (c *transformerCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *transformerCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.transform(fields))
+}
+
+// transform applies the transformers to a copy of fields
+func (c *transformerCore) transform(fields []zapcore.Field) []zapcore.Field {
+	out := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		for _, t := range c.transformers {
+			f = t(f)
+		}
+		out[i] = f
+	}
+	return out
+}
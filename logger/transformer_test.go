@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func maskPassword(f zap.Field) zap.Field {
+	if f.Key == "password" {
+		return zap.String("password", "***")
+	}
+	return f
+}
+
+// registerTransformer registers transformer for the duration of the test
+func registerTransformer(t *testing.T, transformer FieldTransformer) {
+	t.Helper()
+	original := registeredFieldTransformers()
+	RegisterFieldTransformer(transformer)
+	t.Cleanup(func() {
+		fieldTransformers.Lock()
+		fieldTransformers.list = original
+		fieldTransformers.Unlock()
+	})
+}
+
+func TestTransformerCore(t *testing.T) {
+	base, logs := observer.New(zapcore.DebugLevel)
+	upper := func(f zap.Field) zap.Field {
+		if f.Type == zapcore.StringType {
+			f.String = strings.ToUpper(f.String)
+		}
+		return f
+	}
+	l := zap.New(TransformerCore(base, maskPassword, upper)).With(zap.String("password", "with"))
+	l.Info("login", zap.String("password", "secret"), zap.String("user", "alice"))
+
+	fields := logs.All()[0].ContextMap()
+	// transformers run in order, so the mask is uppercased too
+	if fields["password"] != "***" || fields["user"] != "ALICE" {
+		t.Errorf("fields = %v", fields)
+	}
+	if n := len(logs.All()[0].Context); n != 3 {
+		t.Errorf("got %d fields, want 3", n)
+	}
+}
+
+func TestRegisterFieldTransformerAppliedByInitLogger(t *testing.T) {
+	restoreGlobals(t)
+	registerTransformer(t, maskPassword)
+	cfg := testConfig(t)
+
+	if err := InitLoggerFromConfig(&cfg); err != nil {
+		t.Fatalf("InitLoggerFromConfig: %v", err)
+	}
+	Logger.Info("login", zap.String("password", "hunter2"))
+	if err := Logger.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(cfg.Director, "info_*.log"))
+	if len(matches) != 1 {
+		t.Fatalf("info log files = %v, want one", matches)
+	}
+	data, _ := os.ReadFile(matches[0])
+	if !strings.Contains(string(data), `"password":"***"`) || strings.Contains(string(data), "hunter2") {
+		t.Errorf("info log = %q", data)
+	}
+}
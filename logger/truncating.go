@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"unicode/utf8"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// truncatedSuffix is appended to messages cut by TruncatingCore
+const truncatedSuffix = "...[truncated]"
+
+type truncatingCore struct {
+	zapcore.Core
+	maxLen int
+}
+
+// TruncatingCore wraps base so that entry messages longer than maxLen bytes are cut at a
+// rune boundary and suffixed with "...[truncated]"
+func TruncatingCore(base zapcore.Core, maxLen int) zapcore.Core {
+	return &truncatingCore{Core: base, maxLen: maxLen}
+}
+
+func (c *truncatingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &truncatingCore{Core: c.Core.With(fields), maxLen: c.maxLen}
+}
+
+func (c *truncatingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *truncatingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = truncateMessage(ent.Message, c.maxLen)
+	return c.Core.Write(ent, fields)
+}
+
+// truncateMessage cuts msg to at most maxLen bytes without splitting a multi-byte character
+func truncateMessage(msg string, maxLen int) string {
+	if maxLen <= 0 || len(msg) <= maxLen {
+		return msg
+	}
+	cut := maxLen
+	for cut > 0 && !utf8.RuneStart(msg[cut]) {
+		cut--
+	}
+	return msg[:cut] + truncatedSuffix
+}
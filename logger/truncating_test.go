@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestTruncateMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		msg    string
+		maxLen int
+		want   string
+	}{
+		{"exactly maxLen", "abcdef", 6, "abcdef"},
+		{"shorter", "abc", 6, "abc"},
+		{"maxLen+1", "abcdefg", 6, "abcdef" + truncatedSuffix},
+		{"unlimited", "abcdefg", 0, "abcdefg"},
+		// "日志" is two 3-byte runes; cutting at 4 bytes must not keep half of the second
+		{"multi-byte boundary", "日志内容", 4, "日" + truncatedSuffix},
+		{"multi-byte exact rune end", "日志内容", 6, "日志" + truncatedSuffix},
+		{"multi-byte inside first rune", "日志", 2, truncatedSuffix},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncateMessage(tt.msg, tt.maxLen)
+			if got != tt.want {
+				t.Errorf("truncateMessage(%q, %d) = %q, want %q", tt.msg, tt.maxLen, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncateMessage(%q, %d) = %q splits a character", tt.msg, tt.maxLen, got)
+			}
+		})
+	}
+}
+
+func TestTruncatingCore(t *testing.T) {
+	base, logs := observer.New(zapcore.DebugLevel)
+	l := zap.New(TruncatingCore(base, 8)).With(zap.String("k", strings.Repeat("v", 20)))
+
+	l.Info(strings.Repeat("x", 8))
+	l.Info(strings.Repeat("x", 9))
+
+	entries := logs.All()
+	if entries[0].Message != strings.Repeat("x", 8) {
+		t.Errorf("message of exactly maxLen = %q, want unchanged", entries[0].Message)
+	}
+	if entries[1].Message != strings.Repeat("x", 8)+truncatedSuffix {
+		t.Errorf("message of maxLen+1 = %q, want truncated", entries[1].Message)
+	}
+	// fields are left alone
+	if v := entries[1].ContextMap()["k"]; v != strings.Repeat("v", 20) {
+		t.Errorf("field k = %v, want untouched", v)
+	}
+}
+
+func TestMaxMessageLengthConfig(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.MaxMessageLength = 5
+	var buf bytes.Buffer
+	l, err := NewWithConfig(cfg, WithWriteSyncer(zapcore.AddSync(&buf)))
+	if err != nil {
+		t.Fatalf("NewWithConfig: %v", err)
+	}
+	l.Info("hello world")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("decode %q: %v", buf.String(), err)
+	}
+	if entry["msg"] != "hello"+truncatedSuffix {
+		t.Errorf("msg = %v, want truncated to 5 bytes", entry["msg"])
+	}
+}
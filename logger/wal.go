@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"github.com/natefinch/lumberjack"
+)
+
+// SyncingWriter calls syncFn after every batchSize writes so that log entries reach the disk
+// before a crash can lose them. A batchSize of 1 syncs after every write.
+type SyncingWriter struct {
+	mu        sync.Mutex
+	w         io.Writer
+	syncFn    func() error
+	batchSize int
+	pending   int
+}
+
+// NewSyncingWriter wraps w; a non-positive batchSize is treated as 1
+func NewSyncingWriter(w io.Writer, syncFn func() error, batchSize int) *SyncingWriter {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	return &SyncingWriter{w: w, syncFn: syncFn, batchSize: batchSize}
+}
+
+// Write writes p and syncs once batchSize writes have accumulated
+func (s *SyncingWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, err := s.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	s.pending++
+	if s.pending >= s.batchSize {
+		s.pending = 0
+		if err := s.syncFn(); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Sync syncs any writes not yet synced
+func (s *SyncingWriter) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = 0
+	return s.syncFn()
+}
+
+// syncedFile writes to a lumberjack.Logger and keeps its own descriptor of the current file
+// open for fsync, since lumberjack does not expose its *os.File. The descriptor is dropped
+// when lumberjack switches to a new file: on Rotate, and on a Write that exceeds the size
+// limit, mirroring the check lumberjack makes. The next Sync opens the new file.
+type syncedFile struct {
+	mu   sync.Mutex
+	lj   *lumberjack.Logger
+	file *os.File
+	size int64 // bytes in file, tracked while it is open
+}
+
+func newSyncedFile(l *lumberjack.Logger) *syncedFile {
+	return &syncedFile{lj: l}
+}
+
+// Write writes p to lumberjack
+func (s *syncedFile) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file != nil && s.size+int64(len(p)) > lumberjackMaxBytes(s.lj) {
+		// lumberjack rotates before this write
+		s.closeFile()
+	}
+	n, err := s.lj.Write(p)
+	s.size += int64(n)
+	return n, err
+}
+
+// Rotate rotates the lumberjack file
+func (s *syncedFile) Rotate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeFile()
+	return s.lj.Rotate()
+}
+
+// Sync fsyncs the current file, opening it on the first call after a rotation
+func (s *syncedFile) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		f, err := os.OpenFile(s.lj.Filename, os.O_WRONLY, 0)
+		if os.IsNotExist(err) {
+			// nothing has been written yet
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return err
+		}
+		s.file, s.size = f, info.Size()
+	}
+	return s.file.Sync()
+}
+
+// Close closes the descriptor and the lumberjack file
+func (s *syncedFile) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeFile()
+	return s.lj.Close()
+}
+
+func (s *syncedFile) closeFile() {
+	if s.file != nil {
+		s.file.Close()
+		s.file = nil
+	}
+}
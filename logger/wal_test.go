@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/natefinch/lumberjack"
+)
+
+func TestSyncingWriterBatchSize(t *testing.T) {
+	tests := []struct {
+		batchSize int
+		writes    int
+		want      int
+	}{
+		{0, 3, 3},
+		{1, 3, 3},
+		{3, 7, 2},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		syncs := 0
+		w := NewSyncingWriter(&buf, func() error { syncs++; return nil }, tt.batchSize)
+		for i := 0; i < tt.writes; i++ {
+			if _, err := w.Write([]byte("entry\n")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if syncs != tt.want {
+			t.Errorf("batchSize %d: %d syncs after %d writes, want %d", tt.batchSize, syncs, tt.writes, tt.want)
+		}
+		if buf.Len() != tt.writes*len("entry\n") {
+			t.Errorf("batchSize %d: wrote %d bytes", tt.batchSize, buf.Len())
+		}
+	}
+}
+
+// sameFile reports whether f is the file currently at path
+func sameFile(t *testing.T, f *os.File, path string) bool {
+	t.Helper()
+	held, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return os.SameFile(held, current)
+}
+
+func TestSyncedFileKeepsDescriptorOpen(t *testing.T) {
+	lj := &lumberjack.Logger{Filename: filepath.Join(t.TempDir(), "info.log"), MaxSize: 1}
+	f := newSyncedFile(lj)
+	defer f.Close()
+
+	if err := f.Sync(); err != nil {
+		t.Fatalf("Sync before any write: %v", err)
+	}
+	if _, err := f.Write([]byte("first\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	held := f.file
+	if held == nil || !sameFile(t, held, lj.Filename) {
+		t.Fatal("Sync did not open the current file")
+	}
+	f.Write([]byte("second\n"))
+	if err := f.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if f.file != held {
+		t.Error("the file was opened again between syncs")
+	}
+
+	// a write past MaxSize makes lumberjack move to a new file
+	f.Write(bytes.Repeat([]byte("x"), megabyte))
+	if err := f.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if f.file == held || !sameFile(t, f.file, lj.Filename) {
+		t.Error("descriptor does not follow the size based rotation")
+	}
+
+	held = f.file
+	if err := f.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte("after rotate\n"))
+	if err := f.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if f.file == held || !sameFile(t, f.file, lj.Filename) {
+		t.Error("descriptor does not follow Rotate")
+	}
+}
+
+func TestWALSyncConfig(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.WALSync = true
+	cfg.WALBatchSize = 2
+	ws, err := getLogWriter(&cfg, "info")
+	if err != nil {
+		t.Fatalf("getLogWriter: %v", err)
+	}
+	w, ok := ws.(*SyncingWriter)
+	if !ok {
+		t.Fatalf("getLogWriter returned %T, want *SyncingWriter", ws)
+	}
+	if w.batchSize != 2 {
+		t.Errorf("batchSize = %d, want 2", w.batchSize)
+	}
+	if _, err := w.Write([]byte("entry\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	matches, _ := filepath.Glob(filepath.Join(cfg.Director, "info_*.log"))
+	if len(matches) != 1 {
+		t.Fatalf("log files = %v, want one", matches)
+	}
+	if data, _ := os.ReadFile(matches[0]); !strings.Contains(string(data), "entry") {
+		t.Errorf("log = %q", data)
+	}
+}
@@ -0,0 +1,220 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// zeroAllocBufferPool provides the line buffers returned by ZeroAllocConsoleEncoder
+var zeroAllocBufferPool = buffer.NewPool()
+
+// consoleArrayPool recycles the encoders that write the entry metadata (time, level, name, caller)
+var consoleArrayPool = sync.Pool{
+	New: func() interface{} {
+		return &consoleArrayEncoder{}
+	},
+}
+
+// ZeroAllocConsoleEncoder produces the same output as zapcore.NewConsoleEncoder without the
+// per-element fmt.Fprint of the zap implementation: entry metadata is appended straight to a
+// pooled buffer and the structured context is rendered by a console encoder whose entry keys
+// are disabled, so the JSON part is byte-for-byte identical.
+type ZeroAllocConsoleEncoder struct {
+	// Encoder holds the accumulated context fields; its entry keys are all disabled
+	zapcore.Encoder
+	cfg zapcore.EncoderConfig
+}
+
+// NewZeroAllocConsoleEncoder creates a ZeroAllocConsoleEncoder from cfg
+func NewZeroAllocConsoleEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	if cfg.ConsoleSeparator == "" {
+		cfg.ConsoleSeparator = "\t"
+	}
+	if cfg.SkipLineEnding {
+		cfg.LineEnding = ""
+	} else if cfg.LineEnding == "" {
+		cfg.LineEnding = zapcore.DefaultLineEnding
+	}
+
+	contextCfg := cfg
+	contextCfg.TimeKey = ""
+	contextCfg.LevelKey = ""
+	contextCfg.NameKey = ""
+	contextCfg.CallerKey = ""
+	contextCfg.FunctionKey = ""
+	contextCfg.MessageKey = ""
+	contextCfg.StacktraceKey = ""
+	contextCfg.SkipLineEnding = true
+	return &ZeroAllocConsoleEncoder{Encoder: zapcore.NewConsoleEncoder(contextCfg), cfg: cfg}
+}
+
+// Clone implements zapcore.Encoder
+func (e *ZeroAllocConsoleEncoder) Clone() zapcore.Encoder {
+	return &ZeroAllocConsoleEncoder{Encoder: e.Encoder.Clone(), cfg: e.cfg}
+}
+
+// EncodeEntry implements zapcore.Encoder
+func (e *ZeroAllocConsoleEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line := zeroAllocBufferPool.Get()
+
+	arr := consoleArrayPool.Get().(*consoleArrayEncoder)
+	arr.line, arr.separator, arr.count = line, e.cfg.ConsoleSeparator, 0
+	if e.cfg.TimeKey != "" && e.cfg.EncodeTime != nil && !ent.Time.IsZero() {
+		e.cfg.EncodeTime(ent.Time, arr)
+	}
+	if e.cfg.LevelKey != "" && e.cfg.EncodeLevel != nil {
+		e.cfg.EncodeLevel(ent.Level, arr)
+	}
+	if ent.LoggerName != "" && e.cfg.NameKey != "" {
+		nameEncoder := e.cfg.EncodeName
+		if nameEncoder == nil {
+			nameEncoder = zapcore.FullNameEncoder
+		}
+		nameEncoder(ent.LoggerName, arr)
+	}
+	if ent.Caller.Defined {
+		if e.cfg.CallerKey != "" && e.cfg.EncodeCaller != nil {
+			e.cfg.EncodeCaller(ent.Caller, arr)
+		}
+		if e.cfg.FunctionKey != "" {
+			arr.AppendString(ent.Caller.Function)
+		}
+	}
+	arr.line = nil
+	consoleArrayPool.Put(arr)
+
+	if e.cfg.MessageKey != "" {
+		e.addSeparatorIfNecessary(line)
+		line.AppendString(ent.Message)
+	}
+
+	// only the context is enabled in the inner encoder, so it renders "{...}" or nothing
+	context, err := e.Encoder.EncodeEntry(zapcore.Entry{}, fields)
+	if err != nil {
+		line.Free()
+		return nil, err
+	}
+	if context.Len() > 0 {
+		e.addSeparatorIfNecessary(line)
+		line.Write(context.Bytes())
+	}
+	context.Free()
+
+	if ent.Stack != "" && e.cfg.StacktraceKey != "" {
+		line.AppendByte('\n')
+		line.AppendString(ent.Stack)
+	}
+	line.AppendString(e.cfg.LineEnding)
+	return line, nil
+}
+
+func (e *ZeroAllocConsoleEncoder) addSeparatorIfNecessary(line *buffer.Buffer) {
+	if line.Len() > 0 {
+		line.AppendString(e.cfg.ConsoleSeparator)
+	}
+}
+
+// trimmedCallerAppender is implemented by encoders that can write caller.TrimmedPath() without
+// building the intermediate string; ZapConfig.CallerEncoder uses it when available
+type trimmedCallerAppender interface {
+	appendTrimmedCaller(caller zapcore.EntryCaller)
+}
+
+// consoleArrayEncoder is a zapcore.PrimitiveArrayEncoder that writes each element to line,
+// separated by separator, formatted the way fmt.Fprint formats it in the zap console encoder
+type consoleArrayEncoder struct {
+	line      *buffer.Buffer
+	separator string
+	count     int
+	scratch   [64]byte
+}
+
+func (a *consoleArrayEncoder) next() {
+	if a.count > 0 {
+		a.line.AppendString(a.separator)
+	}
+	a.count++
+}
+
+func (a *consoleArrayEncoder) AppendBool(v bool)             { a.next(); a.line.AppendBool(v) }
+func (a *consoleArrayEncoder) AppendByteString(v []byte)     { a.next(); a.line.Write(v) }
+func (a *consoleArrayEncoder) AppendComplex128(v complex128) { a.next(); fmt.Fprint(a.line, v) }
+func (a *consoleArrayEncoder) AppendComplex64(v complex64)   { a.next(); fmt.Fprint(a.line, v) }
+func (a *consoleArrayEncoder) AppendFloat64(v float64)       { a.appendFloat(v, 64) }
+func (a *consoleArrayEncoder) AppendFloat32(v float32)       { a.appendFloat(float64(v), 32) }
+func (a *consoleArrayEncoder) AppendInt(v int)               { a.AppendInt64(int64(v)) }
+func (a *consoleArrayEncoder) AppendInt64(v int64)           { a.next(); a.line.AppendInt(v) }
+func (a *consoleArrayEncoder) AppendInt32(v int32)           { a.AppendInt64(int64(v)) }
+func (a *consoleArrayEncoder) AppendInt16(v int16)           { a.AppendInt64(int64(v)) }
+func (a *consoleArrayEncoder) AppendInt8(v int8)             { a.AppendInt64(int64(v)) }
+func (a *consoleArrayEncoder) AppendString(v string)         { a.next(); a.line.AppendString(v) }
+func (a *consoleArrayEncoder) AppendUint(v uint)             { a.AppendUint64(uint64(v)) }
+func (a *consoleArrayEncoder) AppendUint64(v uint64)         { a.next(); a.line.AppendUint(v) }
+func (a *consoleArrayEncoder) AppendUint32(v uint32)         { a.AppendUint64(uint64(v)) }
+func (a *consoleArrayEncoder) AppendUint16(v uint16)         { a.AppendUint64(uint64(v)) }
+func (a *consoleArrayEncoder) AppendUint8(v uint8)           { a.AppendUint64(uint64(v)) }
+func (a *consoleArrayEncoder) AppendUintptr(v uintptr)       { a.AppendUint64(uint64(v)) }
+func (a *consoleArrayEncoder) AppendDuration(v time.Duration) {
+	a.next()
+	a.line.AppendString(v.String())
+}
+func (a *consoleArrayEncoder) AppendTime(v time.Time) { a.next(); a.line.AppendString(v.String()) }
+
+// AppendTimeLayout lets zapcore.TimeEncoderOfLayout format the time without allocating
+func (a *consoleArrayEncoder) AppendTimeLayout(v time.Time, layout string) {
+	a.next()
+	a.line.AppendTime(v, layout)
+}
+
+// appendTrimmedCaller writes the same text as caller.TrimmedPath()
+func (a *consoleArrayEncoder) appendTrimmedCaller(caller zapcore.EntryCaller) {
+	a.next()
+	if !caller.Defined {
+		a.line.AppendString("undefined")
+		return
+	}
+	// keep everything after the penultimate '/', or the full path if there are fewer than two
+	file := caller.File
+	if idx := strings.LastIndexByte(file, '/'); idx != -1 {
+		if idx = strings.LastIndexByte(file[:idx], '/'); idx != -1 {
+			file = file[idx+1:]
+		}
+	}
+	a.line.AppendString(file)
+	a.line.AppendByte(':')
+	a.line.AppendInt(int64(caller.Line))
+}
+
+// appendFloat matches the %v verb used by fmt.Fprint
+func (a *consoleArrayEncoder) appendFloat(v float64, bitSize int) {
+	a.next()
+	a.line.Write(strconv.AppendFloat(a.scratch[:0], v, 'g', -1, bitSize))
+}
+
+func (a *consoleArrayEncoder) AppendArray(v zapcore.ArrayMarshaler) error {
+	m := zapcore.NewMapObjectEncoder()
+	err := m.AddArray("", v)
+	a.next()
+	fmt.Fprint(a.line, m.Fields[""])
+	return err
+}
+
+func (a *consoleArrayEncoder) AppendObject(v zapcore.ObjectMarshaler) error {
+	m := zapcore.NewMapObjectEncoder()
+	err := v.MarshalLogObject(m)
+	a.next()
+	fmt.Fprint(a.line, m.Fields)
+	return err
+}
+
+func (a *consoleArrayEncoder) AppendReflected(v interface{}) error {
+	a.next()
+	fmt.Fprint(a.line, v)
+	return nil
+}
@@ -0,0 +1,139 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// consoleEntry is a representative entry with metadata in every position the console format uses
+func consoleEntry() zapcore.Entry {
+	return zapcore.Entry{
+		Level:      zapcore.DebugLevel,
+		Time:       time.Date(2024, 5, 6, 7, 8, 9, 123456789, time.UTC),
+		LoggerName: "orders",
+		Message:    "order created",
+		Caller:     zapcore.NewEntryCaller(0, "/src/service/orders/handler.go", 42, true),
+	}
+}
+
+func threeStringFields() []zapcore.Field {
+	return []zapcore.Field{
+		zap.String("order_id", "o-1"),
+		zap.String("user_id", "u-1"),
+		zap.String("status", "created"),
+	}
+}
+
+func TestZeroAllocConsoleEncoderMatchesZap(t *testing.T) {
+	repoCfg := testConfig(t)
+	stackCfg := zap.NewDevelopmentEncoderConfig()
+	stackCfg.FunctionKey = "func"
+	minimalCfg := zapcore.EncoderConfig{MessageKey: "msg", ConsoleSeparator: " | ", SkipLineEnding: true}
+
+	withStack := consoleEntry()
+	withStack.Stack = "goroutine 1 [running]:\nmain.main()"
+	withStack.Caller.Function = "orders.(*Handler).Create"
+	noCaller := consoleEntry()
+	noCaller.Caller = zapcore.EntryCaller{}
+	noCaller.LoggerName = ""
+	shortCaller := consoleEntry()
+	shortCaller.Caller = zapcore.NewEntryCaller(0, "orders/handler.go", 7, true)
+	bareCaller := consoleEntry()
+	bareCaller.Caller = zapcore.NewEntryCaller(0, "handler.go", 7, true)
+
+	tests := []struct {
+		name   string
+		cfg    zapcore.EncoderConfig
+		entry  zapcore.Entry
+		fields []zapcore.Field
+	}{
+		{"repo config", repoCfg.EncoderConfig(), consoleEntry(), threeStringFields()},
+		{"no fields", repoCfg.EncoderConfig(), consoleEntry(), nil},
+		{"no caller or name", repoCfg.EncoderConfig(), noCaller, threeStringFields()},
+		{"caller with one separator", repoCfg.EncoderConfig(), shortCaller, nil},
+		{"caller without separator", repoCfg.EncoderConfig(), bareCaller, nil},
+		{"mixed fields", repoCfg.EncoderConfig(), consoleEntry(), []zapcore.Field{
+			zap.Int("attempt", 3),
+			zap.Float64("ratio", 0.25),
+			zap.Bool("retry", true),
+			zap.Duration("elapsed", 1500*time.Millisecond),
+			zap.Strings("tags", []string{"a", "b"}),
+			zap.Error(errors.New("timeout")),
+		}},
+		{"development config with stack", stackCfg, withStack, threeStringFields()},
+		{"message only", minimalCfg, consoleEntry(), threeStringFields()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, context := range [][]zapcore.Field{nil, {zap.String("service", "orders")}} {
+				want := zapcore.NewConsoleEncoder(tt.cfg)
+				got := NewZeroAllocConsoleEncoder(tt.cfg)
+				for _, f := range context {
+					f.AddTo(want)
+					f.AddTo(got)
+				}
+				wantBuf, err := want.Clone().EncodeEntry(tt.entry, tt.fields)
+				if err != nil {
+					t.Fatal(err)
+				}
+				gotBuf, err := got.Clone().EncodeEntry(tt.entry, tt.fields)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if gotBuf.String() != wantBuf.String() {
+					t.Errorf("context %v:\n got %q\nwant %q", context, gotBuf.String(), wantBuf.String())
+				}
+				wantBuf.Free()
+				gotBuf.Free()
+			}
+		})
+	}
+}
+
+func TestZeroAllocConsoleEncoderAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not stable under the race detector")
+	}
+	cfg := testConfig(t)
+	enc := NewZeroAllocConsoleEncoder(cfg.EncoderConfig())
+	entry, fields := consoleEntry(), threeStringFields()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, err := enc.EncodeEntry(entry, fields)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf.Free()
+	})
+	if allocs != 0 {
+		t.Errorf("EncodeEntry with three string fields: %v allocs, want 0", allocs)
+	}
+}
+
+func BenchmarkConsoleEncoder(b *testing.B) {
+	cfg := getDefaultConfig()
+	encoders := []struct {
+		name string
+		enc  zapcore.Encoder
+	}{
+		{"ZeroAlloc", NewZeroAllocConsoleEncoder(cfg.EncoderConfig())},
+		{"Zap", zapcore.NewConsoleEncoder(cfg.EncoderConfig())},
+	}
+	entry, fields := consoleEntry(), threeStringFields()
+	for _, bm := range encoders {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf, err := bm.enc.EncodeEntry(entry, fields)
+				if err != nil {
+					b.Fatal(err)
+				}
+				buf.Free()
+			}
+		})
+	}
+}
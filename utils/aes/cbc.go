@@ -0,0 +1,113 @@
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidPadding 解密后的 PKCS#7 填充格式错误，通常表示密钥或 IV 不匹配
+var ErrInvalidPadding = errors.New("aes: invalid PKCS#7 padding")
+
+// CryptoCBC AES-CBC 加密，使用 PKCS#7 填充，可与其他语言的标准实现互通。
+// 未指定 IV 时每次加密生成随机 IV 并放在密文之前：base64(IV || 密文)；
+// 指定 IV 时密文中不包含 IV：base64(密文)
+type CryptoCBC struct {
+	block cipher.Block
+	iv    []byte
+}
+
+// NewCryptoCBC 创建新的 CryptoCBC 实例，key 长度须为 16、24 或 32 字节，
+// iv 为空表示随机生成，否则长度须为 16 字节
+func NewCryptoCBC(key, iv string) (*CryptoCBC, error) {
+	block, err := aes.NewCipher([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+	c := &CryptoCBC{block: block}
+	if iv != "" {
+		if len(iv) != block.BlockSize() {
+			return nil, fmt.Errorf("aes: invalid IV length %d, want %d", len(iv), block.BlockSize())
+		}
+		c.iv = []byte(iv)
+	}
+	return c, nil
+}
+
+// Encrypt 对文本进行 PKCS#7 填充后加密，返回 base64 编码的密文
+func (c *CryptoCBC) Encrypt(text string) (string, error) {
+	bs := c.block.BlockSize()
+	padded := pkcs7Pad([]byte(text), bs)
+
+	var out, iv []byte
+	if c.iv != nil {
+		iv = c.iv
+		out = make([]byte, len(padded))
+		cipher.NewCBCEncrypter(c.block, iv).CryptBlocks(out, padded)
+	} else {
+		out = make([]byte, bs+len(padded))
+		iv = out[:bs]
+		if _, err := rand.Read(iv); err != nil {
+			return "", fmt.Errorf("aes: generate IV: %w", err)
+		}
+		cipher.NewCBCEncrypter(c.block, iv).CryptBlocks(out[bs:], padded)
+	}
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// Decrypt 解密 Encrypt 生成的密文并去掉 PKCS#7 填充，填充无效时返回 ErrInvalidPadding
+func (c *CryptoCBC) Decrypt(text string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		return "", fmt.Errorf("aes: %w", err)
+	}
+	bs := c.block.BlockSize()
+	iv := c.iv
+	if iv == nil {
+		if len(data) < bs {
+			return "", errNotBlockMultiple
+		}
+		iv, data = data[:bs], data[bs:]
+	}
+	if len(data) == 0 || len(data)%bs != 0 {
+		return "", errNotBlockMultiple
+	}
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(c.block, iv).CryptBlocks(plain, data)
+	unpadded, err := pkcs7Unpad(plain, bs)
+	if err != nil {
+		return "", err
+	}
+	return string(unpadded), nil
+}
+
+// pkcs7Pad 按 PKCS#7 填充到 blockSize 的倍数，长度恰好为倍数时追加一个完整的填充块
+func pkcs7Pad(data []byte, blockSize int) []byte {
+	padding := blockSize - len(data)%blockSize
+	padded := make([]byte, len(data)+padding)
+	copy(padded, data)
+	for i := len(data); i < len(padded); i++ {
+		padded[i] = byte(padding)
+	}
+	return padded
+}
+
+// pkcs7Unpad 校验并去掉 PKCS#7 填充
+func pkcs7Unpad(data []byte, blockSize int) ([]byte, error) {
+	if len(data) == 0 || len(data)%blockSize != 0 {
+		return nil, ErrInvalidPadding
+	}
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > blockSize {
+		return nil, ErrInvalidPadding
+	}
+	for _, b := range data[len(data)-padding:] {
+		if int(b) != padding {
+			return nil, ErrInvalidPadding
+		}
+	}
+	return data[:len(data)-padding], nil
+}
@@ -0,0 +1,202 @@
+package aes
+
+import (
+	"crypto/cipher"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+const testIV = "fedcba9876543210"
+
+func newTestCryptoCBC(t *testing.T, iv string) *CryptoCBC {
+	t.Helper()
+	c, err := NewCryptoCBC(testKey, iv)
+	if err != nil {
+		t.Fatalf("NewCryptoCBC: %v", err)
+	}
+	return c
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// NIST SP 800-38A F.2.1 CBC-AES128.Encrypt 的前两个分组
+func TestCBCKnownAnswer(t *testing.T) {
+	key := mustDecodeHex(t, "2b7e151628aed2a6abf7158809cf4f3c")
+	iv := mustDecodeHex(t, "000102030405060708090a0b0c0d0e0f")
+	plain := mustDecodeHex(t, "6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e51")
+	want := "7649abac8119b246cee98e9b12e9197d5086cb9b507219ee95db113a917678b2"
+
+	c, err := NewCryptoCBC(string(key), string(iv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := c.Encrypt(string(plain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := base64.StdEncoding.DecodeString(encrypted)
+	// 明文恰好两个分组，PKCS#7 追加一个完整的填充分组
+	if len(data) != 48 {
+		t.Fatalf("ciphertext length = %d, want 48", len(data))
+	}
+	if got := hex.EncodeToString(data[:32]); got != want {
+		t.Errorf("ciphertext = %s, want %s", got, want)
+	}
+	decrypted, err := c.Decrypt(encrypted)
+	if err != nil || decrypted != string(plain) {
+		t.Errorf("Decrypt = %x, %v; want %x", decrypted, err, plain)
+	}
+}
+
+func TestCBCExplicitIV(t *testing.T) {
+	c := newTestCryptoCBC(t, testIV)
+	first, err := c.Encrypt("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.Encrypt("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 固定 IV 时结果确定，且密文中不包含 IV
+	if first != second {
+		t.Errorf("encryptions with a fixed IV differ: %q, %q", first, second)
+	}
+	if data, _ := base64.StdEncoding.DecodeString(first); len(data) != 16 {
+		t.Errorf("ciphertext length = %d, want 16", len(data))
+	}
+	if got, err := c.Decrypt(first); err != nil || got != "secret" {
+		t.Errorf("Decrypt = %q, %v", got, err)
+	}
+}
+
+func TestCBCRandomIV(t *testing.T) {
+	c := newTestCryptoCBC(t, "")
+	first, err := c.Encrypt("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.Encrypt("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Errorf("two encryptions with random IVs are both %q", first)
+	}
+	// 随机 IV 放在密文之前
+	data, _ := base64.StdEncoding.DecodeString(first)
+	if len(data) != 32 {
+		t.Errorf("ciphertext length = %d, want 32", len(data))
+	}
+	for _, encrypted := range []string{first, second} {
+		if got, err := c.Decrypt(encrypted); err != nil || got != "secret" {
+			t.Errorf("Decrypt(%q) = %q, %v", encrypted, got, err)
+		}
+	}
+
+	// 使用密文前 16 字节作为固定 IV 也能解密其余部分
+	fixed := newTestCryptoCBC(t, string(data[:16]))
+	if got, err := fixed.Decrypt(base64.StdEncoding.EncodeToString(data[16:])); err != nil || got != "secret" {
+		t.Errorf("Decrypt with the prefixed IV = %q, %v", got, err)
+	}
+}
+
+func TestCBCRoundTrip(t *testing.T) {
+	for _, iv := range []string{"", testIV} {
+		c := newTestCryptoCBC(t, iv)
+		for _, text := range []string{"", "secret", "exactly16bytes!!", "密码 with unicode"} {
+			encrypted, err := c.Encrypt(text)
+			if err != nil {
+				t.Fatalf("Encrypt(%q): %v", text, err)
+			}
+			decrypted, err := c.Decrypt(encrypted)
+			if err != nil || decrypted != text {
+				t.Errorf("Decrypt(Encrypt(%q)) with IV %q = %q, %v", text, iv, decrypted, err)
+			}
+		}
+	}
+}
+
+func TestCBCDecryptInvalidPadding(t *testing.T) {
+	c := newTestCryptoCBC(t, testIV)
+	tests := []struct {
+		name string
+		last []byte
+	}{
+		{"zero padding", []byte{0}},
+		{"padding longer than a block", []byte{17}},
+		{"inconsistent padding bytes", []byte{1, 3, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 直接加密一个以错误填充结尾的分组，绕过 Encrypt 的填充
+			plain := make([]byte, 16)
+			copy(plain[16-len(tt.last):], tt.last)
+			out := make([]byte, 16)
+			cipher.NewCBCEncrypter(c.block, []byte(testIV)).CryptBlocks(out, plain)
+
+			got, err := c.Decrypt(base64.StdEncoding.EncodeToString(out))
+			if !errors.Is(err, ErrInvalidPadding) || got != "" {
+				t.Errorf("Decrypt = %q, %v; want ErrInvalidPadding", got, err)
+			}
+		})
+	}
+}
+
+func TestCBCDecryptNotBlockMultiple(t *testing.T) {
+	tests := []struct {
+		name string
+		iv   string
+		size int
+	}{
+		{"empty with fixed IV", testIV, 0},
+		{"20 bytes with fixed IV", testIV, 20},
+		{"15 bytes with fixed IV", testIV, 15},
+		{"shorter than the IV", "", 15},
+		{"only the IV", "", 16},
+		{"IV and a partial block", "", 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCryptoCBC(t, tt.iv)
+			got, err := c.Decrypt(base64.StdEncoding.EncodeToString(make([]byte, tt.size)))
+			if !errors.Is(err, errNotBlockMultiple) || got != "" {
+				t.Errorf("Decrypt(%d bytes) = %q, %v; want errNotBlockMultiple", tt.size, got, err)
+			}
+		})
+	}
+
+	c := newTestCryptoCBC(t, testIV)
+	if _, err := c.Decrypt("not base64!"); !errors.Is(err, base64.CorruptInputError(3)) {
+		t.Errorf("Decrypt(not base64) error = %v", err)
+	}
+}
+
+func TestNewCryptoCBCInvalidInput(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		iv   string
+	}{
+		{"15-byte IV", testKey, "0123456789abcde"},
+		{"17-byte IV", testKey, "0123456789abcdefg"},
+		{"15-byte key", "0123456789abcde", ""},
+		{"17-byte key", "0123456789abcdefg", testIV},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if c, err := NewCryptoCBC(tt.key, tt.iv); err == nil || c != nil {
+				t.Errorf("NewCryptoCBC = %v, %v; want nil and an error", c, err)
+			}
+		})
+	}
+}
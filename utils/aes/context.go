@@ -0,0 +1,46 @@
+package aes
+
+import (
+	"context"
+	"fmt"
+)
+
+// cryptoResult 后台加解密的结果
+type cryptoResult struct {
+	text string
+	err  error
+}
+
+// EncryptWithContext 在独立 goroutine 中执行 Encrypt，ctx 取消或超时时立即返回 ctx.Err()
+func (c *CryptoDB) EncryptWithContext(ctx context.Context, text string) (string, error) {
+	return runWithContext(ctx, func() (string, error) { return c.Encrypt(text) })
+}
+
+// DecryptWithContext 在独立 goroutine 中执行 Decrypt，ctx 取消或超时时立即返回 ctx.Err()
+func (c *CryptoDB) DecryptWithContext(ctx context.Context, text string) (string, error) {
+	return runWithContext(ctx, func() (string, error) { return c.Decrypt(text) })
+}
+
+// runWithContext 运行 fn 并等待结果或 ctx 结束；fn 中的 panic 转换为 error 返回
+func runWithContext(ctx context.Context, fn func() (string, error)) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	// 带缓冲，ctx 先结束时后台 goroutine 仍可写入并退出
+	done := make(chan cryptoResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- cryptoResult{err: fmt.Errorf("aes: %v", r)}
+			}
+		}()
+		text, err := fn()
+		done <- cryptoResult{text: text, err: err}
+	}()
+	select {
+	case res := <-done:
+		return res.text, res.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
@@ -0,0 +1,97 @@
+package aes
+
+import (
+	"context"
+	"crypto/cipher"
+	"errors"
+	"testing"
+	"time"
+)
+
+// slowBlock 每处理一个分组都要等待 release 关闭，用于模拟耗时很长的加解密
+type slowBlock struct {
+	cipher.Block
+	release chan struct{}
+}
+
+func (b *slowBlock) Encrypt(dst, src []byte) {
+	<-b.release
+	b.Block.Encrypt(dst, src)
+}
+
+func (b *slowBlock) Decrypt(dst, src []byte) {
+	<-b.release
+	b.Block.Decrypt(dst, src)
+}
+
+func newSlowCryptoDB(t *testing.T) *CryptoDB {
+	t.Helper()
+	release := make(chan struct{})
+	// 测试结束后放行，让后台 goroutine 退出
+	t.Cleanup(func() { close(release) })
+	return &CryptoDB{block: &slowBlock{Block: newTestCryptoDB(t).block, release: release}}
+}
+
+func TestWithContextDeadline(t *testing.T) {
+	c := newSlowCryptoDB(t)
+	ciphertext, _ := newTestCryptoDB(t).Encrypt("hello")
+
+	tests := []struct {
+		name string
+		fn   func(ctx context.Context) (string, error)
+	}{
+		{"encrypt", func(ctx context.Context) (string, error) { return c.EncryptWithContext(ctx, "hello") }},
+		{"decrypt", func(ctx context.Context) (string, error) { return c.DecryptWithContext(ctx, ciphertext) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			_, err := tt.fn(ctx)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("returned after %v, want right after the deadline", elapsed)
+			}
+		})
+	}
+}
+
+func TestWithContextCanceledBeforeStart(t *testing.T) {
+	c := newTestCryptoDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.EncryptWithContext(ctx, "hello"); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestWithContextRoundTrip(t *testing.T) {
+	c := newTestCryptoDB(t)
+	ctx := context.Background()
+
+	encrypted, err := c.EncryptWithContext(ctx, "hello world")
+	if err != nil {
+		t.Fatalf("EncryptWithContext: %v", err)
+	}
+	if want, _ := c.Encrypt("hello world"); encrypted != want {
+		t.Errorf("EncryptWithContext = %q, want the Encrypt result %q", encrypted, want)
+	}
+	decrypted, err := c.DecryptWithContext(ctx, encrypted)
+	if err != nil || decrypted != "hello world" {
+		t.Errorf("DecryptWithContext = %q, %v", decrypted, err)
+	}
+	if _, err := c.DecryptWithContext(ctx, "not base64!"); err == nil {
+		t.Error("DecryptWithContext accepted invalid input")
+	}
+}
+
+func TestWithContextRecoversPanic(t *testing.T) {
+	_, err := runWithContext(context.Background(), func() (string, error) { panic("broken block") })
+	if err == nil || err.Error() != "aes: broken block" {
+		t.Errorf("err = %v, want the panic converted to an error", err)
+	}
+}
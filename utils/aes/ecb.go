@@ -3,41 +3,51 @@ package aes
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"bufio"
+	"bytes"
 	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
 	"strings"
 )
 
+// errNotBlockMultiple 密文长度不是 AES 分组大小的倍数
+var errNotBlockMultiple = errors.New("aes: ciphertext is not a multiple of the block size")
 
 type CryptoDB struct {
 	block cipher.Block
 }
 
-// NewCryptoDB 创建新的 CryptoDB 实例并初始化 AES 加密块
-func NewCryptoDB(key string) *CryptoDB {
+// NewCryptoDB 创建新的 CryptoDB 实例并初始化 AES 加密块，key 长度须为 16、24 或 32 字节
+func NewCryptoDB(key string) (*CryptoDB, error) {
 	keyBytes := []byte(key) // 修改这里，使用 keyBytes 避免与参数名冲突
 	block, err := aes.NewCipher(keyBytes)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	return &CryptoDB{block: block}
+	return &CryptoDB{block: block}, nil
 }
 
 // Encrypt 对文本进行加密并返回加密后的 base64 编码字符串
-func (c *CryptoDB) Encrypt(text string) string {
+func (c *CryptoDB) Encrypt(text string) (string, error) {
 	paddedText := addTo16([]byte(text))
 	encrypted := make([]byte, len(paddedText))
 	for bs, be := 0, c.block.BlockSize(); bs < len(paddedText); bs, be = bs+c.block.BlockSize(), be+c.block.BlockSize() {
 		c.block.Encrypt(encrypted[bs:be], paddedText[bs:be])
 	}
 	encMsg := base64.StdEncoding.EncodeToString(encrypted)
-	return encMsg
+	return encMsg, nil
 }
 
-// Decrypt 对 base64 编码的加密字符串进行解密并返回明文
-func (c *CryptoDB) Decrypt(text string) string {
+// Decrypt 对 base64 编码的加密字符串进行解密并返回明文，base64 无效或长度不是分组大小的倍数时返回 error
+func (c *CryptoDB) Decrypt(text string) (string, error) {
 	decoded, err := base64.StdEncoding.DecodeString(text)
 	if err != nil {
-		panic(err)
+		return "", fmt.Errorf("aes: %w", err)
+	}
+	if len(decoded)%c.block.BlockSize() != 0 {
+		return "", errNotBlockMultiple
 	}
 	decrypted := make([]byte, len(decoded))
 	for bs, be := 0, c.block.BlockSize(); bs < len(decoded); bs, be = bs+c.block.BlockSize(), be+c.block.BlockSize() {
@@ -45,7 +55,73 @@ func (c *CryptoDB) Decrypt(text string) string {
 	}
 	decMsg := string(decrypted)
 	decMsg = strings.TrimRight(decMsg, "\x00") // 去掉填充的 \x00
-	return decMsg
+	return decMsg, nil
+}
+
+// EncryptStream 从 src 读取明文，按 16 字节分组加密后写入 dst（原始密文，不做 base64 编码）
+// 最后一组使用与 Encrypt 相同的 \x00 填充
+func (c *CryptoDB) EncryptStream(src io.Reader, dst io.Writer) error {
+	r := bufio.NewReader(src)
+	w := bufio.NewWriter(dst)
+	bs := c.block.BlockSize()
+	plain := make([]byte, bs)
+	encrypted := make([]byte, bs)
+	for {
+		n, err := io.ReadFull(r, plain)
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			// 最后一组（可能为空）填充后加密
+			c.block.Encrypt(encrypted, addTo16(plain[:n]))
+			if _, err := w.Write(encrypted); err != nil {
+				return err
+			}
+			return w.Flush()
+		}
+		if err != nil {
+			return err
+		}
+		c.block.Encrypt(encrypted, plain)
+		if _, err := w.Write(encrypted); err != nil {
+			return err
+		}
+	}
+}
+
+// DecryptStream 从 src 读取 EncryptStream 生成的密文，按 16 字节分组解密后写入 dst，仅在最后一组去掉 \x00 填充
+func (c *CryptoDB) DecryptStream(src io.Reader, dst io.Writer) error {
+	r := bufio.NewReader(src)
+	w := bufio.NewWriter(dst)
+	bs := c.block.BlockSize()
+	encrypted := make([]byte, bs)
+	current := make([]byte, bs)
+	pending := make([]byte, bs)
+	hasPending := false
+	for {
+		_, err := io.ReadFull(r, encrypted)
+		if err == io.EOF {
+			break
+		}
+		if err == io.ErrUnexpectedEOF {
+			return errNotBlockMultiple
+		}
+		if err != nil {
+			return err
+		}
+		// 先写出上一组，保留当前组，直到确认它是否为最后一组
+		if hasPending {
+			if _, err := w.Write(pending); err != nil {
+				return err
+			}
+		}
+		c.block.Decrypt(current, encrypted)
+		pending, current = current, pending
+		hasPending = true
+	}
+	if hasPending {
+		if _, err := w.Write(bytes.TrimRight(pending, "\x00")); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
 }
 
 // addTo16 用于将明文填充到 16 字节的倍数
@@ -0,0 +1,154 @@
+package aes
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"io"
+	"testing"
+)
+
+const testKey = "0123456789abcdef"
+
+func newTestCryptoDB(t *testing.T) *CryptoDB {
+	t.Helper()
+	c, err := NewCryptoDB(testKey)
+	if err != nil {
+		t.Fatalf("NewCryptoDB: %v", err)
+	}
+	return c
+}
+
+func TestStreamRoundTrip1MB(t *testing.T) {
+	c := newTestCryptoDB(t)
+	plain := make([]byte, 1<<20)
+	if _, err := rand.Read(plain); err != nil {
+		t.Fatal(err)
+	}
+	// 末尾不能是 \x00，否则会被当作填充去掉
+	plain[len(plain)-1] = 0xff
+
+	var encrypted, decrypted bytes.Buffer
+	if err := c.EncryptStream(bytes.NewReader(plain), &encrypted); err != nil {
+		t.Fatalf("EncryptStream: %v", err)
+	}
+	if encrypted.Len()%16 != 0 || encrypted.Len() <= len(plain) {
+		t.Fatalf("ciphertext length %d for %d bytes of plaintext", encrypted.Len(), len(plain))
+	}
+	if err := c.DecryptStream(&encrypted, &decrypted); err != nil {
+		t.Fatalf("DecryptStream: %v", err)
+	}
+	if !bytes.Equal(decrypted.Bytes(), plain) {
+		t.Fatal("decrypted stream differs from the input")
+	}
+}
+
+func TestStreamMatchesStringAPI(t *testing.T) {
+	c := newTestCryptoDB(t)
+	for _, text := range []string{"", "short", "exactly16bytes!!", "a little more than one block"} {
+		var encrypted bytes.Buffer
+		if err := c.EncryptStream(bytes.NewReader([]byte(text)), &encrypted); err != nil {
+			t.Fatalf("EncryptStream(%q): %v", text, err)
+		}
+		decrypted, err := c.Decrypt(base64.StdEncoding.EncodeToString(encrypted.Bytes()))
+		if err != nil || decrypted != text {
+			t.Errorf("Decrypt(EncryptStream(%q)) = %q, %v", text, decrypted, err)
+		}
+	}
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+type errWriter struct{ err error }
+
+func (w errWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestStreamPropagatesErrors(t *testing.T) {
+	c := newTestCryptoDB(t)
+	errIO := errors.New("io failure")
+
+	if err := c.EncryptStream(errReader{errIO}, io.Discard); !errors.Is(err, errIO) {
+		t.Errorf("EncryptStream read error = %v", err)
+	}
+	if err := c.EncryptStream(bytes.NewReader(make([]byte, 64)), errWriter{errIO}); !errors.Is(err, errIO) {
+		t.Errorf("EncryptStream write error = %v", err)
+	}
+	if err := c.DecryptStream(errReader{errIO}, io.Discard); !errors.Is(err, errIO) {
+		t.Errorf("DecryptStream read error = %v", err)
+	}
+	if err := c.DecryptStream(bytes.NewReader(make([]byte, 20)), io.Discard); !errors.Is(err, errNotBlockMultiple) {
+		t.Errorf("DecryptStream truncated input error = %v", err)
+	}
+}
+
+func TestNewCryptoDBKeyLength(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr bool
+	}{
+		{"empty", "", true},
+		{"15 bytes", "0123456789abcde", true},
+		{"17 bytes", "0123456789abcdefg", true},
+		{"AES-128", testKey, false},
+		{"AES-192", "0123456789abcdef01234567", false},
+		{"AES-256", "0123456789abcdef0123456789abcdef", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCryptoDB(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCryptoDB(%q) error = %v, wantErr %v", tt.key, err, tt.wantErr)
+			}
+			if tt.wantErr && c != nil {
+				t.Errorf("NewCryptoDB(%q) = %v, want nil on error", tt.key, c)
+			}
+		})
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	c := newTestCryptoDB(t)
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr error
+	}{
+		// 空输入解密为空字符串
+		{"empty input", "", "", nil},
+		{"not base64", "not base64!", "", base64.CorruptInputError(3)},
+		// ECB 使用 \x00 填充，没有分组填充可校验，这里的填充错误指 base64 填充
+		{"bad base64 padding", "QUJD=", "", base64.CorruptInputError(4)},
+		{"not block aligned", base64.StdEncoding.EncodeToString(make([]byte, 20)), "", errNotBlockMultiple},
+		{"one byte short of a block", base64.StdEncoding.EncodeToString(make([]byte, 15)), "", errNotBlockMultiple},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Decrypt(tt.text)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Decrypt(%q) error = %v, want %v", tt.text, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Decrypt(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	c := newTestCryptoDB(t)
+	for _, text := range []string{"", "secret", "exactly16bytes!!", "密码 with unicode"} {
+		encrypted, err := c.Encrypt(text)
+		if err != nil {
+			t.Fatalf("Encrypt(%q): %v", text, err)
+		}
+		decrypted, err := c.Decrypt(encrypted)
+		if err != nil || decrypted != text {
+			t.Errorf("Decrypt(Encrypt(%q)) = %q, %v", text, decrypted, err)
+		}
+	}
+}
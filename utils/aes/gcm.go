@@ -0,0 +1,68 @@
+package aes
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidCiphertext 密文长度不足或认证标签校验失败（密文被篡改或密钥不匹配）
+var ErrInvalidCiphertext = errors.New("aes: invalid ciphertext")
+
+// CryptoGCM AES-GCM 认证加密，相同明文每次加密的结果不同，且能发现被篡改的密文。
+// 密文格式为 base64(12 字节随机 nonce || 密文 || 认证标签)
+type CryptoGCM struct {
+	aead cipher.AEAD
+	// err 创建时的密钥错误，在 Encrypt、Decrypt 中返回
+	err error
+}
+
+// NewCryptoGCM 创建新的 CryptoGCM 实例，key 长度须为 16、24 或 32 字节。
+// 密钥无效时不返回 error，而是由 Encrypt、Decrypt 返回
+func NewCryptoGCM(key string) *CryptoGCM {
+	block, err := aes.NewCipher([]byte(key))
+	if err != nil {
+		return &CryptoGCM{err: err}
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return &CryptoGCM{err: err}
+	}
+	return &CryptoGCM{aead: aead}
+}
+
+// Encrypt 使用随机 nonce 加密 plaintext 并返回 base64 编码的密文
+func (c *CryptoGCM) Encrypt(plaintext string) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plaintext)+c.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("aes: generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 解码并解密 Encrypt 生成的密文，认证标签校验失败时返回 ErrInvalidCiphertext
+func (c *CryptoGCM) Decrypt(ciphertext string) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("aes: decode ciphertext: %w", err)
+	}
+	nonceSize := c.aead.NonceSize()
+	if len(data) < nonceSize+c.aead.Overhead() {
+		return "", ErrInvalidCiphertext
+	}
+	plain, err := c.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	return string(plain), nil
+}
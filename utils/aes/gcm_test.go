@@ -0,0 +1,113 @@
+package aes
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestGCMRoundTrip(t *testing.T) {
+	for _, key := range []string{testKey, "0123456789abcdef01234567", "0123456789abcdef0123456789abcdef"} {
+		c := NewCryptoGCM(key)
+		for _, text := range []string{"", "secret", "exactly16bytes!!", "密码 with unicode"} {
+			encrypted, err := c.Encrypt(text)
+			if err != nil {
+				t.Fatalf("Encrypt(%q) with %d-byte key: %v", text, len(key), err)
+			}
+			decrypted, err := c.Decrypt(encrypted)
+			if err != nil || decrypted != text {
+				t.Errorf("Decrypt(Encrypt(%q)) with %d-byte key = %q, %v", text, len(key), decrypted, err)
+			}
+		}
+	}
+}
+
+func TestGCMEncryptUsesRandomNonce(t *testing.T) {
+	c := NewCryptoGCM(testKey)
+	first, err := c.Encrypt("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := c.Encrypt("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Errorf("two encryptions of the same plaintext are both %q", first)
+	}
+}
+
+func TestGCMDecryptTampered(t *testing.T) {
+	c := NewCryptoGCM(testKey)
+	encrypted, err := c.Encrypt("secret message")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := base64.StdEncoding.DecodeString(encrypted)
+
+	tests := []struct {
+		name  string
+		index int
+	}{
+		{"nonce", 0},
+		// 12 字节 nonce 之后的第一个字节属于密文
+		{"ciphertext", 12},
+		// 最后一个字节属于 16 字节的认证标签
+		{"tag", len(data) - 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := append([]byte(nil), data...)
+			tampered[tt.index] ^= 0x01
+			got, err := c.Decrypt(base64.StdEncoding.EncodeToString(tampered))
+			if !errors.Is(err, ErrInvalidCiphertext) || got != "" {
+				t.Errorf("Decrypt = %q, %v; want ErrInvalidCiphertext", got, err)
+			}
+		})
+	}
+
+	// 其他密钥加密的密文同样无法通过认证
+	other, err := NewCryptoGCM("fedcba9876543210").Encrypt("secret message")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Decrypt(other); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Errorf("Decrypt with the wrong key error = %v, want ErrInvalidCiphertext", err)
+	}
+}
+
+func TestGCMDecryptMalformed(t *testing.T) {
+	c := NewCryptoGCM(testKey)
+	tests := []struct {
+		name    string
+		text    string
+		wantErr error
+	}{
+		{"empty", "", ErrInvalidCiphertext},
+		// nonce 与认证标签共需 28 字节
+		{"shorter than nonce and tag", base64.StdEncoding.EncodeToString(make([]byte, 27)), ErrInvalidCiphertext},
+		{"not base64", "not base64!", base64.CorruptInputError(3)},
+		{"bad base64 padding", "QUJD=", base64.CorruptInputError(4)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Decrypt(tt.text)
+			if !errors.Is(err, tt.wantErr) || got != "" {
+				t.Errorf("Decrypt(%q) = %q, %v; want %v", tt.text, got, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGCMInvalidKeyLength(t *testing.T) {
+	for _, key := range []string{"", "0123456789abcde", "0123456789abcdefg"} {
+		c := NewCryptoGCM(key)
+		if got, err := c.Encrypt("secret"); err == nil || got != "" {
+			t.Errorf("Encrypt with %d-byte key = %q, %v; want an error", len(key), got, err)
+		}
+		// 即使密文格式正确，也应返回密钥错误而不是 panic
+		if got, err := c.Decrypt(base64.StdEncoding.EncodeToString(make([]byte, 32))); err == nil || errors.Is(err, ErrInvalidCiphertext) || got != "" {
+			t.Errorf("Decrypt with %d-byte key = %q, %v; want the key error", len(key), got, err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package aes
+
+import (
+	"time"
+
+	"github.com/kobyt2/common-services/utils/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// InstrumentedCryptoDB 包装 CryptoDB，记录加解密耗时和失败次数
+type InstrumentedCryptoDB struct {
+	crypto          *CryptoDB
+	encryptDuration prometheus.Histogram
+	decryptDuration prometheus.Histogram
+	errors          prometheus.Counter
+}
+
+// NewInstrumented 创建 InstrumentedCryptoDB 并向 registry 注册指标，
+// 指标已注册时复用已有的 collector，同名但类型或标签不一致时返回注册错误
+func NewInstrumented(c *CryptoDB, registry prometheus.Registerer) (*InstrumentedCryptoDB, error) {
+	encryptDuration, err := metrics.Register(registry, prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name: "aes_encrypt_duration_seconds",
+		Help: "Duration of AES encrypt calls in seconds.",
+	}))
+	if err != nil {
+		return nil, err
+	}
+	decryptDuration, err := metrics.Register(registry, prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name: "aes_decrypt_duration_seconds",
+		Help: "Duration of AES decrypt calls in seconds.",
+	}))
+	if err != nil {
+		return nil, err
+	}
+	errorCount, err := metrics.Register(registry, prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "aes_error_total",
+		Help: "Total number of failed AES encrypt/decrypt calls.",
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return &InstrumentedCryptoDB{
+		crypto:          c,
+		encryptDuration: encryptDuration,
+		decryptDuration: decryptDuration,
+		errors:          errorCount,
+	}, nil
+}
+
+// Encrypt 调用 CryptoDB.Encrypt 并记录耗时
+func (i *InstrumentedCryptoDB) Encrypt(text string) (string, error) {
+	return i.observe(i.encryptDuration, func() (string, error) { return i.crypto.Encrypt(text) })
+}
+
+// Decrypt 调用 CryptoDB.Decrypt 并记录耗时，密文无效时返回 error 并增加失败计数
+func (i *InstrumentedCryptoDB) Decrypt(text string) (string, error) {
+	return i.observe(i.decryptDuration, func() (string, error) { return i.crypto.Decrypt(text) })
+}
+
+// observe 执行 fn、记录耗时，fn 返回 error 时增加失败计数
+func (i *InstrumentedCryptoDB) observe(hist prometheus.Histogram, fn func() (string, error)) (string, error) {
+	start := time.Now()
+	result, err := fn()
+	hist.Observe(time.Since(start).Seconds())
+	if err != nil {
+		i.errors.Inc()
+	}
+	return result, err
+}
@@ -0,0 +1,85 @@
+package aes
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// histogramCount 返回直方图的观测次数
+func histogramCount(t *testing.T, h prometheus.Histogram) uint64 {
+	t.Helper()
+	var m dto.Metric
+	if err := h.Write(&m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestInstrumentedCryptoDBMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	c, err := NewInstrumented(newTestCryptoDB(t), registry)
+	if err != nil {
+		t.Fatalf("NewInstrumented: %v", err)
+	}
+
+	var encrypted string
+	for i := 0; i < 3; i++ {
+		if encrypted, err = c.Encrypt("hello"); err != nil {
+			t.Fatalf("Encrypt: %v", err)
+		}
+	}
+	if plain, err := c.Decrypt(encrypted); err != nil || plain != "hello" {
+		t.Fatalf("Decrypt = %q, %v", plain, err)
+	}
+	for _, bad := range []string{"not base64!", "AAAA"} {
+		if _, err := c.Decrypt(bad); err == nil {
+			t.Fatalf("Decrypt(%q) succeeded", bad)
+		}
+	}
+
+	if n := histogramCount(t, c.encryptDuration); n != 3 {
+		t.Errorf("encrypt observations = %d, want 3", n)
+	}
+	if n := histogramCount(t, c.decryptDuration); n != 3 {
+		t.Errorf("decrypt observations = %d, want 3", n)
+	}
+	if v := testutil.ToFloat64(c.errors); v != 2 {
+		t.Errorf("aes_error_total = %v, want 2", v)
+	}
+	if n := testutil.CollectAndCount(registry); n != 3 {
+		t.Errorf("registry collected %d metrics, want 3", n)
+	}
+}
+
+func TestNewInstrumentedReusesCollectors(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	first, err := NewInstrumented(newTestCryptoDB(t), registry)
+	if err != nil {
+		t.Fatalf("first NewInstrumented: %v", err)
+	}
+	second, err := NewInstrumented(newTestCryptoDB(t), registry)
+	if err != nil {
+		t.Fatalf("second NewInstrumented: %v", err)
+	}
+
+	first.Decrypt("!")
+	second.Decrypt("!")
+	if v := testutil.ToFloat64(first.errors); v != 2 {
+		t.Errorf("shared aes_error_total = %v, want 2", v)
+	}
+}
+
+func TestNewInstrumentedRegistrationConflict(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	// 同名但标签不同的指标
+	registry.MustRegister(prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "aes_error_total",
+		Help: "Total number of failed AES encrypt/decrypt calls.",
+	}, []string{"op"}))
+	if _, err := NewInstrumented(newTestCryptoDB(t), registry); err == nil {
+		t.Error("NewInstrumented returned nil error for a conflicting collector")
+	}
+}
@@ -0,0 +1,81 @@
+package aes
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// defaultMigrateBatchSize BatchMigrate 在 batchSize 不为正数时使用的批大小
+const defaultMigrateBatchSize = 500
+
+// Encrypter 迁移的目标加密实现，CryptoCBC、CryptoGCM 均满足该接口
+type Encrypter interface {
+	Encrypt(text string) (string, error)
+}
+
+// MigrateECBNullToPKCS7 用旧的 \x00 填充 ECB CryptoDB 解密 ciphertext，再用 newCDB 重新加密。
+// newCDB 通常为使用 PKCS#7 填充的 *CryptoCBC
+func MigrateECBNullToPKCS7(oldCDB *CryptoDB, newCDB Encrypter, ciphertext string) (string, error) {
+	plain, err := oldCDB.Decrypt(ciphertext)
+	if err != nil {
+		return "", err
+	}
+	return newCDB.Encrypt(plain)
+}
+
+// BatchMigrate 按主键 id 分批读取 table 中的 column 并原地重新加密，返回更新的行数，空值会被跳过。
+// 整个迁移在同一个事务中完成：旧密文与新密文无法区分，部分提交后重跑会把新密文当作旧密文再处理一次
+func BatchMigrate(db *gorm.DB, table, column string, oldCDB *CryptoDB, newCDB Encrypter, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = defaultMigrateBatchSize
+	}
+	var migrated int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var lastID interface{}
+		for {
+			query := tx.Table(table).Select([]string{"id", column}).Order("id").Limit(batchSize)
+			if lastID != nil {
+				query = query.Where("id > ?", lastID)
+			}
+			var rows []map[string]interface{}
+			if err := query.Find(&rows).Error; err != nil {
+				return err
+			}
+			for _, row := range rows {
+				lastID = row["id"]
+				ciphertext := columnString(row[column])
+				if ciphertext == "" {
+					continue
+				}
+				value, err := MigrateECBNullToPKCS7(oldCDB, newCDB, ciphertext)
+				if err != nil {
+					return fmt.Errorf("aes: migrate %s.%s id=%v: %w", table, column, lastID, err)
+				}
+				if err := tx.Table(table).Where("id = ?", lastID).Update(column, value).Error; err != nil {
+					return err
+				}
+				migrated++
+			}
+			if len(rows) < batchSize {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+	return migrated, nil
+}
+
+// columnString 将数据库驱动返回的列值转换为字符串
+func columnString(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	default:
+		return ""
+	}
+}
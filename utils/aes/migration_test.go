@@ -0,0 +1,111 @@
+package aes
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type secretRow struct {
+	ID     int64
+	Secret string
+}
+
+func newMigrationDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 内存库每个连接都是独立的数据库，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	if err := db.Table("secrets").AutoMigrate(&secretRow{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestBatchMigrate(t *testing.T) {
+	db := newMigrationDB(t)
+	oldCDB := newTestCryptoDB(t)
+	newCDB, err := NewCryptoCBC("fedcba9876543210", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 100; i++ {
+		value := ""
+		// 第 10 行起每 10 行留一个空值，迁移时跳过
+		if i%10 != 0 {
+			if value, err = oldCDB.Encrypt(fmt.Sprintf("secret-%d", i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		db.Table("secrets").Create(&secretRow{ID: int64(i), Secret: value})
+	}
+
+	// 批大小不能整除行数，覆盖最后一个不满的批次
+	migrated, err := BatchMigrate(db, "secrets", "secret", oldCDB, newCDB, 7)
+	if err != nil {
+		t.Fatalf("BatchMigrate: %v", err)
+	}
+	if migrated != 90 {
+		t.Errorf("migrated %d rows, want 90", migrated)
+	}
+
+	var rows []secretRow
+	db.Table("secrets").Order("id").Find(&rows)
+	for _, row := range rows {
+		if row.ID%10 == 0 {
+			if row.Secret != "" {
+				t.Errorf("id %d: empty value changed to %q", row.ID, row.Secret)
+			}
+			continue
+		}
+		plain, err := newCDB.Decrypt(row.Secret)
+		if err != nil || plain != fmt.Sprintf("secret-%d", row.ID) {
+			t.Errorf("id %d: decrypted %q, %v", row.ID, plain, err)
+		}
+	}
+}
+
+func TestBatchMigrateRollsBackOnError(t *testing.T) {
+	db := newMigrationDB(t)
+	oldCDB := newTestCryptoDB(t)
+	newCDB, _ := NewCryptoCBC("fedcba9876543210", "")
+
+	good, _ := oldCDB.Encrypt("secret")
+	db.Table("secrets").Create(&secretRow{ID: 1, Secret: good})
+	db.Table("secrets").Create(&secretRow{ID: 2, Secret: "not base64!"})
+
+	if _, err := BatchMigrate(db, "secrets", "secret", oldCDB, newCDB, 10); err == nil {
+		t.Fatal("BatchMigrate succeeded with an undecryptable row")
+	}
+	var row secretRow
+	db.Table("secrets").First(&row, 1)
+	if row.Secret != good {
+		t.Error("row 1 was updated although the migration failed")
+	}
+}
+
+func TestMigrateECBNullToPKCS7(t *testing.T) {
+	oldCDB := newTestCryptoDB(t)
+	newCDB, _ := NewCryptoCBC("fedcba9876543210", "")
+	ciphertext, _ := oldCDB.Encrypt("exactly16bytes!!")
+
+	migrated, err := MigrateECBNullToPKCS7(oldCDB, newCDB, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain, err := newCDB.Decrypt(migrated); err != nil || plain != "exactly16bytes!!" {
+		t.Errorf("decrypted %q, %v", plain, err)
+	}
+}
@@ -0,0 +1,147 @@
+package auditlog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditEntry 一条审计记录，Checksum 由 Writer 计算填充
+type AuditEntry struct {
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Resource  string    `json:"resource"`
+	Timestamp time.Time `json:"timestamp"`
+	Checksum  string    `json:"checksum,omitempty"`
+}
+
+// TamperError 校验时发现的第一处篡改，Line 为从 1 开始的行号
+type TamperError struct {
+	Line   int
+	Reason string
+}
+
+func (e *TamperError) Error() string {
+	return fmt.Sprintf("auditlog: tampered at line %d: %s", e.Line, e.Reason)
+}
+
+// Writer 并发安全的只追加审计日志，每条记录的校验和为 HMAC-SHA256(记录 JSON + 上一条校验和)，形成校验链
+type Writer struct {
+	mu       sync.Mutex
+	file     *os.File
+	secret   []byte
+	previous string
+}
+
+// NewWriter 以只追加方式打开审计日志文件；已有内容会先校验，校验链不完整时返回错误
+func NewWriter(path string, secret []byte) (*Writer, error) {
+	previous, _, err := verifyChain(path, secret)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{file: file, secret: secret, previous: previous}, nil
+}
+
+// Append 计算校验和后追加一条记录，Timestamp 为空时使用当前时间
+func (w *Writer) Append(entry AuditEntry) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	checksum, err := computeChecksum(entry, w.previous, w.secret)
+	if err != nil {
+		return err
+	}
+	entry.Checksum = checksum
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := w.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	w.previous = checksum
+	return nil
+}
+
+// Close 关闭底层文件
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+// Verify 重新计算文件中所有记录的校验和，返回有效记录数；
+// 发现篡改（内容修改、删除中间记录、截断）时同时返回 *TamperError
+func Verify(path string, secret []byte) (int, error) {
+	_, count, err := verifyChain(path, secret)
+	return count, err
+}
+
+// verifyChain 校验整条记录链，返回最后一条记录的校验和与有效记录数
+func verifyChain(path string, secret []byte) (string, int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	previous := ""
+	count := 0
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				return previous, count, &TamperError{Line: count + 1, Reason: "incomplete record"}
+			}
+			return previous, count, nil
+		}
+		if err != nil {
+			return previous, count, err
+		}
+
+		var entry AuditEntry
+		if err := json.Unmarshal(bytes.TrimSuffix(line, []byte("\n")), &entry); err != nil {
+			return previous, count, &TamperError{Line: count + 1, Reason: "malformed record"}
+		}
+		expected, err := computeChecksum(entry, previous, secret)
+		if err != nil {
+			return previous, count, err
+		}
+		if !hmac.Equal([]byte(expected), []byte(entry.Checksum)) {
+			return previous, count, &TamperError{Line: count + 1, Reason: "checksum mismatch"}
+		}
+		previous = entry.Checksum
+		count++
+	}
+}
+
+// computeChecksum 计算不含 Checksum 字段的记录 JSON 与上一条校验和的 HMAC-SHA256
+func computeChecksum(entry AuditEntry, previous string, secret []byte) (string, error) {
+	entry.Checksum = ""
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	mac.Write([]byte(previous))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,130 @@
+package auditlog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+var testSecret = []byte("audit-secret")
+
+// writeEntries 写入 n 条记录并返回文件路径
+func writeEntries(t *testing.T, n int) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.log")
+	w, err := NewWriter(path, testSecret)
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		if err := w.Append(AuditEntry{Actor: "alice", Action: "update", Resource: fmt.Sprintf("doc/%d", i)}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func assertTamper(t *testing.T, path string, wantCount, wantLine int) {
+	t.Helper()
+	count, err := Verify(path, testSecret)
+	var tamper *TamperError
+	if !errors.As(err, &tamper) {
+		t.Fatalf("Verify err = %v, want *TamperError", err)
+	}
+	if count != wantCount || tamper.Line != wantLine {
+		t.Errorf("Verify = %d valid, tamper at line %d; want %d, line %d", count, tamper.Line, wantCount, wantLine)
+	}
+}
+
+func TestVerifyValidLog(t *testing.T) {
+	path := writeEntries(t, 5)
+	count, err := Verify(path, testSecret)
+	if err != nil || count != 5 {
+		t.Fatalf("Verify = %d, %v; want 5, nil", count, err)
+	}
+	if _, err := Verify(path, []byte("wrong secret")); err == nil {
+		t.Error("Verify succeeded with the wrong secret")
+	}
+}
+
+func TestVerifyDetectsTruncation(t *testing.T) {
+	path := writeEntries(t, 5)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 截断到最后一条记录的中间
+	if err := os.Truncate(path, info.Size()-20); err != nil {
+		t.Fatal(err)
+	}
+	assertTamper(t, path, 4, 5)
+}
+
+func TestVerifyDetectsModificationAndDeletion(t *testing.T) {
+	t.Run("modified record", func(t *testing.T) {
+		path := writeEntries(t, 5)
+		data, _ := os.ReadFile(path)
+		data = bytes.Replace(data, []byte(`"doc/2"`), []byte(`"doc/9"`), 1)
+		os.WriteFile(path, data, 0600)
+		assertTamper(t, path, 2, 3)
+	})
+	t.Run("deleted record", func(t *testing.T) {
+		path := writeEntries(t, 5)
+		data, _ := os.ReadFile(path)
+		lines := bytes.SplitAfter(data, []byte("\n"))
+		os.WriteFile(path, bytes.Join(append(lines[:1], lines[2:]...), nil), 0600)
+		assertTamper(t, path, 1, 2)
+	})
+}
+
+func TestWriterContinuesChainAndRejectsTamperedFile(t *testing.T) {
+	path := writeEntries(t, 2)
+	w, err := NewWriter(path, testSecret)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if err := w.Append(AuditEntry{Actor: "bob", Action: "delete", Resource: "doc/1"}); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	if count, err := Verify(path, testSecret); err != nil || count != 3 {
+		t.Fatalf("Verify after reopen = %d, %v; want 3, nil", count, err)
+	}
+
+	os.Truncate(path, 10)
+	if _, err := NewWriter(path, testSecret); err == nil {
+		t.Error("NewWriter accepted a tampered file")
+	}
+}
+
+func TestConcurrentAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	w, err := NewWriter(path, testSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				if err := w.Append(AuditEntry{Actor: fmt.Sprint(i), Action: "write", Resource: fmt.Sprint(j)}); err != nil {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	w.Close()
+	if count, err := Verify(path, testSecret); err != nil || count != 200 {
+		t.Fatalf("Verify = %d, %v; want 200, nil", count, err)
+	}
+}
@@ -0,0 +1,142 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kobyt2/common-services/logger"
+	"go.uber.org/zap"
+)
+
+// ErrStopped 处理器已停止，不再接受新数据
+var ErrStopped = errors.New("batch: processor stopped")
+
+// Processor 将数据缓存起来批量交给 flushFn 处理：缓存达到 maxSize 条时立即刷新，
+// 此外每隔 maxWait 刷新一次未满的批次。flushFn 调用是串行的；flushFn 返回错误时该批数据不会重试
+type Processor[T any] struct {
+	flushFn func(ctx context.Context, items []T) error
+	maxSize int
+	onError func(err error)
+
+	mu      sync.Mutex
+	buffer  []T
+	stopped bool
+
+	flushMu  sync.Mutex
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// Option Processor 的可选配置
+type Option func(*options)
+
+type options struct {
+	onError func(err error)
+}
+
+// WithErrorHandler 设置定时刷新失败时的回调。Add、Flush 和 Stop 触发的刷新直接返回错误，不经过该回调；
+// 默认以 Error 级别写入 logger.L()
+func WithErrorHandler(fn func(err error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+// New 创建批处理器并启动定时刷新，maxWait <= 0 时只按数量刷新
+func New[T any](flushFn func(ctx context.Context, items []T) error, maxSize int, maxWait time.Duration, opts ...Option) *Processor[T] {
+	if maxSize <= 0 {
+		maxSize = 1
+	}
+	o := options{onError: logFlushError}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	p := &Processor[T]{
+		flushFn: flushFn,
+		maxSize: maxSize,
+		onError: o.onError,
+		buffer:  make([]T, 0, maxSize),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go p.loop(maxWait)
+	return p
+}
+
+// logFlushError 默认的定时刷新错误处理
+func logFlushError(err error) {
+	logger.L().Error("batch: periodic flush failed", zap.Error(err))
+}
+
+// Add 加入一条数据，缓存达到 maxSize 时在当前 goroutine 中刷新并返回刷新结果
+func (p *Processor[T]) Add(ctx context.Context, item T) error {
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return ErrStopped
+	}
+	p.buffer = append(p.buffer, item)
+	full := len(p.buffer) >= p.maxSize
+	p.mu.Unlock()
+
+	if full {
+		return p.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush 立即刷新当前缓存的所有数据
+func (p *Processor[T]) Flush(ctx context.Context) error {
+	p.flushMu.Lock()
+	defer p.flushMu.Unlock()
+
+	p.mu.Lock()
+	items := p.buffer
+	p.buffer = make([]T, 0, p.maxSize)
+	p.mu.Unlock()
+
+	if len(items) == 0 {
+		return nil
+	}
+	return p.flushFn(ctx, items)
+}
+
+// Stop 停止定时刷新并刷新剩余数据，之后 Add 返回 ErrStopped；可重复调用
+func (p *Processor[T]) Stop(ctx context.Context) error {
+	p.mu.Lock()
+	p.stopped = true
+	p.mu.Unlock()
+
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return p.Flush(ctx)
+}
+
+func (p *Processor[T]) loop(maxWait time.Duration) {
+	defer close(p.done)
+	var tick <-chan time.Time
+	if maxWait > 0 {
+		ticker := time.NewTicker(maxWait)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-tick:
+			if err := p.Flush(context.Background()); err != nil && p.onError != nil {
+				p.onError(err)
+			}
+		case <-p.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kobyt2/common-services/logger"
+	"go.uber.org/zap/zapcore"
+)
+
+// recorder 记录每次 flushFn 收到的批次
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (r *recorder) flush(ctx context.Context, items []int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.batches = append(r.batches, append([]int(nil), items...))
+	return r.err
+}
+
+func (r *recorder) snapshot() [][]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]int(nil), r.batches...)
+}
+
+func (r *recorder) total() int {
+	n := 0
+	for _, batch := range r.snapshot() {
+		n += len(batch)
+	}
+	return n
+}
+
+func TestExactlyMaxSizeTriggersFlush(t *testing.T) {
+	rec := &recorder{}
+	p := New(rec.flush, 3, 0)
+	defer p.Stop(context.Background())
+	ctx := context.Background()
+
+	for i := 1; i <= 2; i++ {
+		if err := p.Add(ctx, i); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	if batches := rec.snapshot(); len(batches) != 0 {
+		t.Fatalf("flushed %v before reaching maxSize", batches)
+	}
+
+	if err := p.Add(ctx, 3); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	batches := rec.snapshot()
+	if len(batches) != 1 || len(batches[0]) != 3 {
+		t.Fatalf("batches = %v, want one batch of exactly 3 items", batches)
+	}
+
+	// 缓存已清空，下一批重新计数
+	p.Add(ctx, 4)
+	if batches := rec.snapshot(); len(batches) != 1 {
+		t.Errorf("batches = %v, want no flush after a single item", batches)
+	}
+}
+
+func TestAddReturnsFlushError(t *testing.T) {
+	errWrite := errors.New("write failed")
+	rec := &recorder{err: errWrite}
+	p := New(rec.flush, 1, 0)
+	defer p.Stop(context.Background())
+
+	if err := p.Add(context.Background(), 1); !errors.Is(err, errWrite) {
+		t.Errorf("Add: err = %v, want the flush error", err)
+	}
+}
+
+func TestStopFlushesRemaining(t *testing.T) {
+	rec := &recorder{}
+	p := New(rec.flush, 100, time.Hour)
+	ctx := context.Background()
+
+	const items = 250
+	var wg sync.WaitGroup
+	for w := 0; w < 5; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < items/5; i++ {
+				if err := p.Add(ctx, w*1000+i); err != nil {
+					t.Errorf("Add: %v", err)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if err := p.Stop(ctx); err != nil {
+		t.Fatalf("Stop: %v", err)
+	}
+	if n := rec.total(); n != items {
+		t.Errorf("%d items flushed after Stop, want all %d", n, items)
+	}
+	seen := make(map[int]bool)
+	for _, batch := range rec.snapshot() {
+		for _, item := range batch {
+			if seen[item] {
+				t.Errorf("item %d flushed twice", item)
+			}
+			seen[item] = true
+		}
+	}
+
+	if err := p.Add(ctx, 1); !errors.Is(err, ErrStopped) {
+		t.Errorf("Add after Stop: err = %v, want ErrStopped", err)
+	}
+	if err := p.Stop(ctx); err != nil {
+		t.Errorf("second Stop: %v", err)
+	}
+}
+
+func TestPeriodicFlush(t *testing.T) {
+	rec := &recorder{}
+	p := New(rec.flush, 100, 10*time.Millisecond)
+	defer p.Stop(context.Background())
+
+	p.Add(context.Background(), 1)
+	p.Add(context.Background(), 2)
+	deadline := time.Now().Add(2 * time.Second)
+	for rec.total() != 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("pending items were not flushed by the ticker")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPeriodicFlushErrorHandler(t *testing.T) {
+	errWrite := errors.New("write failed")
+	rec := &recorder{err: errWrite}
+	errs := make(chan error, 10)
+	p := New(rec.flush, 100, 10*time.Millisecond, WithErrorHandler(func(err error) { errs <- err }))
+	defer p.Stop(context.Background())
+
+	p.Add(context.Background(), 1)
+	select {
+	case err := <-errs:
+		if !errors.Is(err, errWrite) {
+			t.Errorf("handler got %v, want the flush error", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("error handler not called for a failed periodic flush")
+	}
+}
+
+func TestPeriodicFlushErrorLoggedByDefault(t *testing.T) {
+	logs := logger.ObserveGlobal(t, zapcore.DebugLevel)
+	errWrite := errors.New("write failed")
+	p := New((&recorder{err: errWrite}).flush, 100, 10*time.Millisecond)
+
+	p.Add(context.Background(), 1)
+	deadline := time.Now().Add(2 * time.Second)
+	for logs.FilterMessage("batch: periodic flush failed").Len() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("periodic flush error not logged, entries: %v", logs.All())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	// 先停止处理器，再由 cleanup 恢复全局 logger
+	p.Stop(context.Background())
+
+	entry := logs.FilterMessage("batch: periodic flush failed").All()[0]
+	if entry.Level != zapcore.ErrorLevel || entry.ContextMap()["error"] != "write failed" {
+		t.Errorf("entry = %v %v", entry.Level, entry.ContextMap())
+	}
+}
@@ -1,18 +1,47 @@
 package utils
 
-import "golang.org/x/crypto/bcrypt"
+import (
+  "fmt"
+
+  "golang.org/x/crypto/bcrypt"
+)
 
 // 加密密码
 func GenerateFromPassword(password string) (string, error) {
-  hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+  return GenerateFromPasswordWithCost(password, bcrypt.DefaultCost)
+}
+
+// 使用指定的 cost 加密密码，cost 须在 bcrypt.MinCost 与 bcrypt.MaxCost 之间
+func GenerateFromPasswordWithCost(password string, cost int) (string, error) {
+  if err := checkCost(cost); err != nil {
+      return "", err
+  }
+  hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), cost)
   if err != nil {
       return "", err
   }
   return string(hashedPassword), nil
 }
 
+// 检查 cost 是否在 bcrypt.MinCost 与 bcrypt.MaxCost 之间
+func checkCost(cost int) error {
+  if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+      return fmt.Errorf("bcrypt: cost %d out of range [%d, %d]", cost, bcrypt.MinCost, bcrypt.MaxCost)
+  }
+  return nil
+}
+
 // 校验密码
 func CompareHashAndPassword(hashedPassword string, password string) bool {
   err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
   return err == nil
 }
+
+// 判断已有哈希的 cost 是否低于 cost，校验密码成功后可据此重新生成哈希；无法解析的哈希也返回 true
+func NeedsRehash(hashedPassword string, cost int) bool {
+  current, err := bcrypt.Cost([]byte(hashedPassword))
+  if err != nil {
+      return true
+  }
+  return current < cost
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestGenerateFromPasswordWithCostBounds(t *testing.T) {
+	for _, cost := range []int{bcrypt.MinCost - 1, bcrypt.MaxCost + 1} {
+		if hash, err := GenerateFromPasswordWithCost("secret", cost); err == nil || hash != "" {
+			t.Errorf("GenerateFromPasswordWithCost(cost %d) = %q, %v; want an error", cost, hash, err)
+		}
+	}
+
+	hash, err := GenerateFromPasswordWithCost("secret", bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPasswordWithCost(MinCost): %v", err)
+	}
+	if cost, err := bcrypt.Cost([]byte(hash)); err != nil || cost != bcrypt.MinCost {
+		t.Errorf("hash cost = %d, %v; want %d", cost, err, bcrypt.MinCost)
+	}
+	if !CompareHashAndPassword(hash, "secret") || CompareHashAndPassword(hash, "wrong") {
+		t.Error("CompareHashAndPassword does not match the generated hash")
+	}
+}
+
+// MaxCost 需要 2^31 轮计算，无法在测试中实际生成，只校验范围检查
+func TestCheckCostAcceptsBounds(t *testing.T) {
+	for _, cost := range []int{bcrypt.MinCost, bcrypt.DefaultCost, bcrypt.MaxCost} {
+		if err := checkCost(cost); err != nil {
+			t.Errorf("checkCost(%d) = %v, want nil", cost, err)
+		}
+	}
+	for _, cost := range []int{bcrypt.MinCost - 1, bcrypt.MaxCost + 1} {
+		if err := checkCost(cost); err == nil {
+			t.Errorf("checkCost(%d) = nil, want an error", cost)
+		}
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	hash, err := GenerateFromPasswordWithCost("secret", bcrypt.MinCost+1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		hash string
+		cost int
+		want bool
+	}{
+		{"lower than target", hash, bcrypt.MinCost + 2, true},
+		{"equal to target", hash, bcrypt.MinCost + 1, false},
+		{"higher than target", hash, bcrypt.MinCost, false},
+		{"unparsable hash", "not a bcrypt hash", bcrypt.MinCost, true},
+		{"empty hash", "", bcrypt.MinCost, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NeedsRehash(tt.hash, tt.cost); got != tt.want {
+				t.Errorf("NeedsRehash(cost %d) = %v, want %v", tt.cost, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// entry 缓存项，expiresAt 为零值表示不过期
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// Cache 并发安全的泛型内存缓存，过期项在读取时惰性删除
+type Cache[K comparable, V any] struct {
+	mu    sync.RWMutex
+	items map[K]entry[V]
+}
+
+// New 创建新的内存缓存
+func New[K comparable, V any]() *Cache[K, V] {
+	return &Cache[K, V]{items: make(map[K]entry[V])}
+}
+
+// Get 读取缓存，不存在或已过期时返回 false
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.RLock()
+	item, ok := c.items[key]
+	c.mu.RUnlock()
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	if !item.expiresAt.IsZero() && time.Now().After(item.expiresAt) {
+		c.mu.Lock()
+		// 加写锁期间可能已被重新写入，确认仍是过期项再删除
+		if current, ok := c.items[key]; ok && current.expiresAt.Equal(item.expiresAt) {
+			delete(c.items, key)
+		}
+		c.mu.Unlock()
+		var zero V
+		return zero, false
+	}
+	return item.value, true
+}
+
+// Set 写入缓存，ttl <= 0 表示不过期
+func (c *Cache[K, V]) Set(key K, value V, ttl time.Duration) {
+	item := entry[V]{value: value}
+	if ttl > 0 {
+		item.expiresAt = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	c.items[key] = item
+	c.mu.Unlock()
+}
+
+// Delete 删除缓存
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	delete(c.items, key)
+	c.mu.Unlock()
+}
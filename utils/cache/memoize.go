@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Memoize 为耗时的纯函数增加缓存：先查缓存，未命中时调用 fn 并以 ttl 写入缓存；
+// 同一个 key 的并发调用通过该 key 独有的 singleflight.Group 合并，fn 只执行一次。出错的结果不会被缓存。
+// 合并后的 fn 收到的是 context.WithoutCancel(ctx)：保留首个调用方 ctx 中的值，但不受其取消影响，
+// 避免一个调用方取消导致同一 key 上等待的其他调用方都得到 context.Canceled
+func Memoize[K comparable, V any](fn func(ctx context.Context, key K) (V, error), cache *Cache[K, V], ttl time.Duration) func(ctx context.Context, key K) (V, error) {
+	var (
+		mu     sync.Mutex
+		groups = make(map[K]*singleflight.Group)
+	)
+	return func(ctx context.Context, key K) (V, error) {
+		if value, ok := cache.Get(key); ok {
+			return value, nil
+		}
+
+		mu.Lock()
+		group, ok := groups[key]
+		if !ok {
+			group = &singleflight.Group{}
+			groups[key] = group
+		}
+		mu.Unlock()
+
+		result, err, _ := group.Do("", func() (interface{}, error) {
+			defer func() {
+				mu.Lock()
+				delete(groups, key)
+				mu.Unlock()
+			}()
+			// 等待期间其他调用可能已写入缓存
+			if value, ok := cache.Get(key); ok {
+				return value, nil
+			}
+			value, err := fn(context.WithoutCancel(ctx), key)
+			if err != nil {
+				return value, err
+			}
+			cache.Set(key, value, ttl)
+			return value, nil
+		})
+		value, _ := result.(V)
+		return value, err
+	}
+}
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoizeConcurrentCallsRunOnce(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	slow := func(ctx context.Context, key string) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return len(key), nil
+	}
+	memoized := Memoize(slow, New[string, int](), time.Minute)
+
+	const callers = 20
+	results := make([]int, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = memoized(context.Background(), "hello")
+		}(i)
+	}
+	// 等所有调用方进入 singleflight 后再放行
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("fn called %d times, want 1", n)
+	}
+	for i := range results {
+		if errs[i] != nil || results[i] != 5 {
+			t.Errorf("caller %d = %d, %v, want 5, nil", i, results[i], errs[i])
+		}
+	}
+
+	// 之后的调用直接命中缓存
+	if v, err := memoized(context.Background(), "hello"); err != nil || v != 5 {
+		t.Errorf("cached call = %d, %v", v, err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("fn called %d times after a cache hit, want 1", n)
+	}
+}
+
+func TestMemoizeDifferentKeys(t *testing.T) {
+	var calls int32
+	fn := func(ctx context.Context, key int) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		return key * 2, nil
+	}
+	memoized := Memoize(fn, New[int, int](), time.Minute)
+
+	for _, key := range []int{1, 2, 1, 2} {
+		if v, _ := memoized(context.Background(), key); v != key*2 {
+			t.Errorf("memoized(%d) = %d", key, v)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("fn called %d times, want 2", n)
+	}
+}
+
+func TestMemoizeDoesNotCacheErrors(t *testing.T) {
+	var calls int32
+	errBoom := errors.New("boom")
+	fn := func(ctx context.Context, key string) (*int, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, errBoom
+	}
+	memoized := Memoize(fn, New[string, *int](), time.Minute)
+
+	for i := 0; i < 2; i++ {
+		if v, err := memoized(context.Background(), "k"); !errors.Is(err, errBoom) || v != nil {
+			t.Errorf("call %d = %v, %v, want nil, errBoom", i, v, err)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("fn called %d times, want 2", n)
+	}
+}
+
+func TestMemoizeIgnoresCallerCancellation(t *testing.T) {
+	fn := func(ctx context.Context, key string) (string, error) {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		return key, nil
+	}
+	memoized := Memoize(fn, New[string, string](), time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if v, err := memoized(ctx, "k"); err != nil || v != "k" {
+		t.Errorf("memoized with canceled ctx = %q, %v, want k, nil", v, err)
+	}
+}
@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keySeparator Redis key 各层级之间的分隔符
+const keySeparator = ":"
+
+// scanBatchSize FlushNamespace 每次 SCAN 的数量
+const scanBatchSize = 100
+
+// RedisCache 基于 Redis 的缓存，key 格式为 <keyPrefix>:<namespace>:<key>，
+// 多个服务共用同一 Redis 集群时通过前缀和命名空间隔离
+type RedisCache struct {
+	client    redis.UniversalClient
+	keyPrefix string
+	namespace string
+}
+
+// RedisOption RedisCache 的可选配置
+type RedisOption func(*RedisCache)
+
+// WithNamespace 在 keyPrefix 之下再增加一层命名空间
+func WithNamespace(ns string) RedisOption {
+	return func(c *RedisCache) {
+		c.namespace = ns
+	}
+}
+
+// NewRedisCache 创建新的 RedisCache 实例
+func NewRedisCache(client redis.UniversalClient, keyPrefix string, opts ...RedisOption) *RedisCache {
+	c := &RedisCache{client: client, keyPrefix: keyPrefix}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Key 返回 key 在 Redis 中的完整名称
+func (c *RedisCache) Key(key string) string {
+	return c.scope() + key
+}
+
+// Get 读取缓存，key 不存在时返回 false
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, c.Key(key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Set 写入缓存，ttl 为 0 表示不过期
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, c.Key(key), value, ttl).Err()
+}
+
+// Delete 删除缓存
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, c.Key(key)).Err()
+}
+
+// FlushNamespace 使用 SCAN + DEL 删除当前前缀和命名空间下的所有 key，不会使用 FLUSHDB
+func (c *RedisCache) FlushNamespace(ctx context.Context) error {
+	scope := c.scope()
+	if scope == "" {
+		return errors.New("cache: refusing to flush without key prefix or namespace")
+	}
+	pattern := escapeGlob(scope) + "*"
+	if cluster, ok := c.client.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return scanDelete(ctx, node, pattern)
+		})
+	}
+	return scanDelete(ctx, c.client, pattern)
+}
+
+// scope 返回 "<keyPrefix>:<namespace>:"，忽略为空的层级
+func (c *RedisCache) scope() string {
+	var b strings.Builder
+	for _, part := range []string{c.keyPrefix, c.namespace} {
+		if part != "" {
+			b.WriteString(part)
+			b.WriteString(keySeparator)
+		}
+	}
+	return b.String()
+}
+
+// scanDelete 先用 SCAN 收集匹配 pattern 的所有 key，再分批删除，避免边扫描边删除时游标跳过 key；
+// 同一批 key 可能位于不同的 hash slot，集群模式下多 key DEL 会返回 CROSSSLOT，因此通过 pipeline 逐个 DEL
+func scanDelete(ctx context.Context, client redis.Cmdable, pattern string) error {
+	var (
+		cursor uint64
+		keys   []string
+	)
+	for {
+		batch, next, err := client.Scan(ctx, cursor, pattern, scanBatchSize).Result()
+		if err != nil {
+			return err
+		}
+		keys = append(keys, batch...)
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+	for start := 0; start < len(keys); start += scanBatchSize {
+		batch := keys[start:min(start+scanBatchSize, len(keys))]
+		_, err := client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, key := range batch {
+				pipe.Del(ctx, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeGlob 转义 SCAN MATCH 中的通配符
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return mr, client
+}
+
+func TestRedisCacheNamespacesDoNotInterfere(t *testing.T) {
+	mr, client := newTestRedis(t)
+	ctx := context.Background()
+	orders := NewRedisCache(client, "svc", WithNamespace("orders"))
+	users := NewRedisCache(client, "svc", WithNamespace("users"))
+
+	if err := orders.Set(ctx, "42", []byte("order"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := users.Set(ctx, "42", []byte("user"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := orders.Key("42"); got != "svc:orders:42" {
+		t.Errorf("Key = %q, want svc:orders:42", got)
+	}
+	if v, _ := mr.Get("svc:users:42"); v != "user" {
+		t.Errorf("raw users key = %q", v)
+	}
+
+	for c, want := range map[*RedisCache]string{orders: "order", users: "user"} {
+		got, ok, err := c.Get(ctx, "42")
+		if err != nil || !ok || string(got) != want {
+			t.Errorf("Get = %q, %v, %v; want %q", got, ok, err, want)
+		}
+	}
+
+	if err := orders.Delete(ctx, "42"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := orders.Get(ctx, "42"); ok {
+		t.Error("deleted key still present")
+	}
+	if _, ok, _ := users.Get(ctx, "42"); !ok {
+		t.Error("Delete in one namespace removed the key of another")
+	}
+}
+
+func TestRedisCacheFlushNamespace(t *testing.T) {
+	mr, client := newTestRedis(t)
+	ctx := context.Background()
+	// 前缀中包含 glob 通配符时也只删除自己的 key
+	target := NewRedisCache(client, "svc*", WithNamespace("a"))
+	other := NewRedisCache(client, "svc-other", WithNamespace("a"))
+	sibling := NewRedisCache(client, "svc*", WithNamespace("ab"))
+
+	// 超过一次 SCAN 的数量，覆盖多批删除
+	for i := 0; i < 3*scanBatchSize; i++ {
+		target.Set(ctx, fmt.Sprint(i), []byte("x"), 0)
+	}
+	other.Set(ctx, "1", []byte("x"), 0)
+	sibling.Set(ctx, "1", []byte("x"), 0)
+
+	if err := target.FlushNamespace(ctx); err != nil {
+		t.Fatalf("FlushNamespace: %v", err)
+	}
+	keys := mr.Keys()
+	if len(keys) != 2 {
+		t.Fatalf("remaining keys = %v, want only the other namespaces", keys)
+	}
+	for _, c := range []*RedisCache{other, sibling} {
+		if _, ok, _ := c.Get(ctx, "1"); !ok {
+			t.Errorf("key of %s flushed", c.Key("1"))
+		}
+	}
+
+	if err := NewRedisCache(client, "").FlushNamespace(ctx); err == nil {
+		t.Error("FlushNamespace without prefix or namespace returned no error")
+	}
+}
+
+// delArgsHook 记录每条 DEL 命令携带的 key 数量
+type delArgsHook struct{ maxKeys int }
+
+func (h *delArgsHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h *delArgsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.record(cmd)
+		return next(ctx, cmd)
+	}
+}
+
+func (h *delArgsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			h.record(cmd)
+		}
+		return next(ctx, cmds)
+	}
+}
+
+func (h *delArgsHook) record(cmd redis.Cmder) {
+	if cmd.Name() == "del" && len(cmd.Args())-1 > h.maxKeys {
+		h.maxKeys = len(cmd.Args()) - 1
+	}
+}
+
+func TestFlushNamespaceDeletesKeysIndividually(t *testing.T) {
+	_, client := newTestRedis(t)
+	ctx := context.Background()
+	hook := &delArgsHook{}
+	client.AddHook(hook)
+	c := NewRedisCache(client, "svc")
+	for i := 0; i < 10; i++ {
+		c.Set(ctx, fmt.Sprint(i), []byte("x"), 0)
+	}
+	if err := c.FlushNamespace(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// 集群中不同 slot 的 key 不能放在同一条 DEL 中
+	if hook.maxKeys != 1 {
+		t.Errorf("DEL sent with %d keys, want one key per command", hook.maxKeys)
+	}
+}
@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Store 以字节为值的缓存存储接口，RedisCache 与 MemoryStore 均实现了该接口
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// MemoryStore 基于 Cache 的进程内 Store 实现
+type MemoryStore struct {
+	cache *Cache[string, []byte]
+}
+
+// NewMemoryStore 创建进程内的 Store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{cache: New[string, []byte]()}
+}
+
+// Get 读取缓存，key 不存在或已过期时返回 false
+func (s *MemoryStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, ok := s.cache.Get(key)
+	return value, ok, nil
+}
+
+// Set 写入缓存，ttl <= 0 表示不过期
+func (s *MemoryStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.cache.Set(key, value, ttl)
+	return nil
+}
+
+// Delete 删除缓存
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.cache.Delete(key)
+	return nil
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+// WarmEntry 预热写入的一条缓存项，TTL <= 0 表示不过期
+type WarmEntry[K comparable, V any] struct {
+	Key   K
+	Value V
+	TTL   time.Duration
+}
+
+// Warmer 缓存预热器，在服务启动时向缓存写入数据
+type Warmer[K comparable, V any] interface {
+	Warm(ctx context.Context, cache *Cache[K, V]) error
+}
+
+// SliceWarmer 将固定的一组缓存项写入缓存
+type SliceWarmer[K comparable, V any] []WarmEntry[K, V]
+
+// Warm 写入所有缓存项
+func (w SliceWarmer[K, V]) Warm(ctx context.Context, cache *Cache[K, V]) error {
+	setAll(cache, w)
+	return nil
+}
+
+// LoaderWarmer 调用 loader 从数据库或外部服务获取缓存项并写入缓存
+type LoaderWarmer[K comparable, V any] func(ctx context.Context) ([]WarmEntry[K, V], error)
+
+// Warm 调用 loader 并写入返回的缓存项，loader 出错时不写入任何数据
+func (w LoaderWarmer[K, V]) Warm(ctx context.Context, cache *Cache[K, V]) error {
+	entries, err := w(ctx)
+	if err != nil {
+		return err
+	}
+	setAll(cache, entries)
+	return nil
+}
+
+// WarmOnStartup 并发运行所有预热器，返回合并后的错误
+func WarmOnStartup[K comparable, V any](ctx context.Context, cache *Cache[K, V], warmers ...Warmer[K, V]) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs error
+	)
+	for _, w := range warmers {
+		wg.Add(1)
+		go func(w Warmer[K, V]) {
+			defer wg.Done()
+			if err := w.Warm(ctx, cache); err != nil {
+				mu.Lock()
+				errs = multierr.Append(errs, err)
+				mu.Unlock()
+			}
+		}(w)
+	}
+	wg.Wait()
+	return errs
+}
+
+// setAll 将 entries 写入缓存
+func setAll[K comparable, V any](cache *Cache[K, V], entries []WarmEntry[K, V]) {
+	for _, e := range entries {
+		cache.Set(e.Key, e.Value, e.TTL)
+	}
+}
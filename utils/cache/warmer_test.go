@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+func TestWarmOnStartup(t *testing.T) {
+	c := New[string, int]()
+	static := SliceWarmer[string, int]{
+		{Key: "a", Value: 1},
+		{Key: "b", Value: 2, TTL: time.Minute},
+	}
+	loader := LoaderWarmer[string, int](func(ctx context.Context) ([]WarmEntry[string, int], error) {
+		return []WarmEntry[string, int]{{Key: "c", Value: 3}, {Key: "d", Value: 4}}, nil
+	})
+
+	if err := WarmOnStartup[string, int](context.Background(), c, static, loader); err != nil {
+		t.Fatalf("WarmOnStartup: %v", err)
+	}
+	for key, want := range map[string]int{"a": 1, "b": 2, "c": 3, "d": 4} {
+		if got, ok := c.Get(key); !ok || got != want {
+			t.Errorf("Get(%q) = %d, %v, want %d", key, got, ok, want)
+		}
+	}
+}
+
+func TestWarmOnStartupCollectsErrors(t *testing.T) {
+	c := New[string, int]()
+	errDB, errRPC := errors.New("db down"), errors.New("rpc timeout")
+	failing := func(err error) Warmer[string, int] {
+		return LoaderWarmer[string, int](func(ctx context.Context) ([]WarmEntry[string, int], error) {
+			return []WarmEntry[string, int]{{Key: "partial", Value: 1}}, err
+		})
+	}
+	ok := SliceWarmer[string, int]{{Key: "a", Value: 1}}
+
+	err := WarmOnStartup(context.Background(), c, failing(errDB), ok, failing(errRPC))
+	if !errors.Is(err, errDB) || !errors.Is(err, errRPC) || len(multierr.Errors(err)) != 2 {
+		t.Fatalf("err = %v, want both loader errors", err)
+	}
+	if _, found := c.Get("a"); !found {
+		t.Error("entries of the successful warmer are missing")
+	}
+	// loader 出错时不写入它返回的数据
+	if _, found := c.Get("partial"); found {
+		t.Error("entries of a failed loader were written")
+	}
+}
+
+func TestLoaderWarmerReceivesContext(t *testing.T) {
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "v")
+	var got interface{}
+	loader := LoaderWarmer[string, int](func(ctx context.Context) ([]WarmEntry[string, int], error) {
+		got = ctx.Value(ctxKey{})
+		return nil, nil
+	})
+	if err := WarmOnStartup[string, int](ctx, New[string, int](), loader); err != nil {
+		t.Fatal(err)
+	}
+	if got != "v" {
+		t.Errorf("loader ctx value = %v, want the WarmOnStartup context", got)
+	}
+}
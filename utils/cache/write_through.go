@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// WriteThroughCache 写穿缓存：先写入持久化存储，成功后再更新缓存，保证缓存中不会出现未持久化的数据
+type WriteThroughCache[K comparable, V any] struct {
+	cache   *Cache[K, V]
+	persist func(ctx context.Context, k K, v V) error
+	remove  func(ctx context.Context, k K) error
+}
+
+// WriteThrough 创建写穿缓存，persist 负责写入、remove 负责删除持久化数据
+func WriteThrough[K comparable, V any](cache *Cache[K, V], persist func(ctx context.Context, k K, v V) error, remove func(ctx context.Context, k K) error) *WriteThroughCache[K, V] {
+	return &WriteThroughCache[K, V]{cache: cache, persist: persist, remove: remove}
+}
+
+// Get 读取缓存
+func (w *WriteThroughCache[K, V]) Get(k K) (V, bool) {
+	return w.cache.Get(k)
+}
+
+// Set 先调用 persist，成功后写入缓存；persist 失败时缓存保持不变
+func (w *WriteThroughCache[K, V]) Set(ctx context.Context, k K, v V, ttl time.Duration) error {
+	if err := w.persist(ctx, k, v); err != nil {
+		return err
+	}
+	w.cache.Set(k, v, ttl)
+	return nil
+}
+
+// Delete 先调用 remove，成功后删除缓存；remove 为 nil 时只删除缓存
+func (w *WriteThroughCache[K, V]) Delete(ctx context.Context, k K) error {
+	if w.remove != nil {
+		if err := w.remove(ctx, k); err != nil {
+			return err
+		}
+	}
+	w.cache.Delete(k)
+	return nil
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeBackend 记录写入和删除的模拟持久化存储
+type fakeBackend struct {
+	data       map[string]int
+	persistErr error
+	removeErr  error
+}
+
+func (b *fakeBackend) persist(ctx context.Context, k string, v int) error {
+	if b.persistErr != nil {
+		return b.persistErr
+	}
+	b.data[k] = v
+	return nil
+}
+
+func (b *fakeBackend) remove(ctx context.Context, k string) error {
+	if b.removeErr != nil {
+		return b.removeErr
+	}
+	delete(b.data, k)
+	return nil
+}
+
+func newWriteThrough() (*WriteThroughCache[string, int], *fakeBackend) {
+	backend := &fakeBackend{data: make(map[string]int)}
+	return WriteThrough(New[string, int](), backend.persist, backend.remove), backend
+}
+
+func TestWriteThroughSet(t *testing.T) {
+	w, backend := newWriteThrough()
+	ctx := context.Background()
+
+	if err := w.Set(ctx, "a", 1, time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if v, ok := w.Get("a"); !ok || v != 1 {
+		t.Errorf("Get = %d, %v, want 1", v, ok)
+	}
+	if backend.data["a"] != 1 {
+		t.Errorf("backend = %v, want a persisted", backend.data)
+	}
+}
+
+func TestWriteThroughPersistFailureKeepsCache(t *testing.T) {
+	w, backend := newWriteThrough()
+	ctx := context.Background()
+	if err := w.Set(ctx, "a", 1, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	errWrite := errors.New("write failed")
+	backend.persistErr = errWrite
+	if err := w.Set(ctx, "a", 2, time.Minute); !errors.Is(err, errWrite) {
+		t.Fatalf("Set: err = %v, want the persist error", err)
+	}
+	if err := w.Set(ctx, "b", 3, time.Minute); !errors.Is(err, errWrite) {
+		t.Fatalf("Set: err = %v, want the persist error", err)
+	}
+	if v, _ := w.Get("a"); v != 1 {
+		t.Errorf("Get(a) = %d after a failed persist, want the old value 1", v)
+	}
+	if _, ok := w.Get("b"); ok {
+		t.Error("Get(b) found a value that was never persisted")
+	}
+}
+
+func TestWriteThroughDelete(t *testing.T) {
+	w, backend := newWriteThrough()
+	ctx := context.Background()
+	w.Set(ctx, "a", 1, time.Minute)
+
+	errRemove := errors.New("remove failed")
+	backend.removeErr = errRemove
+	if err := w.Delete(ctx, "a"); !errors.Is(err, errRemove) {
+		t.Fatalf("Delete: err = %v, want the remove error", err)
+	}
+	if _, ok := w.Get("a"); !ok {
+		t.Error("cache entry removed although remove failed")
+	}
+
+	backend.removeErr = nil
+	if err := w.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := w.Get("a"); ok {
+		t.Error("cache entry still present after Delete")
+	}
+	if _, ok := backend.data["a"]; ok {
+		t.Error("backend entry still present after Delete")
+	}
+}
+
+func TestWriteThroughNilRemove(t *testing.T) {
+	backend := &fakeBackend{data: make(map[string]int)}
+	w := WriteThrough(New[string, int](), backend.persist, nil)
+	ctx := context.Background()
+	w.Set(ctx, "a", 1, time.Minute)
+
+	if err := w.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := w.Get("a"); ok {
+		t.Error("cache entry still present after Delete")
+	}
+}
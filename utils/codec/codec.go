@@ -0,0 +1,67 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"mime"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec 与序列化格式无关的编解码接口
+type Codec interface {
+	Encode(v interface{}) ([]byte, error)
+	Decode(data []byte, v interface{}) error
+	ContentType() string
+}
+
+// JSONCodec 使用 encoding/json 编解码
+type JSONCodec struct{}
+
+func (JSONCodec) Encode(v interface{}) ([]byte, error)    { return json.Marshal(v) }
+func (JSONCodec) Decode(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (JSONCodec) ContentType() string                     { return "application/json" }
+
+// MsgpackCodec 使用 MessagePack 编解码
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) Encode(v interface{}) ([]byte, error)    { return msgpack.Marshal(v) }
+func (MsgpackCodec) Decode(data []byte, v interface{}) error { return msgpack.Unmarshal(data, v) }
+func (MsgpackCodec) ContentType() string                     { return "application/msgpack" }
+
+// GobCodec 使用 encoding/gob 编解码，仅适用于 Go 服务之间
+type GobCodec struct{}
+
+func (GobCodec) Encode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Decode(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+func (GobCodec) ContentType() string { return "application/x-gob" }
+
+// NewCodecForContentType 根据 MIME 类型返回对应的编解码器，忽略 charset 等参数
+func NewCodecForContentType(ct string) (Codec, error) {
+	mediaType, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return nil, fmt.Errorf("codec: invalid content type %q: %v", ct, err)
+	}
+	switch mediaType {
+	case "application/json":
+		return JSONCodec{}, nil
+	case "application/msgpack", "application/x-msgpack":
+		return MsgpackCodec{}, nil
+	case "application/x-gob":
+		return GobCodec{}, nil
+	default:
+		return nil, fmt.Errorf("codec: unsupported content type %q", mediaType)
+	}
+}
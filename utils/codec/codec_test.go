@@ -0,0 +1,83 @@
+package codec
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type payload struct {
+	ID        int64             `json:"id" msgpack:"id"`
+	Name      string            `json:"name" msgpack:"name"`
+	Tags      []string          `json:"tags" msgpack:"tags"`
+	Attrs     map[string]string `json:"attrs" msgpack:"attrs"`
+	Score     float64           `json:"score" msgpack:"score"`
+	Active    bool              `json:"active" msgpack:"active"`
+	CreatedAt time.Time         `json:"created_at" msgpack:"created_at"`
+}
+
+func TestCodecsRoundTrip(t *testing.T) {
+	in := payload{
+		ID:        42,
+		Name:      "订单",
+		Tags:      []string{"a", "b"},
+		Attrs:     map[string]string{"region": "cn"},
+		Score:     3.5,
+		Active:    true,
+		CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	for _, c := range []Codec{JSONCodec{}, MsgpackCodec{}, GobCodec{}} {
+		t.Run(c.ContentType(), func(t *testing.T) {
+			data, err := c.Encode(in)
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			var out payload
+			if err := c.Decode(data, &out); err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			// 比较前统一时区，msgpack 解码出的时间为本地时区
+			out.CreatedAt = out.CreatedAt.UTC()
+			if !reflect.DeepEqual(in, out) {
+				t.Errorf("round trip = %+v, want %+v", out, in)
+			}
+		})
+	}
+}
+
+func TestNewCodecForContentType(t *testing.T) {
+	tests := []struct {
+		ct      string
+		want    Codec
+		wantErr bool
+	}{
+		{ct: "application/json", want: JSONCodec{}},
+		{ct: "application/json; charset=utf-8", want: JSONCodec{}},
+		{ct: "application/msgpack", want: MsgpackCodec{}},
+		{ct: "application/x-msgpack", want: MsgpackCodec{}},
+		{ct: "application/x-gob", want: GobCodec{}},
+		{ct: "text/plain", wantErr: true},
+		{ct: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NewCodecForContentType(tt.ct)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NewCodecForContentType(%q) = %T, want error", tt.ct, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NewCodecForContentType(%q): %v", tt.ct, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NewCodecForContentType(%q) = %T, want %T", tt.ct, got, tt.want)
+		}
+	}
+
+	c, _ := NewCodecForContentType("application/json")
+	if _, ok := c.(JSONCodec); !ok {
+		t.Errorf("application/json codec is %T, want JSONCodec", c)
+	}
+}
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+)
+
+// ConstantTimeEqual 以常量时间比较两个字符串，可用于比较 token、签名等敏感值
+//
+// 直接使用 subtle.ConstantTimeCompare 时，长度不同会立即返回，从而泄露长度信息；
+// 这里先对两边做 SHA-256 摘要，再比较等长的摘要，因此比较耗时与输入内容和长度都无关。
+func ConstantTimeEqual(a, b string) bool {
+	return ConstantTimeEqualBytes([]byte(a), []byte(b))
+}
+
+// ConstantTimeEqualBytes 以常量时间比较两个字节切片，规则同 ConstantTimeEqual
+func ConstantTimeEqualBytes(a, b []byte) bool {
+	da := sha256.Sum256(a)
+	db := sha256.Sum256(b)
+	return subtle.ConstantTimeCompare(da[:], db[:]) == 1
+}
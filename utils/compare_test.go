@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConstantTimeEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"equal", "secret-token", "secret-token", true},
+		{"same length differs", "secret-token", "secret-tokem", false},
+		{"different length", "secret", "secret-token", false},
+		{"both empty", "", "", true},
+		{"one empty", "", "x", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConstantTimeEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("ConstantTimeEqual(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+			if got := ConstantTimeEqualBytes([]byte(tt.a), []byte(tt.b)); got != tt.want {
+				t.Errorf("ConstantTimeEqualBytes(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+// BenchmarkSecureCompare 相同长度下相等与不相等（首字节不同、末字节不同）的耗时应在噪声范围内
+func BenchmarkSecureCompare(b *testing.B) {
+	a := strings.Repeat("a", 64)
+	cases := []struct {
+		name  string
+		other string
+	}{
+		{"equal", a},
+		{"unequal_first_byte", "b" + a[1:]},
+		{"unequal_last_byte", a[:63] + "b"},
+	}
+	for _, c := range cases {
+		b.Run(c.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ConstantTimeEqual(a, c.other)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ConfigEntry config_entries 表中的一条配置
+type ConfigEntry struct {
+	Key       string `gorm:"primaryKey;size:255"`
+	Value     string
+	UpdatedAt time.Time
+}
+
+// TableName 指定配置表名
+func (ConfigEntry) TableName() string {
+	return "config_entries"
+}
+
+// KVStore 基于数据库表的运行时配置，服务重启后配置依然保留
+type KVStore struct {
+	db *gorm.DB
+}
+
+// NewKVStore 创建 KVStore 并自动建表
+func NewKVStore(db *gorm.DB) (*KVStore, error) {
+	if err := db.AutoMigrate(&ConfigEntry{}); err != nil {
+		return nil, err
+	}
+	return &KVStore{db: db}, nil
+}
+
+// Get 读取配置，key 不存在时返回 false
+func (s *KVStore) Get(ctx context.Context, key string) (string, bool, error) {
+	var entry ConfigEntry
+	result := s.db.WithContext(ctx).Where(keyEq(key)).Limit(1).Find(&entry)
+	if result.Error != nil {
+		return "", false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return "", false, nil
+	}
+	return entry.Value, true, nil
+}
+
+// Set 写入配置，key 已存在时覆盖
+func (s *KVStore) Set(ctx context.Context, key, value string) error {
+	entry := ConfigEntry{Key: key, Value: value, UpdatedAt: time.Now()}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&entry).Error
+}
+
+// Delete 删除配置
+func (s *KVStore) Delete(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Where(keyEq(key)).Delete(&ConfigEntry{}).Error
+}
+
+// keyEq 生成 key = ? 条件；key 在 MySQL 中是保留字，需要由 GORM 负责加引号
+func keyEq(key string) clause.Eq {
+	return clause.Eq{Column: clause.Column{Name: "key"}, Value: key}
+}
+
+// Watch 每隔 interval 读取一次 key，值发生变化时写入返回的 channel（启动时的当前值不会发送，删除也不会通知）。
+// ctx 结束或调用返回的函数时停止轮询并关闭 channel
+func (s *KVStore) Watch(ctx context.Context, key string, interval time.Duration) (<-chan string, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	ch := make(chan string, 1)
+	last, _, _ := s.Get(ctx, key)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+			value, ok, err := s.Get(ctx, key)
+			if err != nil || !ok || value == last {
+				continue
+			}
+			last = value
+			select {
+			case ch <- value:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
+
+// kvStoreKey 上下文中保存 KVStore 的 key
+type kvStoreKey struct{}
+
+// ErrNoKVStore ctx 中没有通过 WithKVStore 设置 KVStore
+var ErrNoKVStore = errors.New("config: no KVStore in context")
+
+// WithKVStore 返回携带 store 的 ctx，供 GetJSON 使用；Go 的方法不能带类型参数，GetJSON 因此从 ctx 中取得 store
+func WithKVStore(ctx context.Context, store *KVStore) context.Context {
+	return context.WithValue(ctx, kvStoreKey{}, store)
+}
+
+// GetJSON 使用 ctx 中的 KVStore 读取配置并按 JSON 解码为 T，ctx 中没有 KVStore 时返回 ErrNoKVStore
+func GetJSON[T any](ctx context.Context, key string) (T, bool, error) {
+	var v T
+	store, ok := ctx.Value(kvStoreKey{}).(*KVStore)
+	if !ok || store == nil {
+		return v, false, ErrNoKVStore
+	}
+	raw, ok, err := store.Get(ctx, key)
+	if err != nil || !ok {
+		return v, ok, err
+	}
+	if err := json.Unmarshal([]byte(raw), &v); err != nil {
+		return v, true, err
+	}
+	return v, true, nil
+}
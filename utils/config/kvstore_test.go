@@ -0,0 +1,119 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestKVStore(t *testing.T) *KVStore {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 内存库每个连接都是独立的数据库，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	store, err := NewKVStore(db)
+	if err != nil {
+		t.Fatalf("NewKVStore: %v", err)
+	}
+	return store
+}
+
+func TestKVStoreGetSetDelete(t *testing.T) {
+	ctx := context.Background()
+	store := newTestKVStore(t)
+
+	if _, ok, err := store.Get(ctx, "feature"); err != nil || ok {
+		t.Fatalf("Get missing key = %v, %v", ok, err)
+	}
+	if err := store.Set(ctx, "feature", "on"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := store.Set(ctx, "feature", "off"); err != nil {
+		t.Fatalf("Set existing key: %v", err)
+	}
+	if value, ok, err := store.Get(ctx, "feature"); err != nil || !ok || value != "off" {
+		t.Errorf("Get = %q, %v, %v, want off", value, ok, err)
+	}
+	if err := store.Delete(ctx, "feature"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok, _ := store.Get(ctx, "feature"); ok {
+		t.Error("key still present after Delete")
+	}
+}
+
+func TestKVStoreWatch(t *testing.T) {
+	const interval = 50 * time.Millisecond
+	ctx := context.Background()
+	store := newTestKVStore(t)
+	store.Set(ctx, "limit", "10")
+
+	ch, stop := store.Watch(ctx, "limit", interval)
+	defer stop()
+
+	start := time.Now()
+	if err := store.Set(ctx, "limit", "20"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case value := <-ch:
+		if value != "20" {
+			t.Errorf("watched value = %q, want 20", value)
+		}
+		if elapsed := time.Since(start); elapsed > 2*interval {
+			t.Errorf("new value delivered after %v, want within %v", elapsed, 2*interval)
+		}
+	case <-time.After(2 * interval):
+		t.Fatalf("no value within %v", 2*interval)
+	}
+
+	// 值未变化时不重复发送
+	select {
+	case value := <-ch:
+		t.Errorf("unexpected value %q without a change", value)
+	case <-time.After(2 * interval):
+	}
+
+	stop()
+	if _, open := <-ch; open {
+		t.Error("channel not closed after stop")
+	}
+}
+
+func TestGetJSON(t *testing.T) {
+	type limits struct {
+		Max   int      `json:"max"`
+		Users []string `json:"users"`
+	}
+	store := newTestKVStore(t)
+	ctx := WithKVStore(context.Background(), store)
+	store.Set(ctx, "limits", `{"max":5,"users":["a","b"]}`)
+	store.Set(ctx, "broken", `{`)
+
+	got, ok, err := GetJSON[limits](ctx, "limits")
+	if err != nil || !ok || got.Max != 5 || len(got.Users) != 2 {
+		t.Errorf("GetJSON = %+v, %v, %v", got, ok, err)
+	}
+	if _, ok, err := GetJSON[limits](ctx, "missing"); ok || err != nil {
+		t.Errorf("missing key = %v, %v", ok, err)
+	}
+	if _, ok, err := GetJSON[limits](ctx, "broken"); !ok || err == nil {
+		t.Errorf("invalid JSON = %v, %v, want a decode error", ok, err)
+	}
+	if _, _, err := GetJSON[limits](context.Background(), "limits"); !errors.Is(err, ErrNoKVStore) {
+		t.Errorf("without store err = %v, want ErrNoKVStore", err)
+	}
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/yaml.v3"
+)
+
+// Manager 从 YAML 文件加载类型为 T 的配置，文件变更后自动重新加载，无需重启服务
+type Manager[T any] struct {
+	path     string
+	onChange func(old, new T)
+	current  atomic.Value // T
+	watcher  *fsnotify.Watcher
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewManager 加载初始配置并开始监听文件变更。监听的是文件所在目录，目录中的任何变更都会重新读取文件，
+// 因此编辑器以重命名方式保存文件、Kubernetes ConfigMap 替换 ..data 符号链接时同样会触发重新加载；
+// 内容未变化时不做处理，新内容解析失败时保留原配置
+func NewManager[T any](path string, onChange func(old, new T)) (*Manager[T], error) {
+	cfg, err := loadYAML[T](path)
+	if err != nil {
+		return nil, err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	m := &Manager[T]{path: path, onChange: onChange, watcher: watcher, done: make(chan struct{})}
+	m.current.Store(cfg)
+	go m.watch()
+	return m, nil
+}
+
+// Current 返回最新的配置
+func (m *Manager[T]) Current() T {
+	return m.current.Load().(T)
+}
+
+// Close 停止监听文件变更，可重复调用
+func (m *Manager[T]) Close() error {
+	var err error
+	m.once.Do(func() {
+		err = m.watcher.Close()
+		<-m.done
+	})
+	return err
+}
+
+func (m *Manager[T]) watch() {
+	defer close(m.done)
+	for {
+		select {
+		case event, ok := <-m.watcher.Events:
+			if !ok {
+				return
+			}
+			// ConfigMap 中的 path 是经由 ..data 的符号链接，更新时事件只出现在 ..data 上，
+			// 所以不按文件名过滤，由 reload 比较解析后的内容
+			if !event.Has(fsnotify.Write | fsnotify.Create | fsnotify.Rename | fsnotify.Remove) {
+				continue
+			}
+			m.reload()
+		case _, ok := <-m.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// reload 重新读取文件，内容有变化时更新配置并调用 onChange
+func (m *Manager[T]) reload() {
+	cfg, err := loadYAML[T](m.path)
+	if err != nil {
+		return
+	}
+	old := m.Current()
+	if reflect.DeepEqual(old, cfg) {
+		return
+	}
+	m.current.Store(cfg)
+	if m.onChange != nil {
+		m.onChange(old, cfg)
+	}
+}
+
+// loadYAML 读取并解析 YAML 文件
+func loadYAML[T any](path string) (T, error) {
+	var cfg T
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("config: failed to parse %s: %v", path, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type appConfig struct {
+	Timeout int    `yaml:"timeout"`
+	Mode    string `yaml:"mode"`
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitForConfig 等待 Current 返回 want，最多 1 秒
+func waitForConfig(t *testing.T, m *Manager[appConfig], want appConfig) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for m.Current() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("Current() = %+v, want %+v within 1s", m.Current(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestManagerReloadsOnOverwrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	writeFile(t, path, "timeout: 5\nmode: normal\n")
+
+	changes := make(chan [2]appConfig, 4)
+	m, err := NewManager[appConfig](path, func(old, new appConfig) { changes <- [2]appConfig{old, new} })
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	defer m.Close()
+	if m.Current() != (appConfig{Timeout: 5, Mode: "normal"}) {
+		t.Fatalf("initial config = %+v", m.Current())
+	}
+
+	writeFile(t, path, "timeout: 10\nmode: degraded\n")
+	waitForConfig(t, m, appConfig{Timeout: 10, Mode: "degraded"})
+	select {
+	case change := <-changes:
+		if change[0].Timeout != 5 || change[1].Timeout != 10 {
+			t.Errorf("onChange(%+v, %+v)", change[0], change[1])
+		}
+	case <-time.After(time.Second):
+		t.Fatal("onChange not called")
+	}
+
+	// 解析失败时保留原配置
+	writeFile(t, path, "timeout: [\n")
+	time.Sleep(100 * time.Millisecond)
+	if m.Current() != (appConfig{Timeout: 10, Mode: "degraded"}) {
+		t.Errorf("config after invalid YAML = %+v", m.Current())
+	}
+}
+
+func TestManagerReloadsOnConfigMapSwap(t *testing.T) {
+	// 模拟 ConfigMap 挂载：app.yaml -> ..data/app.yaml，..data -> ..v1
+	dir := t.TempDir()
+	for _, version := range []string{"..v1", "..v2"} {
+		if err := os.Mkdir(filepath.Join(dir, version), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(dir, "..v1", "app.yaml"), "timeout: 1\n")
+	writeFile(t, filepath.Join(dir, "..v2", "app.yaml"), "timeout: 2\n")
+	if err := os.Symlink("..v1", filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "app.yaml")
+	if err := os.Symlink(filepath.Join("..data", "app.yaml"), path); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := NewManager[appConfig](path, nil)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	defer m.Close()
+
+	// kubelet 创建新的符号链接后用 rename 原子替换 ..data，app.yaml 本身没有事件
+	if err := os.Symlink("..v2", filepath.Join(dir, "..data_tmp")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")); err != nil {
+		t.Fatal(err)
+	}
+	waitForConfig(t, m, appConfig{Timeout: 2})
+}
+
+func TestManagerClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	writeFile(t, path, "timeout: 1\n")
+	m, err := NewManager[appConfig](path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+
+	if _, err := NewManager[appConfig](filepath.Join(t.TempDir(), "missing.yaml"), nil); err == nil {
+		t.Error("NewManager accepted a missing file")
+	}
+}
@@ -0,0 +1,123 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// ErrSecretNotFound 密钥不存在
+var ErrSecretNotFound = errors.New("config: secret not found")
+
+// SecretsManager 按 key 读取敏感配置
+type SecretsManager interface {
+	GetSecret(ctx context.Context, key string) (string, error)
+}
+
+// EnvSecretsManager 从环境变量读取密钥，变量名为 Prefix + key
+type EnvSecretsManager struct {
+	Prefix string
+}
+
+// GetSecret 读取环境变量，未设置时返回 ErrSecretNotFound
+func (m EnvSecretsManager) GetSecret(ctx context.Context, key string) (string, error) {
+	value, ok := os.LookupEnv(m.Prefix + key)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, key)
+	}
+	return value, nil
+}
+
+// AWSSecretsManagerAPI AWSSecretsManager 使用的客户端方法，*secretsmanager.Client 即满足该接口
+type AWSSecretsManagerAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// AWSSecretsManager 从 AWS Secrets Manager 读取密钥。key 为 secret ID（名称或 ARN），
+// 写成 "<secret ID>#<field>" 时将密钥值按 JSON 对象解析并返回其中的 field
+type AWSSecretsManager struct {
+	client AWSSecretsManagerAPI
+}
+
+// NewAWSSecretsManager 基于 secretsmanager 客户端创建 AWSSecretsManager，
+// 客户端通常由 secretsmanager.NewFromConfig(cfg) 创建
+func NewAWSSecretsManager(client AWSSecretsManagerAPI) *AWSSecretsManager {
+	return &AWSSecretsManager{client: client}
+}
+
+// GetSecret 读取密钥的字符串值
+func (m *AWSSecretsManager) GetSecret(ctx context.Context, key string) (string, error) {
+	secretID, field, hasField := strings.Cut(key, "#")
+	out, err := m.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String(secretID)})
+	if err != nil {
+		return "", err
+	}
+	if out.SecretString == nil {
+		return "", fmt.Errorf("config: secret %s has no string value", secretID)
+	}
+	if !hasField {
+		return *out.SecretString, nil
+	}
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(*out.SecretString), &values); err != nil {
+		return "", fmt.Errorf("config: secret %s is not a JSON object: %v", secretID, err)
+	}
+	value, ok := values[field]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrSecretNotFound, key)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+// ResolveSecrets 遍历 cfg（结构体指针）及其嵌套结构体，将带有 secret:"<key>" 标签的 string 字段
+// 设置为 sm.GetSecret(key) 的返回值
+func ResolveSecrets(ctx context.Context, cfg interface{}, sm SecretsManager) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("config: ResolveSecrets requires a non-nil pointer to a struct")
+	}
+	return resolveStruct(ctx, v.Elem(), sm)
+}
+
+func resolveStruct(ctx context.Context, v reflect.Value, sm SecretsManager) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		info := t.Field(i)
+		if !info.IsExported() {
+			continue
+		}
+		if key, ok := info.Tag.Lookup("secret"); ok && key != "" {
+			if field.Kind() != reflect.String {
+				return fmt.Errorf("config: secret field %s must be a string", info.Name)
+			}
+			value, err := sm.GetSecret(ctx, key)
+			if err != nil {
+				return fmt.Errorf("config: failed to resolve %s: %w", info.Name, err)
+			}
+			field.SetString(value)
+			continue
+		}
+		switch {
+		case field.Kind() == reflect.Struct:
+			if err := resolveStruct(ctx, field, sm); err != nil {
+				return err
+			}
+		case field.Kind() == reflect.Ptr && !field.IsNil() && field.Elem().Kind() == reflect.Struct:
+			if err := resolveStruct(ctx, field.Elem(), sm); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+type dbSecrets struct {
+	Host     string
+	Password string `secret:"DB_PASSWORD"`
+}
+
+type appSecrets struct {
+	DB       dbSecrets
+	Cache    *dbSecrets
+	APIToken string `secret:"API_TOKEN"`
+}
+
+func TestResolveSecretsFromEnv(t *testing.T) {
+	t.Setenv("APP_DB_PASSWORD", "s3cret")
+	t.Setenv("APP_API_TOKEN", "token")
+
+	cfg := appSecrets{DB: dbSecrets{Host: "db"}, Cache: &dbSecrets{}}
+	if err := ResolveSecrets(context.Background(), &cfg, EnvSecretsManager{Prefix: "APP_"}); err != nil {
+		t.Fatalf("ResolveSecrets: %v", err)
+	}
+	if cfg.DB.Password != "s3cret" || cfg.DB.Host != "db" {
+		t.Errorf("DB = %+v", cfg.DB)
+	}
+	// 嵌套的结构体指针同样解析
+	if cfg.Cache.Password != "s3cret" || cfg.APIToken != "token" {
+		t.Errorf("Cache = %+v, APIToken = %q", cfg.Cache, cfg.APIToken)
+	}
+}
+
+func TestResolveSecretsErrors(t *testing.T) {
+	var cfg dbSecrets
+	err := ResolveSecrets(context.Background(), &cfg, EnvSecretsManager{Prefix: "UNSET_PREFIX_"})
+	if !errors.Is(err, ErrSecretNotFound) {
+		t.Errorf("missing variable: err = %v, want ErrSecretNotFound", err)
+	}
+	if err := ResolveSecrets(context.Background(), cfg, EnvSecretsManager{}); err == nil {
+		t.Error("ResolveSecrets accepted a non-pointer")
+	}
+	var wrongType struct {
+		Port int `secret:"DB_PORT"`
+	}
+	if err := ResolveSecrets(context.Background(), &wrongType, EnvSecretsManager{}); err == nil {
+		t.Error("ResolveSecrets accepted a non-string secret field")
+	}
+}
+
+// fakeSecretsAPI 按 secret ID 返回固定值
+type fakeSecretsAPI map[string]string
+
+func (f fakeSecretsAPI) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	value, ok := f[aws.ToString(params.SecretId)]
+	if !ok {
+		return nil, errors.New("ResourceNotFoundException")
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(value)}, nil
+}
+
+func TestAWSSecretsManager(t *testing.T) {
+	sm := NewAWSSecretsManager(fakeSecretsAPI{
+		"prod/token": "plain",
+		"prod/db":    `{"password":"s3cret","port":5432}`,
+	})
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{"prod/token", "plain", false},
+		{"prod/db#password", "s3cret", false},
+		{"prod/db#port", "5432", false},
+		{"prod/db#user", "", true},
+		{"prod/token#field", "", true},
+		{"prod/unknown", "", true},
+	}
+	for _, tt := range tests {
+		got, err := sm.GetSecret(context.Background(), tt.key)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("GetSecret(%q) = %q, %v", tt.key, got, err)
+		}
+	}
+}
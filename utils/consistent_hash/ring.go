@@ -0,0 +1,160 @@
+package consistenthash
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// defaultVNodes New 在 vnodes 不为正数时使用的虚拟节点数
+const defaultVNodes = 100
+
+// Ring 使用虚拟节点的一致性哈希环，节点增减时只有约 1/N 的 key 会迁移
+type Ring struct {
+	mu     sync.RWMutex
+	vnodes int
+	hashFn func([]byte) uint64
+	hashes []uint64          // 已排序的虚拟节点哈希
+	owners map[uint64]string // 虚拟节点哈希 -> 真实节点
+	nodes  map[string]struct{}
+}
+
+// New 创建哈希环，每个节点对应 vnodes 个虚拟节点；hashFn 为 nil 时使用 64 位 FNV-1a
+func New(vnodes int, hashFn func([]byte) uint64) *Ring {
+	if vnodes <= 0 {
+		vnodes = defaultVNodes
+	}
+	if hashFn == nil {
+		hashFn = defaultHash
+	}
+	return &Ring{
+		vnodes: vnodes,
+		hashFn: hashFn,
+		owners: make(map[uint64]string),
+		nodes:  make(map[string]struct{}),
+	}
+}
+
+// Add 向环中加入节点，已存在的节点会被忽略
+func (r *Ring) Add(nodes ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, node := range nodes {
+		if _, ok := r.nodes[node]; ok {
+			continue
+		}
+		r.nodes[node] = struct{}{}
+		for i := 0; i < r.vnodes; i++ {
+			h := r.hashFn([]byte(vnodeKey(node, i)))
+			// 哈希冲突时保留先加入的节点
+			if _, taken := r.owners[h]; taken {
+				continue
+			}
+			r.owners[h] = node
+			r.hashes = append(r.hashes, h)
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+}
+
+// Remove 从环中移除节点
+func (r *Ring) Remove(nodes ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	removed := false
+	for _, node := range nodes {
+		if _, ok := r.nodes[node]; !ok {
+			continue
+		}
+		delete(r.nodes, node)
+		removed = true
+	}
+	if !removed {
+		return
+	}
+	hashes := r.hashes[:0]
+	for _, h := range r.hashes {
+		if _, ok := r.nodes[r.owners[h]]; ok {
+			hashes = append(hashes, h)
+		} else {
+			delete(r.owners, h)
+		}
+	}
+	r.hashes = hashes
+}
+
+// Nodes 返回环中的所有节点，按名称排序
+func (r *Ring) Nodes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	nodes := make([]string, 0, len(r.nodes))
+	for node := range r.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// Get 返回负责 key 的节点，环为空时返回 false
+func (r *Ring) Get(key string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.hashes) == 0 {
+		return "", false
+	}
+	return r.owners[r.hashes[r.search(key)]], true
+}
+
+// GetN 沿环顺时针返回最多 n 个不同的节点，用于副本放置；第一个节点与 Get 的结果相同
+func (r *Ring) GetN(key string, n int) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if n > len(r.nodes) {
+		n = len(r.nodes)
+	}
+	if n <= 0 || len(r.hashes) == 0 {
+		return nil
+	}
+	result := make([]string, 0, n)
+	seen := make(map[string]struct{}, n)
+	start := r.search(key)
+	for i := 0; i < len(r.hashes) && len(result) < n; i++ {
+		node := r.owners[r.hashes[(start+i)%len(r.hashes)]]
+		if _, ok := seen[node]; ok {
+			continue
+		}
+		seen[node] = struct{}{}
+		result = append(result, node)
+	}
+	return result
+}
+
+// search 返回第一个不小于 key 哈希的虚拟节点下标，超过末尾时回到 0
+func (r *Ring) search(key string) int {
+	h := r.hashFn([]byte(key))
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		return 0
+	}
+	return i
+}
+
+// vnodeKey 虚拟节点的哈希输入
+func vnodeKey(node string, i int) string {
+	return node + "#" + strconv.Itoa(i)
+}
+
+// defaultHash 默认哈希函数。FNV 对 "node#1"、"node#2" 这类相近输入的高位区分度很差，
+// 虚拟节点会在环上聚集，因此再用 splitmix64 的终结步骤打散
+func defaultHash(data []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(data)
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,106 @@
+package consistenthash
+
+import (
+	"fmt"
+	"testing"
+)
+
+const testKeys = 10000
+
+func assignments(r *Ring) map[string]string {
+	owners := make(map[string]string, testKeys)
+	for i := 0; i < testKeys; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		owners[key], _ = r.Get(key)
+	}
+	return owners
+}
+
+func moved(before, after map[string]string) int {
+	n := 0
+	for key, node := range before {
+		if after[key] != node {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRingAddMovesAboutOneNth(t *testing.T) {
+	r := New(200, nil)
+	r.Add("a", "b", "c", "d")
+	before := assignments(r)
+
+	r.Add("e")
+	after := assignments(r)
+	// 加入第 5 个节点后理论上有 1/5 的 key 迁移，且只会迁移到新节点
+	n := moved(before, after)
+	if frac := float64(n) / testKeys; frac < 0.15 || frac > 0.25 {
+		t.Errorf("%.1f%% of keys moved, want about 20%%", frac*100)
+	}
+	for key, node := range after {
+		if before[key] != node && node != "e" {
+			t.Fatalf("key %s moved from %s to %s, not to the new node", key, before[key], node)
+		}
+	}
+}
+
+func TestRingRemoveMovesOnlyRemovedKeys(t *testing.T) {
+	r := New(200, nil)
+	r.Add("a", "b", "c", "d", "e")
+	before := assignments(r)
+
+	r.Remove("c")
+	after := assignments(r)
+	n := moved(before, after)
+	if frac := float64(n) / testKeys; frac < 0.15 || frac > 0.25 {
+		t.Errorf("%.1f%% of keys moved, want about 20%%", frac*100)
+	}
+	// 只有原来属于 c 的 key 迁移
+	for key, node := range before {
+		if (node == "c") != (after[key] != node) {
+			t.Fatalf("key %s: %s -> %s", key, node, after[key])
+		}
+	}
+	if nodes := r.Nodes(); len(nodes) != 4 {
+		t.Errorf("Nodes() = %v", nodes)
+	}
+}
+
+func TestRingGetN(t *testing.T) {
+	r := New(50, nil)
+	if _, ok := r.Get("k"); ok {
+		t.Error("Get on an empty ring returned a node")
+	}
+	if nodes := r.GetN("k", 2); nodes != nil {
+		t.Errorf("GetN on an empty ring = %v", nodes)
+	}
+
+	r.Add("a", "b", "c")
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		nodes := r.GetN(key, 2)
+		if len(nodes) != 2 || nodes[0] == nodes[1] {
+			t.Fatalf("GetN(%s, 2) = %v, want two distinct nodes", key, nodes)
+		}
+		if first, _ := r.Get(key); nodes[0] != first {
+			t.Fatalf("GetN(%s)[0] = %s, Get = %s", key, nodes[0], first)
+		}
+	}
+	// n 大于节点数时返回所有节点
+	if nodes := r.GetN("k", 5); len(nodes) != 3 {
+		t.Errorf("GetN(k, 5) = %v, want all 3 nodes", nodes)
+	}
+}
+
+func TestRingCustomHash(t *testing.T) {
+	calls := 0
+	r := New(1, func(data []byte) uint64 {
+		calls++
+		return uint64(len(data))
+	})
+	r.Add("node")
+	if node, ok := r.Get("anything"); !ok || node != "node" || calls == 0 {
+		t.Errorf("Get = %q, %v with %d hash calls", node, ok, calls)
+	}
+}
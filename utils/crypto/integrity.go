@@ -0,0 +1,113 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HashAlgo 文件完整性校验使用的摘要算法
+type HashAlgo int
+
+const (
+	SHA256 HashAlgo = iota
+	SHA512
+)
+
+// ErrHashMismatch 文件摘要与期望值不一致
+var ErrHashMismatch = errors.New("crypto: hash mismatch")
+
+// String 返回算法名称
+func (a HashAlgo) String() string {
+	switch a {
+	case SHA256:
+		return "SHA-256"
+	case SHA512:
+		return "SHA-512"
+	default:
+		return fmt.Sprintf("HashAlgo(%d)", int(a))
+	}
+}
+
+// newHash 根据算法创建 hash.Hash
+func (a HashAlgo) newHash() (hash.Hash, error) {
+	switch a {
+	case SHA256:
+		return sha256.New(), nil
+	case SHA512:
+		return sha512.New(), nil
+	default:
+		return nil, fmt.Errorf("crypto: unsupported hash algorithm %v", a)
+	}
+}
+
+// HashReader 计算 io.Reader 内容的摘要，返回十六进制字符串
+func HashReader(r io.Reader, algo HashAlgo) (string, error) {
+	h, err := algo.newHash()
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashFile 计算文件的摘要，返回十六进制字符串
+func HashFile(path string, algo HashAlgo) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return HashReader(f, algo)
+}
+
+// VerifyFile 计算文件摘要并以常量时间与 expectedHex 比较，不一致时返回 ErrHashMismatch
+func VerifyFile(path string, expectedHex string, algo HashAlgo) error {
+	actual, err := HashFile(path, algo)
+	if err != nil {
+		return err
+	}
+	expected := strings.ToLower(strings.TrimSpace(expectedHex))
+	if subtle.ConstantTimeCompare([]byte(actual), []byte(expected)) != 1 {
+		return fmt.Errorf("%w: %s", ErrHashMismatch, path)
+	}
+	return nil
+}
+
+// HashDirectory 递归计算目录下所有普通文件的摘要，返回 相对路径 -> 摘要 的映射
+func HashDirectory(dir string, algo HashAlgo) (map[string]string, error) {
+	hashes := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		sum, err := HashFile(path, algo)
+		if err != nil {
+			return err
+		}
+		hashes[filepath.ToSlash(rel)] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
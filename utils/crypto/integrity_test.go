@@ -0,0 +1,91 @@
+package crypto
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// NIST FIPS 180-2 附录中的 SHA-256 / SHA-512 示例
+var nistVectors = []struct {
+	name  string
+	input string
+	algo  HashAlgo
+	want  string
+}{
+	{"sha256 empty", "", SHA256, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
+	{"sha256 abc", "abc", SHA256, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+	{"sha256 448 bits", "abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq", SHA256, "248d6a61d20638b8e5c026930c3e6039a33ce45964ff2167f6ecedd419db06c1"},
+	{"sha256 million a", strings.Repeat("a", 1000000), SHA256, "cdc76e5c9914fb9281a1c7e284d73e67f1809a48a497200e046d39ccc7112cd0"},
+	{"sha512 abc", "abc", SHA512, "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f"},
+}
+
+func TestHashReaderNISTVectors(t *testing.T) {
+	for _, v := range nistVectors {
+		t.Run(v.name, func(t *testing.T) {
+			got, err := HashReader(strings.NewReader(v.input), v.algo)
+			if err != nil {
+				t.Fatalf("HashReader: %v", err)
+			}
+			if got != v.want {
+				t.Errorf("got %s, want %s", got, v.want)
+			}
+		})
+	}
+}
+
+func TestHashReaderUnsupportedAlgo(t *testing.T) {
+	if _, err := HashReader(strings.NewReader("abc"), HashAlgo(99)); err == nil {
+		t.Fatal("expected error for unsupported algorithm")
+	}
+}
+
+func TestHashFileAndVerifyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "abc.txt")
+	if err := os.WriteFile(path, []byte("abc"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	const want = "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+
+	got, err := HashFile(path, SHA256)
+	if err != nil || got != want {
+		t.Fatalf("HashFile = %s, %v; want %s", got, err, want)
+	}
+	if err := VerifyFile(path, " "+strings.ToUpper(want)+"\n", SHA256); err != nil {
+		t.Errorf("VerifyFile with matching hash: %v", err)
+	}
+	if err := VerifyFile(path, strings.Repeat("0", 64), SHA256); !errors.Is(err, ErrHashMismatch) {
+		t.Errorf("VerifyFile with wrong hash err = %v, want ErrHashMismatch", err)
+	}
+	if _, err := HashFile(filepath.Join(t.TempDir(), "missing"), SHA256); err == nil {
+		t.Error("HashFile of a missing file returned no error")
+	}
+}
+
+func TestHashDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("abc"), 0o600)
+	os.WriteFile(filepath.Join(dir, "sub", "empty"), nil, 0o600)
+
+	hashes, err := HashDirectory(dir, SHA256)
+	if err != nil {
+		t.Fatalf("HashDirectory: %v", err)
+	}
+	want := map[string]string{
+		"a.txt":     "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		"sub/empty": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	}
+	if len(hashes) != len(want) {
+		t.Fatalf("got %v, want %v", hashes, want)
+	}
+	for path, sum := range want {
+		if hashes[path] != sum {
+			t.Errorf("%s = %s, want %s", path, hashes[path], sum)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// 预置字符集
+const (
+	AlphanumericCharset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	HexCharset          = "0123456789abcdef"
+	Base62Charset       = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	URLSafeCharset      = Base62Charset + "-_"
+)
+
+// RandomString 使用 crypto/rand 从 charset 中均匀选取字符生成随机字符串（无取模偏差）
+// 适用于密码重置 token、CSRF token、nonce 等场景
+func RandomString(length int, charset string) (string, error) {
+	if length < 0 {
+		return "", errors.New("crypto: negative random string length")
+	}
+	chars := []rune(charset)
+	if len(chars) == 0 {
+		return "", errors.New("crypto: empty charset")
+	}
+	max := big.NewInt(int64(len(chars)))
+	result := make([]rune, length)
+	for i := range result {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		result[i] = chars[n.Int64()]
+	}
+	return string(result), nil
+}
+
+// RandomBytes 返回 n 个密码学安全的随机字节
+func RandomBytes(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, errors.New("crypto: negative random byte count")
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// RandomInt64 返回 [0, max) 区间内均匀分布的随机整数
+func RandomInt64(max int64) (int64, error) {
+	if max <= 0 {
+		return 0, errors.New("crypto: max must be positive")
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(max))
+	if err != nil {
+		return 0, err
+	}
+	return n.Int64(), nil
+}
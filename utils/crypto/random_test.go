@@ -0,0 +1,109 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestRandomStringNoDuplicates(t *testing.T) {
+	const n, length = 10000, 10
+	// 生日问题：n 个取值空间为 N 的随机串出现重复的概率约为 n²/2N
+	space := math.Pow(float64(len(AlphanumericCharset)), length)
+	if p := float64(n) * float64(n) / (2 * space); p > 1e-9 {
+		t.Fatalf("collision probability %g too high for this test", p)
+	}
+
+	seen := make(map[string]struct{}, n)
+	for i := 0; i < n; i++ {
+		s, err := RandomString(length, AlphanumericCharset)
+		if err != nil {
+			t.Fatalf("RandomString: %v", err)
+		}
+		if len(s) != length {
+			t.Fatalf("len(%q) = %d, want %d", s, len(s), length)
+		}
+		if _, dup := seen[s]; dup {
+			t.Fatalf("duplicate random string %q after %d iterations", s, i)
+		}
+		seen[s] = struct{}{}
+	}
+}
+
+func TestRandomStringCharset(t *testing.T) {
+	for _, charset := range []string{AlphanumericCharset, HexCharset, Base62Charset, URLSafeCharset, "日本語"} {
+		s, err := RandomString(200, charset)
+		if err != nil {
+			t.Fatalf("RandomString: %v", err)
+		}
+		for _, r := range s {
+			if !strings.ContainsRune(charset, r) {
+				t.Fatalf("%q contains %q outside charset %q", s, r, charset)
+			}
+		}
+	}
+}
+
+func TestRandomStringUniform(t *testing.T) {
+	// 字符集大小不是 2 的幂时，取模实现会偏向前面的字符；这里要求每个字符的频率都接近均值
+	const charset, samples = "abc", 30000
+	s, err := RandomString(samples, charset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range charset {
+		got := strings.Count(s, string(c))
+		if expected := samples / len(charset); math.Abs(float64(got-expected)) > 0.05*float64(expected) {
+			t.Errorf("%q appears %d times, expected about %d", c, got, expected)
+		}
+	}
+}
+
+func TestRandomInvalidArguments(t *testing.T) {
+	if _, err := RandomString(-1, HexCharset); err == nil {
+		t.Error("RandomString(-1) returned no error")
+	}
+	if _, err := RandomString(5, ""); err == nil {
+		t.Error("RandomString with empty charset returned no error")
+	}
+	if _, err := RandomBytes(-1); err == nil {
+		t.Error("RandomBytes(-1) returned no error")
+	}
+	if _, err := RandomInt64(0); err == nil {
+		t.Error("RandomInt64(0) returned no error")
+	}
+}
+
+func TestRandomInt64Range(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		n, err := RandomInt64(7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n < 0 || n >= 7 {
+			t.Fatalf("RandomInt64(7) = %d", n)
+		}
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("entropy exhausted") }
+
+func TestRandomReaderFailure(t *testing.T) {
+	original := rand.Reader
+	rand.Reader = failingReader{}
+	defer func() { rand.Reader = original }()
+
+	if _, err := RandomString(8, HexCharset); err == nil {
+		t.Error("RandomString returned no error on reader failure")
+	}
+	if _, err := RandomBytes(8); err == nil {
+		t.Error("RandomBytes returned no error on reader failure")
+	}
+	if _, err := RandomInt64(10); err == nil {
+		t.Error("RandomInt64 returned no error on reader failure")
+	}
+}
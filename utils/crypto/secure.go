@@ -0,0 +1,71 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"runtime"
+	"sync"
+)
+
+// SecureBytes 保存密码、密钥等敏感数据，使用完毕后调用 Zero 清除内存中的内容
+type SecureBytes struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+// NewSecureBytes 复制 data 创建 SecureBytes，调用方可随后自行清除原切片
+func NewSecureBytes(data []byte) *SecureBytes {
+	buf := make([]byte, len(data))
+	copy(buf, data)
+	return &SecureBytes{data: buf}
+}
+
+// String 返回内容的字符串副本；注意该副本无法被 Zero 清除
+func (s *SecureBytes) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return string(s.data)
+}
+
+// Bytes 返回底层切片，Zero 之后其内容全部为 0
+func (s *SecureBytes) Bytes() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data
+}
+
+// Zero 先用随机数据覆盖再全部置 0，runtime.KeepAlive 防止写入被编译器当作无用存储优化掉
+func (s *SecureBytes) Zero() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, _ = rand.Read(s.data)
+	for i := range s.data {
+		s.data[i] = 0
+	}
+	runtime.KeepAlive(s.data)
+}
+
+// SecureString 只能读取一次的敏感字符串，String 返回明文后立即清除底层数据
+type SecureString struct {
+	bytes *SecureBytes
+	once  sync.Once
+}
+
+// NewSecureString 复制 data 创建 SecureString
+func NewSecureString(data []byte) *SecureString {
+	return &SecureString{bytes: NewSecureBytes(data)}
+}
+
+// String 第一次调用返回明文并清除底层数据，之后返回空字符串
+func (s *SecureString) String() string {
+	var plain string
+	s.once.Do(func() {
+		plain = s.bytes.String()
+		s.bytes.Zero()
+	})
+	return plain
+}
+
+// Zero 不读取直接清除底层数据
+func (s *SecureString) Zero() {
+	s.once.Do(s.bytes.Zero)
+}
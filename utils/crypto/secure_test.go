@@ -0,0 +1,49 @@
+package crypto
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+)
+
+func TestSecureBytesZero(t *testing.T) {
+	src := []byte("s3cret-password")
+	s := NewSecureBytes(src)
+	if s.String() != "s3cret-password" {
+		t.Fatalf("String() = %q", s.String())
+	}
+	data := s.Bytes()
+
+	s.Zero()
+	runtime.KeepAlive(s)
+	if !bytes.Equal(data, make([]byte, len(src))) {
+		t.Errorf("after Zero the data is %v, want all zeros", data)
+	}
+	if s.String() != string(make([]byte, len(src))) {
+		t.Errorf("String() after Zero = %q", s.String())
+	}
+	// NewSecureBytes 复制了输入，原切片不受影响
+	if string(src) != "s3cret-password" {
+		t.Errorf("source slice modified: %q", src)
+	}
+}
+
+func TestSecureStringReadOnce(t *testing.T) {
+	s := NewSecureString([]byte("token"))
+	data := s.bytes.Bytes()
+	if got := s.String(); got != "token" {
+		t.Fatalf("first String() = %q", got)
+	}
+	if got := s.String(); got != "" {
+		t.Errorf("second String() = %q, want empty", got)
+	}
+	if !bytes.Equal(data, make([]byte, 5)) {
+		t.Errorf("data after read = %v, want all zeros", data)
+	}
+
+	unread := NewSecureString([]byte("token"))
+	unread.Zero()
+	if got := unread.String(); got != "" {
+		t.Errorf("String() after Zero = %q, want empty", got)
+	}
+}
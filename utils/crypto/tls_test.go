@@ -0,0 +1,79 @@
+package crypto
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSelfSignedCertTLSRoundTrip(t *testing.T) {
+	certPEM, keyPEM, err := GenerateSelfSignedCert([]string{"localhost", "127.0.0.1"}, time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateSelfSignedCert: %v", err)
+	}
+	cfg, err := TLSConfigFromPEM(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("TLSConfigFromPEM: %v", err)
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	srv.TLS = cfg
+	// 下面故意失败的握手不打印到测试输出
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+
+	// 客户端只信任该自签名证书，不跳过校验
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg.Clone()}}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "ok" {
+		t.Errorf("body = %q", body)
+	}
+
+	// 未加入 RootCAs 的客户端应校验失败
+	if _, err := http.Get(srv.URL); err == nil {
+		t.Error("default client accepted the self-signed certificate")
+	}
+}
+
+func TestGenerateSelfSignedCertSANs(t *testing.T) {
+	certPEM, _, err := GenerateSelfSignedCert([]string{"example.test", "10.0.0.1"}, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(certPEM)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cert.DNSNames) != 1 || cert.DNSNames[0] != "example.test" {
+		t.Errorf("DNSNames = %v", cert.DNSNames)
+	}
+	if len(cert.IPAddresses) != 1 || !cert.IPAddresses[0].Equal(net.ParseIP("10.0.0.1")) {
+		t.Errorf("IPAddresses = %v", cert.IPAddresses)
+	}
+	if d := cert.NotAfter.Sub(time.Now()); d > 24*time.Hour || d < 23*time.Hour {
+		t.Errorf("NotAfter = %v", cert.NotAfter)
+	}
+
+	if _, _, err := GenerateSelfSignedCert(nil, time.Hour); err == nil {
+		t.Error("GenerateSelfSignedCert accepted no hosts")
+	}
+	if _, err := TLSConfigFromPEM([]byte("bad"), []byte("bad")); err == nil {
+		t.Error("TLSConfigFromPEM accepted invalid PEM")
+	}
+}
@@ -0,0 +1,118 @@
+package db
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kobyt2/common-services/logger"
+	"go.uber.org/zap"
+)
+
+// idleIntervalsBeforeShrink 连续多少个周期没有等待时缩小连接池
+const idleIntervalsBeforeShrink = 3
+
+// Pool 可调整大小的连接池，*sql.DB 即满足该接口
+type Pool interface {
+	StatsProvider
+	SetMaxOpenConns(n int)
+}
+
+// AutoTuneOption AutoTunePool 的可选配置
+type AutoTuneOption func(*autoTuneConfig)
+
+type autoTuneConfig struct {
+	minConns int
+	maxConns int
+	logger   *zap.Logger
+}
+
+// WithPoolBounds 设置 MaxOpenConns 的调整范围，默认为 [2, 100]
+func WithPoolBounds(minConns, maxConns int) AutoTuneOption {
+	return func(c *autoTuneConfig) {
+		c.minConns = minConns
+		c.maxConns = maxConns
+	}
+}
+
+// WithAutoTuneLogger 设置记录调整日志的 logger，默认在写日志时使用 logger.L()
+func WithAutoTuneLogger(logger *zap.Logger) AutoTuneOption {
+	return func(c *autoTuneConfig) {
+		c.logger = logger
+	}
+}
+
+// AutoTunePool 每隔 interval 读取一次连接池统计：本周期平均等待时间超过 target 时将 MaxOpenConns 增加 10%，
+// 连续三个周期没有等待时减少 10%，调整结果限制在配置的范围内。MaxOpenConns 为 0（不限制）时不做调整。
+// 返回的函数用于停止调整，可重复调用
+func AutoTunePool(db Pool, target time.Duration, interval time.Duration, opts ...AutoTuneOption) func() {
+	cfg := autoTuneConfig{minConns: 2, maxConns: 100}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	// 基准在返回前读取，之后的等待都计入第一个周期
+	prev := db.Stats()
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		idle := 0
+		for {
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+			current := db.Stats()
+			waits := current.WaitCount - prev.WaitCount
+			waited := current.WaitDuration - prev.WaitDuration
+			prev = current
+
+			size := current.MaxOpenConnections
+			if size <= 0 {
+				continue
+			}
+			if waits == 0 {
+				idle++
+			} else {
+				idle = 0
+			}
+
+			next := size
+			switch {
+			case waits > 0 && waited/time.Duration(waits) > target:
+				next = min(size+step(size), cfg.maxConns)
+			case idle >= idleIntervalsBeforeShrink:
+				idle = 0
+				next = max(size-step(size), cfg.minConns)
+			}
+			if next != size {
+				db.SetMaxOpenConns(next)
+				log := cfg.logger
+				if log == nil {
+					log = logger.L()
+				}
+				log.Info("db pool max open connections adjusted",
+					zap.Int("from", size),
+					zap.Int("to", next),
+					zap.Int64("waits", waits),
+					zap.Duration("waited", waited))
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-done
+		})
+	}
+}
+
+// step 调整幅度为当前大小的 10%，至少为 1
+func step(size int) int {
+	return max(size/10, 1)
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/kobyt2/common-services/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// fakePool 在 fakeStats 基础上记录 SetMaxOpenConns 的结果
+type fakePool struct {
+	fakeStats
+}
+
+func (p *fakePool) SetMaxOpenConns(n int) {
+	p.set(func(s *sql.DBStats) { s.MaxOpenConnections = n })
+}
+
+func (p *fakePool) maxOpen() int {
+	return p.Stats().MaxOpenConnections
+}
+
+// waitForSize 等待 MaxOpenConns 变为 want
+func waitForSize(t *testing.T, pool *fakePool, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for pool.maxOpen() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("MaxOpenConns = %d, want %d", pool.maxOpen(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestAutoTunePoolGrowsOnSlowWaits(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	pool := &fakePool{fakeStats{stats: sql.DBStats{MaxOpenConnections: 20}}}
+	stop := AutoTunePool(pool, 10*time.Millisecond, 20*time.Millisecond, WithPoolBounds(5, 23), WithAutoTuneLogger(zap.New(core)))
+	defer stop()
+
+	// 平均等待 50ms，超过 10ms 的目标：20 -> 22
+	pool.set(func(s *sql.DBStats) { s.WaitCount, s.WaitDuration = 2, 100*time.Millisecond })
+	waitForSize(t, pool, 22)
+	entry := logs.FilterMessage("db pool max open connections adjusted").All()[0]
+	if fields := entry.ContextMap(); fields["from"] != int64(20) || fields["to"] != int64(22) || fields["waits"] != int64(2) {
+		t.Errorf("log fields = %v", fields)
+	}
+
+	// 再次增长受上限 23 限制，需在出现三个空闲周期前设置
+	pool.set(func(s *sql.DBStats) { s.WaitCount, s.WaitDuration = 4, 200*time.Millisecond })
+	waitForSize(t, pool, 23)
+}
+
+func TestAutoTunePoolLogsToRepoLoggerByDefault(t *testing.T) {
+	logs := logger.ObserveGlobal(t, zapcore.InfoLevel)
+	pool := &fakePool{fakeStats{stats: sql.DBStats{MaxOpenConnections: 20}}}
+	stop := AutoTunePool(pool, 10*time.Millisecond, 20*time.Millisecond)
+
+	pool.set(func(s *sql.DBStats) { s.WaitCount, s.WaitDuration = 2, 100*time.Millisecond })
+	waitForSize(t, pool, 22)
+	// 先停止调整，再由 cleanup 恢复全局 logger
+	stop()
+
+	if n := logs.FilterMessage("db pool max open connections adjusted").Len(); n != 1 {
+		t.Errorf("adjust logs = %d, want 1", n)
+	}
+}
+
+// steadyWaits 每次读取统计都新增一次 1ms 的等待，模拟持续但很短的等待
+type steadyWaits struct {
+	fakePool
+}
+
+func (p *steadyWaits) Stats() sql.DBStats {
+	p.set(func(s *sql.DBStats) {
+		s.WaitCount++
+		s.WaitDuration += time.Millisecond
+	})
+	return p.fakePool.Stats()
+}
+
+func TestAutoTunePoolIgnoresFastWaits(t *testing.T) {
+	pool := &steadyWaits{fakePool{fakeStats{stats: sql.DBStats{MaxOpenConnections: 20}}}}
+	stop := AutoTunePool(pool, 10*time.Millisecond, time.Millisecond, WithAutoTuneLogger(zap.NewNop()))
+
+	// 每个周期都有等待但平均只有 1ms：既不增长，也不计为空闲周期
+	time.Sleep(30 * time.Millisecond)
+	stop()
+	if got := pool.maxOpen(); got != 20 {
+		t.Errorf("MaxOpenConns = %d, want unchanged 20", got)
+	}
+}
+
+func TestAutoTunePoolShrinksWhenIdle(t *testing.T) {
+	pool := &fakePool{fakeStats{stats: sql.DBStats{MaxOpenConnections: 10}}}
+	stop := AutoTunePool(pool, 10*time.Millisecond, time.Millisecond, WithPoolBounds(8, 100), WithAutoTuneLogger(zap.NewNop()))
+	defer stop()
+
+	// 连续空闲周期逐步减少 10%（至少 1），直到下限 8
+	waitForSize(t, pool, 9)
+	waitForSize(t, pool, 8)
+	time.Sleep(20 * time.Millisecond)
+	if got := pool.maxOpen(); got != 8 {
+		t.Errorf("MaxOpenConns = %d, want the lower bound 8", got)
+	}
+}
+
+func TestAutoTunePoolStop(t *testing.T) {
+	pool := &fakePool{fakeStats{stats: sql.DBStats{MaxOpenConnections: 10}}}
+	stop := AutoTunePool(pool, time.Millisecond, time.Millisecond, WithAutoTuneLogger(zap.NewNop()))
+	stop()
+	stop() // 可重复调用
+
+	pool.set(func(s *sql.DBStats) { s.WaitCount, s.WaitDuration = 1, time.Second })
+	time.Sleep(10 * time.Millisecond)
+	if got := pool.maxOpen(); got != 10 {
+		t.Errorf("MaxOpenConns = %d after stop, want 10", got)
+	}
+}
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotFound 记录不存在
+	ErrNotFound = errors.New("db: record not found")
+	// ErrDuplicateKey 违反唯一约束
+	ErrDuplicateKey = errors.New("db: duplicate key")
+	// ErrForeignKeyViolation 违反外键约束
+	ErrForeignKeyViolation = errors.New("db: foreign key violation")
+	// ErrCheckViolation 违反 CHECK 约束
+	ErrCheckViolation = errors.New("db: check constraint violation")
+)
+
+// MySQL 错误码
+const (
+	mysqlDuplicateEntry     = 1062
+	mysqlRowIsReferenced    = 1451
+	mysqlNoReferencedRow    = 1452
+	mysqlCheckConstraintErr = 3819
+)
+
+// PostgreSQL SQLSTATE
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+)
+
+// WrapGORMError 将 GORM 错误和 MySQL、PostgreSQL、SQLite 驱动错误映射为本包的领域错误。
+// ErrNotFound 直接返回；其余领域错误同时包装原始错误，errors.Is 对两者都成立。无法识别的错误原样返回
+func WrapGORMError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	for _, domain := range []error{ErrNotFound, ErrDuplicateKey, ErrForeignKeyViolation, ErrCheckViolation} {
+		if errors.Is(err, domain) {
+			// 已经包装过
+			return err
+		}
+	}
+	if domain := classify(err); domain != nil {
+		return fmt.Errorf("%w: %w", domain, err)
+	}
+	return err
+}
+
+// IsNotFound 判断 err 是否表示记录不存在
+func IsNotFound(err error) bool {
+	return errors.Is(WrapGORMError(err), ErrNotFound)
+}
+
+// IsDuplicateKey 判断 err 是否为唯一约束冲突
+func IsDuplicateKey(err error) bool {
+	return errors.Is(WrapGORMError(err), ErrDuplicateKey)
+}
+
+// IsForeignKeyViolation 判断 err 是否为外键约束冲突
+func IsForeignKeyViolation(err error) bool {
+	return errors.Is(WrapGORMError(err), ErrForeignKeyViolation)
+}
+
+// IsCheckViolation 判断 err 是否为 CHECK 约束冲突
+func IsCheckViolation(err error) bool {
+	return errors.Is(WrapGORMError(err), ErrCheckViolation)
+}
+
+// classify 返回 err 对应的领域错误，无法识别时返回 nil
+func classify(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicateKey
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return ErrForeignKeyViolation
+	case errors.Is(err, gorm.ErrCheckConstraintViolated):
+		return ErrCheckViolation
+	}
+
+	// PostgreSQL：pgconn.PgError 与 pq.Error 都提供 SQLState()
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		switch pgErr.SQLState() {
+		case pgUniqueViolation:
+			return ErrDuplicateKey
+		case pgForeignKeyViolation:
+			return ErrForeignKeyViolation
+		case pgCheckViolation:
+			return ErrCheckViolation
+		}
+	}
+
+	if number, ok := mysqlErrorNumber(err); ok {
+		switch number {
+		case mysqlDuplicateEntry:
+			return ErrDuplicateKey
+		case mysqlRowIsReferenced, mysqlNoReferencedRow:
+			return ErrForeignKeyViolation
+		case mysqlCheckConstraintErr:
+			return ErrCheckViolation
+		}
+	}
+
+	// SQLite 驱动只提供错误信息
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "UNIQUE constraint failed"):
+		return ErrDuplicateKey
+	case strings.Contains(msg, "FOREIGN KEY constraint failed"):
+		return ErrForeignKeyViolation
+	case strings.Contains(msg, "CHECK constraint failed"):
+		return ErrCheckViolation
+	}
+	return nil
+}
+
+// mysqlErrorNumber 在错误链中查找 *mysql.MySQLError 并返回其错误码。
+// 通过 Number 字段识别，避免为此引入 MySQL 驱动依赖
+func mysqlErrorNumber(err error) (uint16, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		v := reflect.ValueOf(err)
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				continue
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct || v.Type().Name() != "MySQLError" {
+			continue
+		}
+		if f := v.FieldByName("Number"); f.IsValid() && f.Kind() == reflect.Uint16 {
+			return uint16(f.Uint()), true
+		}
+	}
+	return 0, false
+}
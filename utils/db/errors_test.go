@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// MySQLError 与 go-sql-driver/mysql 的错误类型同名同字段，WrapGORMError 按此识别
+type MySQLError struct {
+	Number  uint16
+	Message string
+}
+
+func (e *MySQLError) Error() string {
+	return fmt.Sprintf("Error %d: %s", e.Number, e.Message)
+}
+
+// pgError 与 pgconn.PgError 一样提供 SQLState()
+type pgError struct {
+	code string
+}
+
+func (e *pgError) Error() string    { return "ERROR: SQLSTATE " + e.code }
+func (e *pgError) SQLState() string { return e.code }
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	mock.ExpectQuery(regexp.QuoteMeta("select sqlite_version()")).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("3.45.0"))
+	db, err := gorm.Open(sqlite.Dialector{Conn: sqlDB}, &gorm.Config{Logger: logger.Discard, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatalf("open gorm: %v", err)
+	}
+	return db, mock
+}
+
+func TestRepositoryWrapsDriverErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   error
+		helper func(error) bool
+	}{
+		{"mysql duplicate", &MySQLError{Number: 1062, Message: "Duplicate entry"}, ErrDuplicateKey, IsDuplicateKey},
+		{"mysql foreign key", &MySQLError{Number: 1452, Message: "Cannot add or update a child row"}, ErrForeignKeyViolation, IsForeignKeyViolation},
+		{"mysql check", &MySQLError{Number: 3819, Message: "Check constraint is violated"}, ErrCheckViolation, IsCheckViolation},
+		{"postgres duplicate", &pgError{code: "23505"}, ErrDuplicateKey, IsDuplicateKey},
+		{"postgres foreign key", &pgError{code: "23503"}, ErrForeignKeyViolation, IsForeignKeyViolation},
+		{"postgres check", &pgError{code: "23514"}, ErrCheckViolation, IsCheckViolation},
+		{"sqlite duplicate", errors.New("constraint failed: UNIQUE constraint failed: orders.id (1555)"), ErrDuplicateKey, IsDuplicateKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			mock.ExpectQuery("INSERT INTO `orders`").WillReturnError(tt.err)
+
+			err := NewRepository[order, uint](db).Create(context.Background(), &order{Status: "paid"})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Create = %v, want %v", err, tt.want)
+			}
+			// 原始驱动错误仍在错误链中
+			if !errors.Is(err, tt.err) {
+				t.Errorf("driver error lost from %v", err)
+			}
+			if !tt.helper(err) {
+				t.Errorf("helper returned false for %v", err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestRepositoryNotFound(t *testing.T) {
+	db, mock := newMockDB(t)
+	mock.ExpectQuery("SELECT \\* FROM `orders`").WillReturnRows(sqlmock.NewRows([]string{"id", "status"}))
+	mock.ExpectExec("DELETE FROM `orders`").WillReturnResult(sqlmock.NewResult(0, 0))
+	repo := NewRepository[order, uint](db)
+
+	if _, err := repo.FindByID(context.Background(), 1); err != ErrNotFound || !IsNotFound(err) {
+		t.Errorf("FindByID = %v, want ErrNotFound", err)
+	}
+	if err := repo.Delete(context.Background(), 1); err != ErrNotFound {
+		t.Errorf("Delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestWrapGORMError(t *testing.T) {
+	unknown := errors.New("connection reset")
+	if err := WrapGORMError(unknown); err != unknown {
+		t.Errorf("unknown error = %v, want it unchanged", err)
+	}
+	if err := WrapGORMError(nil); err != nil {
+		t.Errorf("nil = %v", err)
+	}
+	if err := WrapGORMError(gorm.ErrDuplicatedKey); !errors.Is(err, ErrDuplicateKey) {
+		t.Errorf("gorm.ErrDuplicatedKey = %v", err)
+	}
+	// 重复包装不会叠加前缀
+	once := WrapGORMError(&pgError{code: "23505"})
+	if twice := WrapGORMError(once); twice != once {
+		t.Errorf("wrapped twice: %v", twice)
+	}
+}
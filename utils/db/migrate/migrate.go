@@ -0,0 +1,176 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration 一次数据库迁移
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(*gorm.DB) error
+	Down    func(*gorm.DB) error
+}
+
+// MigrationStatus 迁移的执行状态
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// schemaMigration schema_migrations 表中已执行的迁移记录
+type schemaMigration struct {
+	Version   int64  `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:255"`
+	AppliedAt time.Time
+}
+
+// TableName 指定迁移记录表名
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator 按版本号顺序执行迁移，并在 schema_migrations 表中记录已执行的版本
+type Migrator struct {
+	migrations []Migration
+}
+
+// New 创建迁移执行器
+func New() *Migrator {
+	return &Migrator{}
+}
+
+// AddMigration 注册一次迁移，版本号必须唯一
+func (m *Migrator) AddMigration(version int64, name string, up func(*gorm.DB) error, down func(*gorm.DB) error) {
+	m.migrations = append(m.migrations, Migration{Version: version, Name: name, Up: up, Down: down})
+}
+
+// Up 在同一个事务中按版本号升序执行所有未执行的迁移，任一迁移失败则全部回滚
+func (m *Migrator) Up(ctx context.Context, db *gorm.DB) error {
+	migrations, err := m.sorted()
+	if err != nil {
+		return err
+	}
+	if err := db.WithContext(ctx).AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		applied, err := appliedVersions(tx)
+		if err != nil {
+			return err
+		}
+		for _, migration := range migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := migration.Up(tx); err != nil {
+				return fmt.Errorf("migrate: up %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			record := schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}
+			if err := tx.Create(&record).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Down 在同一个事务中按版本号降序回滚最近执行的 steps 次迁移，steps 必须大于 0
+func (m *Migrator) Down(ctx context.Context, db *gorm.DB, steps int) error {
+	// GORM 把非正数的 Limit 当作不限制，不拦截会回滚全部迁移
+	if steps <= 0 {
+		return fmt.Errorf("migrate: down steps must be positive, got %d", steps)
+	}
+	migrations, err := m.sorted()
+	if err != nil {
+		return err
+	}
+	byVersion := make(map[int64]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+	if err := db.WithContext(ctx).AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var records []schemaMigration
+		if err := tx.Order("version DESC").Limit(steps).Find(&records).Error; err != nil {
+			return err
+		}
+		for _, record := range records {
+			migration, ok := byVersion[record.Version]
+			if !ok {
+				return fmt.Errorf("migrate: applied version %d is not registered", record.Version)
+			}
+			if migration.Down != nil {
+				if err := migration.Down(tx); err != nil {
+					return fmt.Errorf("migrate: down %d_%s: %w", migration.Version, migration.Name, err)
+				}
+			}
+			if err := tx.Delete(&schemaMigration{}, record.Version).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Status 按版本号升序列出所有已注册迁移的执行状态
+func (m *Migrator) Status(db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, err := m.sorted()
+	if err != nil {
+		return nil, err
+	}
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, migration := range migrations {
+		statuses[i] = MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// sorted 返回按版本号升序排列的迁移，版本号重复时返回错误
+func (m *Migrator) sorted() ([]Migration, error) {
+	migrations := make([]Migration, len(m.migrations))
+	copy(migrations, m.migrations)
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return nil, fmt.Errorf("migrate: duplicate migration version %d", migrations[i].Version)
+		}
+	}
+	return migrations, nil
+}
+
+// appliedVersions 返回已执行的迁移记录
+func appliedVersions(db *gorm.DB) (map[int64]schemaMigration, error) {
+	var records []schemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]schemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
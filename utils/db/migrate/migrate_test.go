@@ -0,0 +1,153 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	// 内存库每个连接都是独立的数据库，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func execSQL(sql string) func(*gorm.DB) error {
+	return func(tx *gorm.DB) error { return tx.Exec(sql).Error }
+}
+
+// newTestMigrator 注册三次迁移，故意乱序注册以验证按版本号执行
+func newTestMigrator() *Migrator {
+	m := New()
+	m.AddMigration(3, "add_users_email",
+		execSQL("ALTER TABLE users ADD COLUMN email TEXT"),
+		execSQL("ALTER TABLE users DROP COLUMN email"))
+	m.AddMigration(1, "create_users",
+		execSQL("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)"),
+		execSQL("DROP TABLE users"))
+	m.AddMigration(2, "create_orders",
+		execSQL("CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER)"),
+		execSQL("DROP TABLE orders"))
+	return m
+}
+
+func appliedOf(t *testing.T, m *Migrator, db *gorm.DB) []bool {
+	t.Helper()
+	statuses, err := m.Status(db)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	applied := make([]bool, len(statuses))
+	for i, status := range statuses {
+		if status.Version != int64(i+1) {
+			t.Fatalf("Status[%d].Version = %d, want versions in ascending order", i, status.Version)
+		}
+		if status.Applied != (status.AppliedAt != nil) {
+			t.Errorf("Status[%d] Applied = %v but AppliedAt = %v", i, status.Applied, status.AppliedAt)
+		}
+		applied[i] = status.Applied
+	}
+	return applied
+}
+
+func assertApplied(t *testing.T, m *Migrator, db *gorm.DB, want ...bool) {
+	t.Helper()
+	got := appliedOf(t, m, db)
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("applied = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestUpDownSequence(t *testing.T) {
+	db := newTestDB(t)
+	m := newTestMigrator()
+	ctx := context.Background()
+
+	assertApplied(t, m, db, false, false, false)
+
+	if err := m.Up(ctx, db); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	assertApplied(t, m, db, true, true, true)
+	if !db.Migrator().HasColumn("users", "email") || !db.Migrator().HasTable("orders") {
+		t.Fatal("Up did not create the schema")
+	}
+
+	// 重复执行 Up 不会重跑已执行的迁移
+	if err := m.Up(ctx, db); err != nil {
+		t.Fatalf("second Up: %v", err)
+	}
+
+	if err := m.Down(ctx, db, 1); err != nil {
+		t.Fatalf("Down(1): %v", err)
+	}
+	assertApplied(t, m, db, true, true, false)
+	if db.Migrator().HasColumn("users", "email") {
+		t.Error("Down(1) did not roll back the latest migration")
+	}
+
+	if err := m.Down(ctx, db, 2); err != nil {
+		t.Fatalf("Down(2): %v", err)
+	}
+	assertApplied(t, m, db, false, false, false)
+	if db.Migrator().HasTable("users") || db.Migrator().HasTable("orders") {
+		t.Error("Down(2) left tables behind")
+	}
+
+	if err := m.Up(ctx, db); err != nil {
+		t.Fatalf("Up after Down: %v", err)
+	}
+	assertApplied(t, m, db, true, true, true)
+}
+
+func TestDownRejectsNonPositiveSteps(t *testing.T) {
+	db := newTestDB(t)
+	m := newTestMigrator()
+	ctx := context.Background()
+	if err := m.Up(ctx, db); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	for _, steps := range []int{0, -1} {
+		if err := m.Down(ctx, db, steps); err == nil {
+			t.Errorf("Down(%d) returned nil, want an error", steps)
+		}
+	}
+	assertApplied(t, m, db, true, true, true)
+}
+
+func TestUpRollsBackOnFailure(t *testing.T) {
+	db := newTestDB(t)
+	m := newTestMigrator()
+	errBroken := errors.New("broken")
+	m.AddMigration(4, "broken", func(*gorm.DB) error { return errBroken }, nil)
+
+	if err := m.Up(context.Background(), db); !errors.Is(err, errBroken) {
+		t.Fatalf("Up: err = %v, want the failing migration's error", err)
+	}
+	assertApplied(t, m, db, false, false, false, false)
+	if db.Migrator().HasTable("users") {
+		t.Error("tables of earlier migrations were kept after the transaction failed")
+	}
+}
+
+func TestDuplicateVersion(t *testing.T) {
+	m := newTestMigrator()
+	m.AddMigration(2, "again", execSQL("SELECT 1"), nil)
+	if err := m.Up(context.Background(), newTestDB(t)); err == nil {
+		t.Error("Up with a duplicate version returned nil, want an error")
+	}
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// otelSpanKey 保存插件创建的 span，避免 after 回调误结束调用方的 span
+type otelSpanKey struct{}
+
+// otelPlugin 为每条 GORM 语句创建子 span 的插件
+type otelPlugin struct {
+	tracer trace.Tracer
+}
+
+// OTelPlugin 返回为 GORM 语句创建 "db.query"、"db.create" 等子 span 的插件，
+// 结束时记录 db.statement（不含参数值）与 db.rows_affected；gorm.ErrRecordNotFound 不视为错误。
+// 使用 db.Use(OTelPlugin(tracer)) 注册，并通过 WithContext 传入父 span
+func OTelPlugin(tracer trace.Tracer) gorm.Plugin {
+	return &otelPlugin{tracer: tracer}
+}
+
+// Name 实现 gorm.Plugin
+func (p *otelPlugin) Name() string {
+	return "otel"
+}
+
+// Initialize 实现 gorm.Plugin，为各类操作注册前后回调
+func (p *otelPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	registrations := []error{
+		cb.Create().Before("gorm:create").Register("otel:before_create", p.before("db.create")),
+		cb.Create().After("gorm:create").Register("otel:after_create", p.after),
+		cb.Query().Before("gorm:query").Register("otel:before_query", p.before("db.query")),
+		cb.Query().After("gorm:query").Register("otel:after_query", p.after),
+		cb.Update().Before("gorm:update").Register("otel:before_update", p.before("db.update")),
+		cb.Update().After("gorm:update").Register("otel:after_update", p.after),
+		cb.Delete().Before("gorm:delete").Register("otel:before_delete", p.before("db.delete")),
+		cb.Delete().After("gorm:delete").Register("otel:after_delete", p.after),
+		cb.Row().Before("gorm:row").Register("otel:before_row", p.before("db.row")),
+		cb.Row().After("gorm:row").Register("otel:after_row", p.after),
+		cb.Raw().Before("gorm:raw").Register("otel:before_raw", p.before("db.raw")),
+		cb.Raw().After("gorm:raw").Register("otel:after_raw", p.after),
+	}
+	for _, err := range registrations {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// before 开始子 span 并存入语句的 context
+func (p *otelPlugin) before(spanName string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		ctx, span := p.tracer.Start(ctx, spanName,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.system", db.Dialector.Name())),
+		)
+		db.Statement.Context = context.WithValue(ctx, otelSpanKey{}, span)
+	}
+}
+
+// after 记录语句、影响行数与错误后结束 span
+func (p *otelPlugin) after(db *gorm.DB) {
+	if db.Statement.Context == nil {
+		return
+	}
+	span, ok := db.Statement.Context.Value(otelSpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	attrs := []attribute.KeyValue{attribute.Int64("db.rows_affected", db.Statement.RowsAffected)}
+	if sql := db.Statement.SQL.String(); sql != "" {
+		attrs = append(attrs, attribute.String("db.statement", sql))
+	}
+	if table := db.Statement.Table; table != "" {
+		attrs = append(attrs, attribute.String("db.sql.table", table))
+	}
+	span.SetAttributes(attrs...)
+
+	if err := db.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+)
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestOTelPlugin(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer provider.Shutdown(context.Background())
+	tracer := provider.Tracer("test")
+
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&order{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(OTelPlugin(tracer)); err != nil {
+		t.Fatalf("Use: %v", err)
+	}
+
+	ctx, parent := tracer.Start(context.Background(), "handler")
+	tx := db.WithContext(ctx)
+	tx.Create(&[]order{{Status: "paid", Amount: 1}, {Status: "paid", Amount: 2}})
+	var orders []order
+	tx.Where("status = ?", "paid").Find(&orders)
+	var missing order
+	if err := tx.First(&missing, 999).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("First = %v", err)
+	}
+	tx.Exec("SELECT * FROM no_such_table")
+	parent.End()
+
+	spans := recorder.Ended()
+	byName := map[string][]sdktrace.ReadOnlySpan{}
+	for _, span := range spans {
+		byName[span.Name()] = append(byName[span.Name()], span)
+		if span.Name() != "handler" && span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %s is not a child of the caller's span", span.Name())
+		}
+	}
+	if len(byName["db.create"]) != 1 || len(byName["db.query"]) != 2 || len(byName["db.raw"]) != 1 {
+		t.Fatalf("spans = %v", byName)
+	}
+
+	create := spanAttributes(byName["db.create"][0])
+	if create["db.rows_affected"].AsInt64() != 2 || create["db.sql.table"].AsString() != "orders" || create["db.system"].AsString() != "sqlite" {
+		t.Errorf("create attributes = %v", create)
+	}
+	query := byName["db.query"][0]
+	attrs := spanAttributes(query)
+	if stmt := attrs["db.statement"].AsString(); !strings.HasPrefix(stmt, "SELECT * FROM `orders` WHERE status = ?") {
+		t.Errorf("db.statement = %q, want the SQL without values", stmt)
+	}
+	if attrs["db.rows_affected"].AsInt64() != 2 || query.Status().Code == codes.Error {
+		t.Errorf("query span = %v, %v", attrs, query.Status())
+	}
+
+	// 记录不存在不视为错误
+	if status := byName["db.query"][1].Status(); status.Code == codes.Error {
+		t.Errorf("not found query status = %v", status)
+	}
+	raw := byName["db.raw"][0]
+	if raw.Status().Code != codes.Error || len(raw.Events()) == 0 || raw.Events()[0].Name != "exception" {
+		t.Errorf("failed statement: status %v, events %v", raw.Status(), raw.Events())
+	}
+}
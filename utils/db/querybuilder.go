@@ -0,0 +1,62 @@
+package db
+
+import (
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Builder 动态拼接 WHERE 条件，所有条件都通过参数绑定，不做字符串拼接
+type Builder struct {
+	db *gorm.DB
+}
+
+// NewBuilder 基于 db 创建查询构造器
+func NewBuilder(db *gorm.DB) *Builder {
+	return &Builder{db: db}
+}
+
+// WhereIf 仅当 condition 为 true 时追加 WHERE 条件
+func (b *Builder) WhereIf(condition bool, query interface{}, args ...interface{}) *Builder {
+	if condition {
+		b.db = b.db.Where(query, args...)
+	}
+	return b
+}
+
+// WhereIn 追加 column IN (values) 条件，values 可以是任意切片；空切片不会匹配任何记录
+func (b *Builder) WhereIn(column string, values interface{}) *Builder {
+	b.db = b.db.Where(clause.IN{Column: clause.Column{Name: column}, Values: toSlice(values)})
+	return b
+}
+
+// DateRange 分别在 from、to 不为 nil 时追加 column >= from 和 column <= to 条件
+func (b *Builder) DateRange(column string, from, to *time.Time) *Builder {
+	if from != nil {
+		b.db = b.db.Where(clause.Gte{Column: clause.Column{Name: column}, Value: *from})
+	}
+	if to != nil {
+		b.db = b.db.Where(clause.Lte{Column: clause.Column{Name: column}, Value: *to})
+	}
+	return b
+}
+
+// Build 返回拼接完成的查询
+func (b *Builder) Build() *gorm.DB {
+	return b.db
+}
+
+// toSlice 将任意切片或数组转换为 []interface{}，非切片值视为单个元素
+func toSlice(values interface{}) []interface{} {
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return []interface{}{values}
+	}
+	result := make([]interface{}, v.Len())
+	for i := range result {
+		result[i] = v.Index(i).Interface()
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	// 内存库每个连接都是独立的数据库，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+type order struct {
+	ID        uint
+	Status    string
+	Amount    int
+	CreatedAt time.Time
+}
+
+func TestBuilderRowCounts(t *testing.T) {
+	db := newTestDB(t)
+	if err := db.AutoMigrate(&order{}); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return base.AddDate(0, 0, n) }
+	rows := []order{
+		{Status: "paid", Amount: 10, CreatedAt: day(0)},
+		{Status: "paid", Amount: 20, CreatedAt: day(1)},
+		{Status: "pending", Amount: 30, CreatedAt: day(2)},
+		{Status: "refunded", Amount: 40, CreatedAt: day(3)},
+		{Status: "paid", Amount: 50, CreatedAt: day(4)},
+	}
+	if err := db.Create(&rows).Error; err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	ptr := func(t time.Time) *time.Time { return &t }
+	tests := []struct {
+		name  string
+		build func(b *Builder) *Builder
+		want  int64
+	}{
+		{"no conditions", func(b *Builder) *Builder { return b }, 5},
+		{"WhereIf true", func(b *Builder) *Builder { return b.WhereIf(true, "status = ?", "paid") }, 3},
+		{"WhereIf false", func(b *Builder) *Builder { return b.WhereIf(false, "status = ?", "paid") }, 5},
+		{"WhereIf map", func(b *Builder) *Builder { return b.WhereIf(true, map[string]interface{}{"amount": 30}) }, 1},
+		{"WhereIn strings", func(b *Builder) *Builder { return b.WhereIn("status", []string{"pending", "refunded"}) }, 2},
+		{"WhereIn ints", func(b *Builder) *Builder { return b.WhereIn("amount", []int{10, 50, 99}) }, 2},
+		{"WhereIn single value", func(b *Builder) *Builder { return b.WhereIn("status", "pending") }, 1},
+		{"WhereIn empty", func(b *Builder) *Builder { return b.WhereIn("status", []string{}) }, 0},
+		{"DateRange from", func(b *Builder) *Builder { return b.DateRange("created_at", ptr(day(3)), nil) }, 2},
+		{"DateRange to", func(b *Builder) *Builder { return b.DateRange("created_at", nil, ptr(day(1))) }, 2},
+		{"DateRange both inclusive", func(b *Builder) *Builder { return b.DateRange("created_at", ptr(day(1)), ptr(day(3))) }, 3},
+		{"DateRange neither", func(b *Builder) *Builder { return b.DateRange("created_at", nil, nil) }, 5},
+		{"WhereIf and WhereIn", func(b *Builder) *Builder {
+			return b.WhereIf(true, "amount > ?", 15).WhereIn("status", []string{"paid", "pending"})
+		}, 3},
+		{"WhereIf and DateRange", func(b *Builder) *Builder {
+			return b.WhereIf(true, "status = ?", "paid").DateRange("created_at", ptr(day(1)), nil)
+		}, 2},
+		{"WhereIn and DateRange", func(b *Builder) *Builder {
+			return b.WhereIn("status", []string{"paid"}).DateRange("created_at", nil, ptr(day(0)))
+		}, 1},
+		{"all combined", func(b *Builder) *Builder {
+			return b.WhereIf(true, "amount < ?", 50).
+				WhereIf(false, "status = ?", "nothing").
+				WhereIn("status", []string{"paid", "refunded"}).
+				DateRange("created_at", ptr(day(1)), ptr(day(4)))
+		}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var count int64
+			if err := tt.build(NewBuilder(db.Model(&order{}))).Build().Count(&count).Error; err != nil {
+				t.Fatalf("Count: %v", err)
+			}
+			if count != tt.want {
+				t.Errorf("count = %d, want %d", count, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// StatsProvider 提供连接池统计信息，*sql.DB 即满足该接口
+type StatsProvider interface {
+	Stats() sql.DBStats
+}
+
+// LogConnectionStateChanges 每隔 pollInterval 读取一次 db.Stats() 并与上一次比较：
+// 等待连接次数增加或打开的连接数降为 0 时记录 Warn 日志，连接恢复或连接池上限变化时记录 Info 日志。
+// 返回的函数用于停止轮询，可重复调用
+func LogConnectionStateChanges(db StatsProvider, logger *zap.Logger, pollInterval time.Duration) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	// 在返回前读取基准，避免调用方紧接着发生的变化被当作基准
+	prev := db.Stats()
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				current := db.Stats()
+				logStatsChange(logger, prev, current)
+				prev = current
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-done
+		})
+	}
+}
+
+// logStatsChange 比较两次统计并记录显著变化
+func logStatsChange(logger *zap.Logger, prev, current sql.DBStats) {
+	fields := []zap.Field{
+		zap.Int("open_connections", current.OpenConnections),
+		zap.Int("max_open_connections", current.MaxOpenConnections),
+		zap.Int64("wait_count", current.WaitCount),
+	}
+	if current.WaitCount > prev.WaitCount {
+		logger.Warn("db connection pool exhausted, callers waited for a connection",
+			append(fields, zap.Int64("new_waits", current.WaitCount-prev.WaitCount))...)
+	}
+	if prev.OpenConnections > 0 && current.OpenConnections == 0 {
+		logger.Warn("db connections dropped to zero", fields...)
+	}
+	if prev.OpenConnections == 0 && current.OpenConnections > 0 {
+		logger.Info("db connections restored", fields...)
+	}
+	if prev.MaxOpenConnections != current.MaxOpenConnections {
+		logger.Info("db max open connections changed",
+			append(fields, zap.Int("previous_max_open_connections", prev.MaxOpenConnections))...)
+	}
+}
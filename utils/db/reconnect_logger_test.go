@@ -0,0 +1,99 @@
+package db
+
+import (
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// fakeStats 由测试设置连接池统计的 StatsProvider
+type fakeStats struct {
+	mu    sync.Mutex
+	stats sql.DBStats
+}
+
+func (f *fakeStats) Stats() sql.DBStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stats
+}
+
+func (f *fakeStats) set(fn func(s *sql.DBStats)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fn(&f.stats)
+}
+
+// waitForLog 等待出现 message 日志，超时返回 nil
+func waitForLog(logs *observer.ObservedLogs, message string) *observer.LoggedEntry {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if entries := logs.FilterMessage(message).All(); len(entries) > 0 {
+			return &entries[0]
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return nil
+}
+
+func TestLogConnectionStateChanges(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	stats := &fakeStats{stats: sql.DBStats{MaxOpenConnections: 10, OpenConnections: 3}}
+	stop := LogConnectionStateChanges(stats, zap.New(core), 5*time.Millisecond)
+	defer stop()
+
+	stats.set(func(s *sql.DBStats) { s.WaitCount = 4 })
+	entry := waitForLog(logs, "db connection pool exhausted, callers waited for a connection")
+	if entry == nil {
+		t.Fatalf("no warning for a WaitCount increase, entries: %v", logs.All())
+	}
+	if entry.Level != zapcore.WarnLevel || entry.ContextMap()["new_waits"] != int64(4) {
+		t.Errorf("entry = %v %v, want warn with new_waits=4", entry.Level, entry.ContextMap())
+	}
+
+	stats.set(func(s *sql.DBStats) { s.OpenConnections = 0 })
+	if entry := waitForLog(logs, "db connections dropped to zero"); entry == nil || entry.Level != zapcore.WarnLevel {
+		t.Fatalf("no warning when open connections dropped to 0, entries: %v", logs.All())
+	}
+
+	stats.set(func(s *sql.DBStats) { s.OpenConnections = 2 })
+	if entry := waitForLog(logs, "db connections restored"); entry == nil || entry.Level != zapcore.InfoLevel {
+		t.Fatalf("no info entry when connections were restored, entries: %v", logs.All())
+	}
+
+	stats.set(func(s *sql.DBStats) { s.MaxOpenConnections = 20 })
+	entry = waitForLog(logs, "db max open connections changed")
+	if entry == nil || entry.ContextMap()["previous_max_open_connections"] != int64(10) {
+		t.Fatalf("no entry for a max open connections change, entries: %v", logs.All())
+	}
+
+	// 每次变化只记录一次，统计不变时不再输出
+	time.Sleep(30 * time.Millisecond)
+	if n := logs.Len(); n != 4 {
+		t.Errorf("%d entries, want one per change: %v", n, logs.All())
+	}
+
+	stop()
+	stop()
+	stats.set(func(s *sql.DBStats) { s.WaitCount = 100 })
+	time.Sleep(20 * time.Millisecond)
+	if n := logs.Len(); n != 4 {
+		t.Errorf("entries logged after stop: %v", logs.All())
+	}
+}
+
+func TestLogStatsChangeNoChange(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	stats := sql.DBStats{MaxOpenConnections: 10, OpenConnections: 3, WaitCount: 2}
+	logStatsChange(zap.New(core), stats, stats)
+	// 等待次数减少（如统计被重置）不视为连接池耗尽
+	logStatsChange(zap.New(core), stats, sql.DBStats{MaxOpenConnections: 10, OpenConnections: 3})
+	if logs.Len() != 0 {
+		t.Errorf("entries = %v, want none", logs.All())
+	}
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repository 针对单个模型的通用 CRUD，所有方法返回的错误都经过 WrapGORMError
+type Repository[T any, ID any] struct {
+	db *gorm.DB
+}
+
+// NewRepository 基于 db 创建 Repository
+func NewRepository[T any, ID any](db *gorm.DB) *Repository[T, ID] {
+	return &Repository[T, ID]{db: db}
+}
+
+// FindByID 按主键查询，记录不存在时返回 ErrNotFound
+func (r *Repository[T, ID]) FindByID(ctx context.Context, id ID) (*T, error) {
+	var model T
+	if err := r.db.WithContext(ctx).First(&model, id).Error; err != nil {
+		return nil, WrapGORMError(err)
+	}
+	return &model, nil
+}
+
+// Find 查询满足条件的所有记录，query 与 args 的含义同 gorm.DB.Where；query 为 nil 时返回全部记录
+func (r *Repository[T, ID]) Find(ctx context.Context, query interface{}, args ...interface{}) ([]T, error) {
+	tx := r.db.WithContext(ctx)
+	if query != nil {
+		tx = tx.Where(query, args...)
+	}
+	var models []T
+	if err := tx.Find(&models).Error; err != nil {
+		return nil, WrapGORMError(err)
+	}
+	return models, nil
+}
+
+// Create 插入记录
+func (r *Repository[T, ID]) Create(ctx context.Context, model *T) error {
+	return WrapGORMError(r.db.WithContext(ctx).Create(model).Error)
+}
+
+// Save 保存记录的所有字段，主键为零值时插入
+func (r *Repository[T, ID]) Save(ctx context.Context, model *T) error {
+	return WrapGORMError(r.db.WithContext(ctx).Save(model).Error)
+}
+
+// Delete 按主键删除记录，记录不存在时返回 ErrNotFound
+func (r *Repository[T, ID]) Delete(ctx context.Context, id ID) error {
+	var model T
+	result := r.db.WithContext(ctx).Delete(&model, id)
+	if result.Error != nil {
+		return WrapGORMError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// WithTx 返回使用事务 tx 的 Repository
+func (r *Repository[T, ID]) WithTx(tx *gorm.DB) *Repository[T, ID] {
+	return &Repository[T, ID]{db: tx}
+}
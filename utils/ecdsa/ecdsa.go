@@ -0,0 +1,101 @@
+package ecdsa
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+)
+
+// PEM 块类型
+const (
+	ecPrivateKeyType = "EC PRIVATE KEY"
+	publicKeyType    = "PUBLIC KEY"
+)
+
+// ErrInvalidSignature 签名校验失败
+var ErrInvalidSignature = errors.New("ecdsa: invalid signature")
+
+// GenerateKeyPair 生成 ECDSA 密钥对，仅支持 P-256 和 P-384 曲线
+func GenerateKeyPair(curve elliptic.Curve) (*ecdsa.PrivateKey, *ecdsa.PublicKey, error) {
+	if curve != elliptic.P256() && curve != elliptic.P384() {
+		return nil, nil, errors.New("ecdsa: only P-256 and P-384 curves are supported")
+	}
+	priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return priv, &priv.PublicKey, nil
+}
+
+// Sign 对消息做 SHA-256 摘要后签名，返回 ASN.1 DER 编码的 (r, s)
+func Sign(priv *ecdsa.PrivateKey, message []byte) ([]byte, error) {
+	digest := sha256.Sum256(message)
+	return ecdsa.SignASN1(rand.Reader, priv, digest[:])
+}
+
+// Verify 校验 DER 编码的签名，失败时返回 ErrInvalidSignature
+func Verify(pub *ecdsa.PublicKey, message, sig []byte) error {
+	digest := sha256.Sum256(message)
+	if !ecdsa.VerifyASN1(pub, digest[:], sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// PrivateKeyToPEM 将私钥编码为 SEC 1 格式的 PEM
+func PrivateKeyToPEM(priv *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	return encodePEM(ecPrivateKeyType, der)
+}
+
+// PEMToPrivateKey 解析 SEC 1 格式的 PEM 私钥
+func PEMToPrivateKey(pemBytes []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil || block.Type != ecPrivateKeyType {
+		return nil, errors.New("ecdsa: invalid private key PEM block")
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+// PublicKeyToPEM 将公钥编码为 PKIX 格式的 PEM
+func PublicKeyToPEM(pub *ecdsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return encodePEM(publicKeyType, der)
+}
+
+// PEMToPublicKey 解析 PKIX 格式的 PEM 公钥，公钥类型不是 ECDSA 时返回错误
+func PEMToPublicKey(pemBytes []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil || block.Type != publicKeyType {
+		return nil, errors.New("ecdsa: invalid public key PEM block")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("ecdsa: public key is not an ECDSA key")
+	}
+	return pub, nil
+}
+
+// encodePEM 将 DER 数据编码为 PEM
+func encodePEM(blockType string, der []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := pem.Encode(&buf, &pem.Block{Type: blockType, Bytes: der}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,106 @@
+package ecdsa
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func hexInt(t *testing.T, s string) *big.Int {
+	t.Helper()
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		t.Fatalf("invalid hex %q", s)
+	}
+	return n
+}
+
+// P-256 + SHA-256 已知签名向量（RFC 6979 附录 A.2.5，NIST P-256 曲线）
+func TestVerifyP256KnownVectors(t *testing.T) {
+	pub := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     hexInt(t, "60FED4BA255A9D31C961EB74C6356D68C049B8923B61FA6CE669622E60F29FB6"),
+		Y:     hexInt(t, "7903FE1008B8BC99A41AE9E95628BC64F2F1B20C2D7E9F5177A3C294D4462299"),
+	}
+	vectors := []struct {
+		message string
+		r, s    string
+	}{
+		{"sample", "EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716", "F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8"},
+		{"test", "F1ABB023518351CD71D881567B1EA663ED3EFCF6C5132B354F28D3B0B7D38367", "019F4113742A2B14BD25926B49C649155F267E60D3814B4C0CC84250E46F0083"},
+	}
+	for _, v := range vectors {
+		t.Run(v.message, func(t *testing.T) {
+			sig, err := asn1.Marshal(struct{ R, S *big.Int }{hexInt(t, v.r), hexInt(t, v.s)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := Verify(pub, []byte(v.message), sig); err != nil {
+				t.Errorf("known-good signature rejected: %v", err)
+			}
+			if err := Verify(pub, []byte(v.message+"."), sig); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("modified message err = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+
+	// 私钥 x 推出的公钥应与向量一致
+	x := hexInt(t, "C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721")
+	px, py := elliptic.P256().ScalarBaseMult(x.Bytes())
+	if px.Cmp(pub.X) != 0 || py.Cmp(pub.Y) != 0 {
+		t.Error("public key does not match the private key of the vector")
+	}
+}
+
+func TestSignVerifyAndPEM(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P256(), elliptic.P384()} {
+		t.Run(curve.Params().Name, func(t *testing.T) {
+			priv, pub, err := GenerateKeyPair(curve)
+			if err != nil {
+				t.Fatalf("GenerateKeyPair: %v", err)
+			}
+			message := []byte("service-to-service call")
+			sig, err := Sign(priv, message)
+			if err != nil {
+				t.Fatalf("Sign: %v", err)
+			}
+			if err := Verify(pub, message, sig); err != nil {
+				t.Errorf("Verify: %v", err)
+			}
+			modified := append([]byte{}, message...)
+			modified[0] ^= 1
+			if err := Verify(pub, modified, sig); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("modified message err = %v, want ErrInvalidSignature", err)
+			}
+
+			privPEM, err := PrivateKeyToPEM(priv)
+			if err != nil {
+				t.Fatalf("PrivateKeyToPEM: %v", err)
+			}
+			decodedPriv, err := PEMToPrivateKey(privPEM)
+			if err != nil || !decodedPriv.Equal(priv) {
+				t.Fatalf("PEMToPrivateKey = %v, %v", decodedPriv, err)
+			}
+			pubPEM, err := PublicKeyToPEM(pub)
+			if err != nil {
+				t.Fatalf("PublicKeyToPEM: %v", err)
+			}
+			decodedPub, err := PEMToPublicKey(pubPEM)
+			if err != nil || !decodedPub.Equal(pub) {
+				t.Fatalf("PEMToPublicKey = %v, %v", decodedPub, err)
+			}
+			if _, err := PEMToPublicKey(privPEM); err == nil {
+				t.Error("PEMToPublicKey accepted a private key block")
+			}
+		})
+	}
+}
+
+func TestGenerateKeyPairUnsupportedCurve(t *testing.T) {
+	if _, _, err := GenerateKeyPair(elliptic.P521()); err == nil {
+		t.Fatal("expected error for P-521")
+	}
+}
@@ -0,0 +1,111 @@
+package errorstore
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+)
+
+// ErrorRecord 一条持久化的错误记录，包含错误信息、堆栈与上下文字段，便于事后复现
+type ErrorRecord struct {
+	ID        string                 `gorm:"primaryKey;size:36" json:"id"`
+	Level     string                 `gorm:"size:16;index" json:"level"`
+	Message   string                 `json:"message"`
+	Stack     string                 `json:"stack"`
+	Fields    map[string]interface{} `gorm:"serializer:json" json:"fields"`
+	CreatedAt time.Time              `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定错误记录的表名
+func (ErrorRecord) TableName() string {
+	return "error_records"
+}
+
+// ErrorFilter List 的查询条件，零值字段不参与过滤
+type ErrorFilter struct {
+	From  time.Time
+	To    time.Time
+	Level string
+	Limit int
+}
+
+// ErrorStore 基于 GORM 的错误存储，可使用 SQLite 文件等任意数据库
+type ErrorStore struct {
+	db *gorm.DB
+}
+
+// New 创建 ErrorStore 并自动建表
+func New(db *gorm.DB) (*ErrorStore, error) {
+	if err := db.AutoMigrate(&ErrorRecord{}); err != nil {
+		return nil, err
+	}
+	return &ErrorStore{db: db}, nil
+}
+
+// Capture 保存错误及其上下文字段并返回记录 ID；err 为 nil 或保存失败时返回空字符串
+func (s *ErrorStore) Capture(ctx context.Context, err error, fields ...zapcore.Field) string {
+	if err == nil {
+		return ""
+	}
+	return s.save(ctx, zapcore.ErrorLevel, err.Error(), string(debug.Stack()), fields)
+}
+
+// CaptureEntry 保存一条 zap 日志条目，供 logger 的钩子使用
+func (s *ErrorStore) CaptureEntry(ent zapcore.Entry, fields []zapcore.Field) string {
+	return s.save(context.Background(), ent.Level, ent.Message, ent.Stack, fields)
+}
+
+// Get 按 ID 查询错误记录
+func (s *ErrorStore) Get(id string) (*ErrorRecord, error) {
+	var record ErrorRecord
+	if err := s.db.Where("id = ?", id).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// List 按时间范围和级别查询错误记录，结果按时间倒序
+func (s *ErrorStore) List(filter ErrorFilter) ([]ErrorRecord, error) {
+	query := s.db.Model(&ErrorRecord{})
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at <= ?", filter.To)
+	}
+	if filter.Level != "" {
+		query = query.Where("level = ?", filter.Level)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	var records []ErrorRecord
+	if err := query.Order("created_at DESC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// save 序列化字段并写入数据库
+func (s *ErrorStore) save(ctx context.Context, level zapcore.Level, message, stack string, fields []zapcore.Field) string {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	record := ErrorRecord{
+		ID:        uuid.NewString(),
+		Level:     level.String(),
+		Message:   message,
+		Stack:     stack,
+		Fields:    enc.Fields,
+		CreatedAt: time.Now(),
+	}
+	if err := s.db.WithContext(ctx).Create(&record).Error; err != nil {
+		return ""
+	}
+	return record.ID
+}
@@ -0,0 +1,108 @@
+package errorstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestStore(t *testing.T) *ErrorStore {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	// 内存库每个连接都是独立的数据库，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	store, err := New(db)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return store
+}
+
+func TestCaptureAndGet(t *testing.T) {
+	store := newTestStore(t)
+
+	id := store.Capture(context.Background(), errors.New("payment failed"),
+		zap.String("order_id", "o-1"), zap.Int("attempt", 3))
+	if id == "" {
+		t.Fatal("Capture returned empty id")
+	}
+
+	record, err := store.Get(id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if record.Message != "payment failed" || record.Level != "error" {
+		t.Errorf("record = %q/%q, want payment failed/error", record.Message, record.Level)
+	}
+	if record.Stack == "" {
+		t.Error("stack not captured")
+	}
+	if record.Fields["order_id"] != "o-1" || record.Fields["attempt"] != float64(3) {
+		t.Errorf("fields = %v", record.Fields)
+	}
+
+	if _, err := store.Get("missing"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Get(missing) err = %v, want ErrRecordNotFound", err)
+	}
+	if id := store.Capture(context.Background(), nil); id != "" {
+		t.Errorf("Capture(nil) = %q, want empty", id)
+	}
+}
+
+func TestList(t *testing.T) {
+	store := newTestStore(t)
+	now := time.Now()
+	for message, age := range map[string]time.Duration{"old": 2 * time.Hour, "recent error": 10 * time.Minute, "recent warn": time.Minute} {
+		level := zapcore.WarnLevel
+		if message == "recent error" {
+			level = zapcore.ErrorLevel
+		}
+		id := store.CaptureEntry(zapcore.Entry{Level: level, Message: message}, nil)
+		store.db.Model(&ErrorRecord{}).Where("id = ?", id).Update("created_at", now.Add(-age))
+	}
+
+	tests := []struct {
+		name   string
+		filter ErrorFilter
+		want   []string
+	}{
+		{"all newest first", ErrorFilter{}, []string{"recent warn", "recent error", "old"}},
+		{"level", ErrorFilter{Level: "warn"}, []string{"recent warn", "old"}},
+		{"from", ErrorFilter{From: now.Add(-time.Hour)}, []string{"recent warn", "recent error"}},
+		{"to", ErrorFilter{To: now.Add(-time.Hour)}, []string{"old"}},
+		{"limit", ErrorFilter{Limit: 1}, []string{"recent warn"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := store.List(tt.filter)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			var got []string
+			for _, r := range records {
+				got = append(got, r.Message)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
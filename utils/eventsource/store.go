@@ -0,0 +1,118 @@
+package eventsource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrVersionConflict 追加事件时聚合的当前版本与期望版本不一致（乐观并发控制）
+var ErrVersionConflict = errors.New("eventsource: version conflict")
+
+// StoredEvent 已持久化的事件
+type StoredEvent[E any] struct {
+	AggregateID string
+	Sequence    int64
+	OccurredAt  time.Time
+	Payload     E
+}
+
+// eventRecord 事件表的行结构，(aggregate_id, sequence) 唯一，保证同一版本只能写入一次
+type eventRecord struct {
+	ID          uint      `gorm:"primaryKey"`
+	AggregateID string    `gorm:"size:191;not null;uniqueIndex:idx_aggregate_sequence,priority:1"`
+	Sequence    int64     `gorm:"not null;uniqueIndex:idx_aggregate_sequence,priority:2"`
+	OccurredAt  time.Time `gorm:"not null"`
+	Payload     []byte    `gorm:"not null"`
+}
+
+// Store 基于 GORM 的只追加事件存储，事件以 JSON 保存
+type Store[E any] struct {
+	db    *gorm.DB
+	table string
+}
+
+// New 创建事件存储并自动建表
+func New[E any](db *gorm.DB, table string) (*Store[E], error) {
+	if err := db.Table(table).AutoMigrate(&eventRecord{}); err != nil {
+		return nil, err
+	}
+	return &Store[E]{db: db, table: table}, nil
+}
+
+// Append 追加事件，序号从 expectedVersion+1 开始递增；
+// 聚合当前的最大序号不等于 expectedVersion 时返回 ErrVersionConflict
+func (s *Store[E]) Append(ctx context.Context, aggregateID string, events []E, expectedVersion int64) error {
+	if len(events) == 0 {
+		return nil
+	}
+	now := time.Now()
+	records := make([]eventRecord, len(events))
+	for i, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		records[i] = eventRecord{
+			AggregateID: aggregateID,
+			Sequence:    expectedVersion + int64(i) + 1,
+			OccurredAt:  now,
+			Payload:     payload,
+		}
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		current, err := s.currentVersion(tx, aggregateID)
+		if err != nil {
+			return err
+		}
+		if current != expectedVersion {
+			return ErrVersionConflict
+		}
+		return tx.Table(s.table).Create(&records).Error
+	})
+	if err != nil && !errors.Is(err, ErrVersionConflict) {
+		// 并发写入时唯一索引冲突，确认版本已被其他写入推进后按版本冲突返回
+		if current, verr := s.currentVersion(s.db.WithContext(ctx), aggregateID); verr == nil && current != expectedVersion {
+			return ErrVersionConflict
+		}
+	}
+	return err
+}
+
+// Load 按序号升序返回聚合在 fromVersion 之后的所有事件
+func (s *Store[E]) Load(ctx context.Context, aggregateID string, fromVersion int64) ([]StoredEvent[E], error) {
+	var records []eventRecord
+	err := s.db.WithContext(ctx).Table(s.table).
+		Where("aggregate_id = ? AND sequence > ?", aggregateID, fromVersion).
+		Order("sequence ASC").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	events := make([]StoredEvent[E], len(records))
+	for i, record := range records {
+		events[i] = StoredEvent[E]{
+			AggregateID: record.AggregateID,
+			Sequence:    record.Sequence,
+			OccurredAt:  record.OccurredAt,
+		}
+		if err := json.Unmarshal(record.Payload, &events[i].Payload); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+// currentVersion 返回聚合当前的最大序号，没有事件时为 0
+func (s *Store[E]) currentVersion(db *gorm.DB, aggregateID string) (int64, error) {
+	var current int64
+	err := db.Table(s.table).
+		Where("aggregate_id = ?", aggregateID).
+		Select("COALESCE(MAX(sequence), 0)").
+		Scan(&current).Error
+	return current, err
+}
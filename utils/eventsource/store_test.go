@@ -0,0 +1,130 @@
+package eventsource
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type testEvent struct {
+	Kind   string `json:"kind"`
+	Amount int    `json:"amount"`
+}
+
+func newTestStore(t *testing.T) *Store[testEvent] {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	// 内存库每个连接都是独立的数据库，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	store, err := New[testEvent](db, "events")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return store
+}
+
+func TestAppendAndLoad(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	if err := store.Append(ctx, "acc-1", []testEvent{{"opened", 0}, {"deposited", 100}}, 0); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if err := store.Append(ctx, "acc-1", []testEvent{{"withdrawn", 30}}, 2); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if err := store.Append(ctx, "acc-2", []testEvent{{"opened", 0}}, 0); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	events, err := store.Load(ctx, "acc-1", 0)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := []testEvent{{"opened", 0}, {"deposited", 100}, {"withdrawn", 30}}
+	if len(events) != len(want) {
+		t.Fatalf("Load returned %d events, want %d", len(events), len(want))
+	}
+	for i, event := range events {
+		if event.AggregateID != "acc-1" || event.Sequence != int64(i+1) || event.Payload != want[i] {
+			t.Errorf("event %d = %+v, want sequence %d payload %+v", i, event, i+1, want[i])
+		}
+		if event.OccurredAt.IsZero() {
+			t.Errorf("event %d has zero OccurredAt", i)
+		}
+	}
+
+	// fromVersion 之后的事件
+	events, err = store.Load(ctx, "acc-1", 2)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(events) != 1 || events[0].Sequence != 3 {
+		t.Errorf("Load from version 2 = %+v, want only sequence 3", events)
+	}
+}
+
+func TestAppendVersionConflict(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	if err := store.Append(ctx, "acc-1", []testEvent{{"opened", 0}}, 0); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	for _, expected := range []int64{0, 2} {
+		err := store.Append(ctx, "acc-1", []testEvent{{"deposited", 1}}, expected)
+		if !errors.Is(err, ErrVersionConflict) {
+			t.Errorf("Append at version %d: err = %v, want ErrVersionConflict", expected, err)
+		}
+	}
+}
+
+func TestConcurrentAppendConflicts(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	const writers = 10
+	errs := make([]error, writers)
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = store.Append(ctx, "acc-1", []testEvent{{"deposited", i}}, 0)
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case errors.Is(err, ErrVersionConflict):
+		default:
+			t.Errorf("writer %d: unexpected error %v", i, err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("%d appends succeeded at the same expected version, want exactly 1", succeeded)
+	}
+
+	events, err := store.Load(ctx, "acc-1", 0)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(events) != 1 || events[0].Sequence != 1 {
+		t.Errorf("Load = %+v, want a single event with sequence 1", events)
+	}
+}
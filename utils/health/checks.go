@@ -0,0 +1,43 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// DBCheck 通过执行 SELECT 1 检查数据库连接
+func DBCheck(db *gorm.DB, name string, opts ...CheckOption) Check {
+	return NewCheck(name, func(ctx context.Context) error {
+		return db.WithContext(ctx).Exec("SELECT 1").Error
+	}, opts...)
+}
+
+// RedisCheck 通过 PING 检查 Redis 连接，*redis.Client 与 *redis.ClusterClient 均可使用
+func RedisCheck(client redis.UniversalClient, name string, opts ...CheckOption) Check {
+	return NewCheck(name, func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	}, opts...)
+}
+
+// HTTPCheck 发送 GET 请求，响应状态码为 2xx 时视为健康
+func HTTPCheck(url string, name string, opts ...CheckOption) Check {
+	return NewCheck(name, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	}, opts...)
+}
@@ -0,0 +1,138 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newMockDB 基于 sqlmock 创建 *gorm.DB，SQLite 方言初始化时会查询一次版本号
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	mock.ExpectQuery(regexp.QuoteMeta("select sqlite_version()")).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow("3.45.0"))
+	db, err := gorm.Open(sqlite.Dialector{Conn: sqlDB}, &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open gorm: %v", err)
+	}
+	return db, mock
+}
+
+func TestDBCheck(t *testing.T) {
+	errConn := errors.New("connection refused")
+	tests := []struct {
+		name    string
+		expect  func(e *sqlmock.ExpectedExec)
+		opts    []CheckOption
+		wantErr error
+	}{
+		{"healthy", func(e *sqlmock.ExpectedExec) { e.WillReturnResult(sqlmock.NewResult(0, 0)) }, nil, nil},
+		{"query error", func(e *sqlmock.ExpectedExec) { e.WillReturnError(errConn) }, nil, errConn},
+		{"slower than timeout", func(e *sqlmock.ExpectedExec) {
+			e.WillDelayFor(time.Second).WillReturnResult(sqlmock.NewResult(0, 0))
+		}, []CheckOption{WithTimeout(20 * time.Millisecond)}, context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := newMockDB(t)
+			tt.expect(mock.ExpectExec(regexp.QuoteMeta("SELECT 1")))
+
+			check := DBCheck(db, "db", tt.opts...)
+			start := time.Now()
+			err := check.Run(context.Background())
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Run: %v", err)
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					t.Error(err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Run: err = %v, want %v", err, tt.wantErr)
+			}
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("Run took %v, want it bounded by the timeout", elapsed)
+			}
+		})
+	}
+}
+
+func TestHTTPCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusNoContent)
+		case "/slow":
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		url     string
+		opts    []CheckOption
+		wantErr bool
+	}{
+		{"2xx", server.URL + "/ok", nil, false},
+		{"non-2xx", server.URL + "/down", nil, true},
+		{"timeout", server.URL + "/slow", []CheckOption{WithTimeout(20 * time.Millisecond)}, true},
+		{"invalid url", "://bad", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := HTTPCheck(tt.url, "upstream", tt.opts...).Run(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Run: err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRedisCheck(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	check := RedisCheck(client, "redis", WithTimeout(time.Second))
+	if err := check.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	server.Close()
+	if err := check.Run(context.Background()); err == nil {
+		t.Error("Run succeeded after the server was closed")
+	}
+}
+
+func TestCheckErrorIncludesName(t *testing.T) {
+	check := NewCheck("payments", func(ctx context.Context) error { return errors.New("boom") })
+	if err := check.Run(context.Background()); err == nil || err.Error() != "payments: boom" {
+		t.Errorf("Run: err = %v, want it prefixed with the check name", err)
+	}
+	if check.Timeout != defaultTimeout {
+		t.Errorf("Timeout = %v, want the default %v", check.Timeout, defaultTimeout)
+	}
+}
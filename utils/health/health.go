@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// defaultTimeout 未通过 WithTimeout 指定时单个检查的超时时间
+const defaultTimeout = 5 * time.Second
+
+// Check 一项健康检查，可用于存活/就绪探针
+type Check struct {
+	Name    string
+	Timeout time.Duration
+	probe   func(ctx context.Context) error
+}
+
+// CheckOption Check 的可选配置
+type CheckOption func(*Check)
+
+// WithTimeout 设置检查的超时时间
+func WithTimeout(d time.Duration) CheckOption {
+	return func(c *Check) {
+		c.Timeout = d
+	}
+}
+
+// NewCheck 使用自定义探测函数创建检查
+func NewCheck(name string, probe func(ctx context.Context) error, opts ...CheckOption) Check {
+	c := Check{Name: name, Timeout: defaultTimeout, probe: probe}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// Run 执行检查，探测失败或超过 Timeout 时返回错误；探测函数不响应 ctx 时也会按时返回
+func (c Check) Run(ctx context.Context) error {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- c.probe(ctx)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.Name, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%s: %w", c.Name, ctx.Err())
+	}
+}
@@ -0,0 +1,148 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kobyt2/common-services/utils"
+)
+
+// 签名相关的请求头
+const (
+	HeaderTimestamp = "X-Timestamp"
+	HeaderKeyID     = "X-Key-ID"
+	HeaderSignature = "X-Signature"
+)
+
+// SigningAlgo 计算签名使用的 HMAC 算法
+type SigningAlgo string
+
+const (
+	HMACSHA256 SigningAlgo = "HMAC-SHA256"
+	HMACSHA512 SigningAlgo = "HMAC-SHA512"
+)
+
+var (
+	// ErrMissingSignature 请求缺少签名相关的请求头
+	ErrMissingSignature = errors.New("httpclient: missing signature headers")
+	// ErrSignatureExpired 请求时间戳超出允许的时间偏差
+	ErrSignatureExpired = errors.New("httpclient: signature timestamp out of range")
+	// ErrSignatureMismatch 签名不一致
+	ErrSignatureMismatch = errors.New("httpclient: signature mismatch")
+)
+
+// SigningTransport 为每个请求添加 X-Timestamp、X-Key-ID、X-Signature 请求头的 http.RoundTripper，
+// 签名为 HMAC(secret, method + "\n" + path + "\n" + timestamp + "\n" + hex(sha256(body)))
+type SigningTransport struct {
+	base   http.RoundTripper
+	keyID  string
+	secret []byte
+	algo   SigningAlgo
+}
+
+// NewSigningTransport 创建签名 Transport，base 为 nil 时使用 http.DefaultTransport
+func NewSigningTransport(base http.RoundTripper, keyID, secret string, algo SigningAlgo) *SigningTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &SigningTransport{base: base, keyID: keyID, secret: []byte(secret), algo: algo}
+}
+
+// RoundTrip 复制请求并加上签名请求头后交给 base 发送，不修改原请求
+func (t *SigningTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	signed := req.Clone(req.Context())
+	if req.Body != nil {
+		signed.Body = io.NopCloser(bytes.NewReader(body))
+		signed.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature, err := Sign(t.algo, t.secret, req.Method, req.URL.Path, timestamp, body)
+	if err != nil {
+		return nil, err
+	}
+	signed.Header.Set(HeaderTimestamp, timestamp)
+	signed.Header.Set(HeaderKeyID, t.keyID)
+	signed.Header.Set(HeaderSignature, signature)
+	return t.base.RoundTrip(signed)
+}
+
+// Sign 计算签名，返回十六进制字符串；服务端校验时使用同一函数
+func Sign(algo SigningAlgo, secret []byte, method, path, timestamp string, body []byte) (string, error) {
+	var newHash func() hash.Hash
+	switch algo {
+	case HMACSHA256, "":
+		newHash = sha256.New
+	case HMACSHA512:
+		newHash = sha512.New
+	default:
+		return "", fmt.Errorf("httpclient: unsupported signing algorithm %q", algo)
+	}
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(newHash, secret)
+	mac.Write([]byte(method + "\n" + path + "\n" + timestamp + "\n" + hex.EncodeToString(bodyHash[:])))
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// VerifyRequest 服务端校验请求签名，secretFor 根据 X-Key-ID 返回对应的密钥，maxSkew 为允许的时间偏差。
+// 校验后请求体会被恢复，处理函数仍可读取
+func VerifyRequest(r *http.Request, algo SigningAlgo, secretFor func(keyID string) (string, bool), maxSkew time.Duration) error {
+	timestamp := r.Header.Get(HeaderTimestamp)
+	keyID := r.Header.Get(HeaderKeyID)
+	signature := r.Header.Get(HeaderSignature)
+	if timestamp == "" || keyID == "" || signature == "" {
+		return ErrMissingSignature
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrSignatureExpired
+	}
+	if skew := time.Since(time.Unix(unix, 0)); skew > maxSkew || skew < -maxSkew {
+		return ErrSignatureExpired
+	}
+	secret, ok := secretFor(keyID)
+	if !ok {
+		return ErrSignatureMismatch
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return err
+	}
+	if r.Body != nil {
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	expected, err := Sign(algo, []byte(secret), r.Method, r.URL.Path, timestamp, body)
+	if err != nil {
+		return err
+	}
+	if !utils.ConstantTimeEqual(expected, signature) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+// readBody 读取并关闭请求体，请求体为空时返回 nil
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	defer r.Body.Close()
+	return io.ReadAll(r.Body)
+}
@@ -0,0 +1,117 @@
+package httpclient
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newVerifyingServer 校验签名，成功时回显请求体，失败时返回 401 和错误信息
+func newVerifyingServer(t *testing.T, algo SigningAlgo) *httptest.Server {
+	t.Helper()
+	secrets := map[string]string{"client-1": "s3cret"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := VerifyRequest(r, algo, func(keyID string) (string, bool) {
+			secret, ok := secrets[keyID]
+			return secret, ok
+		}, time.Minute)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		io.Copy(w, r.Body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSigningTransport(t *testing.T) {
+	tests := []struct {
+		name   string
+		keyID  string
+		secret string
+		algo   SigningAlgo
+		want   int
+	}{
+		{"valid sha256", "client-1", "s3cret", HMACSHA256, http.StatusOK},
+		{"valid sha512", "client-1", "s3cret", HMACSHA512, http.StatusOK},
+		{"wrong secret", "client-1", "other", HMACSHA256, http.StatusUnauthorized},
+		{"unknown key", "client-2", "s3cret", HMACSHA256, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newVerifyingServer(t, tt.algo)
+			client := &http.Client{Transport: NewSigningTransport(nil, tt.keyID, tt.secret, tt.algo)}
+			resp, err := client.Post(srv.URL+"/orders?x=1", "application/json", strings.NewReader(`{"id":1}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d (%s), want %d", resp.StatusCode, body, tt.want)
+			}
+			// 校验后处理函数仍能读到完整的请求体
+			if tt.want == http.StatusOK && string(body) != `{"id":1}` {
+				t.Errorf("echoed body = %q", body)
+			}
+		})
+	}
+}
+
+func TestSigningTransportGetWithoutBody(t *testing.T) {
+	srv := newVerifyingServer(t, HMACSHA256)
+	client := &http.Client{Transport: NewSigningTransport(nil, "client-1", "s3cret", HMACSHA256)}
+	resp, err := client.Get(srv.URL + "/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestVerifyRequestErrors(t *testing.T) {
+	secretFor := func(string) (string, bool) { return "s3cret", true }
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	sig, _ := Sign(HMACSHA256, []byte("s3cret"), http.MethodPost, "/orders", now, []byte("body"))
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	oldSig, _ := Sign(HMACSHA256, []byte("s3cret"), http.MethodPost, "/orders", old, []byte("body"))
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		body      string
+		want      error
+	}{
+		{"missing headers", "", "", "body", ErrMissingSignature},
+		{"expired", old, oldSig, "body", ErrSignatureExpired},
+		{"bad timestamp", "yesterday", sig, "body", ErrSignatureExpired},
+		{"tampered body", now, sig, "other", ErrSignatureMismatch},
+		{"valid", now, sig, "body", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tt.body))
+			if tt.timestamp != "" {
+				r.Header.Set(HeaderTimestamp, tt.timestamp)
+				r.Header.Set(HeaderKeyID, "client-1")
+				r.Header.Set(HeaderSignature, tt.signature)
+			}
+			if err := VerifyRequest(r, HMACSHA256, secretFor, time.Minute); !errors.Is(err, tt.want) {
+				t.Errorf("VerifyRequest = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := Sign("MD5", nil, "GET", "/", now, nil); err == nil {
+		t.Error("Sign accepted an unsupported algorithm")
+	}
+}
@@ -0,0 +1,111 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrNoPrivateKey Manager 只有公钥，不能签发 token
+	ErrNoPrivateKey = errors.New("jwt: manager has no private key")
+	// ErrInvalidToken token 签名、算法或有效期校验失败
+	ErrInvalidToken = errors.New("jwt: invalid token")
+)
+
+// Manager 使用 ECDSA 签发和校验 JWT，P-256 密钥使用 ES256，P-384 密钥使用 ES384
+type Manager struct {
+	privateKey *ecdsa.PrivateKey
+	publicKey  *ecdsa.PublicKey
+	method     gojwt.SigningMethod
+	issuer     string
+	ttl        time.Duration
+}
+
+// Option Manager 的可选配置
+type Option func(*Manager)
+
+// WithIssuer 签发时写入 iss，校验时要求 iss 一致
+func WithIssuer(issuer string) Option {
+	return func(m *Manager) {
+		m.issuer = issuer
+	}
+}
+
+// WithTTL 设置 token 有效期，默认 5 分钟
+func WithTTL(ttl time.Duration) Option {
+	return func(m *Manager) {
+		m.ttl = ttl
+	}
+}
+
+// NewManager 创建可签发和校验 token 的 Manager
+func NewManager(privateKey *ecdsa.PrivateKey, opts ...Option) (*Manager, error) {
+	m, err := newManager(&privateKey.PublicKey, opts)
+	if err != nil {
+		return nil, err
+	}
+	m.privateKey = privateKey
+	return m, nil
+}
+
+// NewVerifier 创建只能校验 token 的 Manager，供只接收调用的服务使用
+func NewVerifier(publicKey *ecdsa.PublicKey, opts ...Option) (*Manager, error) {
+	return newManager(publicKey, opts)
+}
+
+func newManager(publicKey *ecdsa.PublicKey, opts []Option) (*Manager, error) {
+	m := &Manager{publicKey: publicKey, ttl: 5 * time.Minute}
+	switch publicKey.Curve {
+	case elliptic.P256():
+		m.method = gojwt.SigningMethodES256
+	case elliptic.P384():
+		m.method = gojwt.SigningMethodES384
+	default:
+		return nil, errors.New("jwt: only P-256 and P-384 keys are supported")
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m, nil
+}
+
+// Sign 签发包含 claims 的 token，并自动写入 iat、exp 以及配置的 iss
+func (m *Manager) Sign(claims map[string]interface{}) (string, error) {
+	if m.privateKey == nil {
+		return "", ErrNoPrivateKey
+	}
+	now := time.Now()
+	mapClaims := gojwt.MapClaims{}
+	for k, v := range claims {
+		mapClaims[k] = v
+	}
+	mapClaims["iat"] = now.Unix()
+	mapClaims["exp"] = now.Add(m.ttl).Unix()
+	if m.issuer != "" {
+		mapClaims["iss"] = m.issuer
+	}
+	return gojwt.NewWithClaims(m.method, mapClaims).SignedString(m.privateKey)
+}
+
+// Verify 校验 token 的签名、算法、有效期和 iss，返回其中的 claims
+func (m *Manager) Verify(token string) (map[string]interface{}, error) {
+	opts := []gojwt.ParserOption{
+		gojwt.WithValidMethods([]string{m.method.Alg()}),
+		gojwt.WithExpirationRequired(),
+	}
+	if m.issuer != "" {
+		opts = append(opts, gojwt.WithIssuer(m.issuer))
+	}
+	claims := gojwt.MapClaims{}
+	_, err := gojwt.ParseWithClaims(token, claims, func(*gojwt.Token) (interface{}, error) {
+		return m.publicKey, nil
+	}, opts...)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidToken, err)
+	}
+	return claims, nil
+}
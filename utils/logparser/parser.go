@@ -0,0 +1,251 @@
+package logparser
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxLineSize 单行日志的最大长度，超出时 Parse 返回错误
+const maxLineSize = 1 << 20
+
+// 输出格式
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// defaultTimeLayouts 按顺序尝试的时间格式，覆盖 logger 的默认格式与 RFC3339
+var defaultTimeLayouts = []string{
+	"2006-01-02 15:04:05.000",
+	time.RFC3339Nano,
+	time.RFC3339,
+}
+
+// LogEntry 一条解析后的日志，Time、Level、Message 以外的键都放在 Fields 中
+type LogEntry struct {
+	Time    time.Time
+	Level   string
+	Message string
+	Fields  map[string]interface{}
+}
+
+// Parser NDJSON 日志解析器，键名默认与 logger 包的编码配置一致
+type Parser struct {
+	TimeKey     string
+	LevelKey    string
+	MessageKey  string
+	TimeLayouts []string
+}
+
+// New 创建使用默认键名的 Parser
+func New() *Parser {
+	return &Parser{
+		TimeKey:     "time",
+		LevelKey:    "level",
+		MessageKey:  "msg",
+		TimeLayouts: defaultTimeLayouts,
+	}
+}
+
+// Parse 逐行解析 r 中的日志并通过通道输出，空行被跳过；遇到无法解析的行时
+// 在错误通道中返回带行号的错误并停止。两个通道都会在结束后关闭
+func (p *Parser) Parse(r io.Reader) (<-chan LogEntry, <-chan error) {
+	entries := make(chan LogEntry)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(entries)
+		defer close(errs)
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		line := 0
+		for scanner.Scan() {
+			line++
+			data := strings.TrimSpace(scanner.Text())
+			if data == "" {
+				continue
+			}
+			entry, err := p.parseLine([]byte(data))
+			if err != nil {
+				errs <- fmt.Errorf("logparser: line %d: %v", line, err)
+				return
+			}
+			entries <- entry
+		}
+		if err := scanner.Err(); err != nil {
+			errs <- err
+		}
+	}()
+	return entries, errs
+}
+
+// Parse 使用默认配置解析日志
+func Parse(r io.Reader) (<-chan LogEntry, <-chan error) {
+	return New().Parse(r)
+}
+
+// Filter 只保留满足 pred 的日志，entries 关闭后返回的通道随之关闭
+func Filter(entries <-chan LogEntry, pred func(LogEntry) bool) <-chan LogEntry {
+	out := make(chan LogEntry)
+	go func() {
+		defer close(out)
+		for entry := range entries {
+			if pred(entry) {
+				out <- entry
+			}
+		}
+	}()
+	return out
+}
+
+// GroupBy 读完 entries 并按字段 key 的值分组，key 也可以是 time、level、msg；
+// 没有该字段的日志归入空字符串分组
+func GroupBy(entries <-chan LogEntry, key string) map[string][]LogEntry {
+	groups := make(map[string][]LogEntry)
+	for entry := range entries {
+		value := entry.value(key)
+		groups[value] = append(groups[value], entry)
+	}
+	return groups
+}
+
+// WriteTo 将日志重新序列化为 json（每行一个对象）或 console（制表符分隔）格式写入 w
+func WriteTo(entries <-chan LogEntry, w io.Writer, format string) error {
+	var write func(LogEntry) error
+	switch format {
+	case "", FormatJSON:
+		enc := json.NewEncoder(w)
+		write = func(entry LogEntry) error {
+			return enc.Encode(entry.toMap())
+		}
+	case FormatConsole:
+		write = func(entry LogEntry) error {
+			return writeConsole(w, entry)
+		}
+	default:
+		return fmt.Errorf("logparser: unknown format %q", format)
+	}
+	for entry := range entries {
+		if err := write(entry); err != nil {
+			// 排空通道，避免上游 goroutine 阻塞
+			for range entries {
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// parseLine 解析单行 JSON 日志
+func (p *Parser) parseLine(data []byte) (LogEntry, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return LogEntry{}, err
+	}
+	entry := LogEntry{Fields: fields}
+	if raw, ok := fields[p.TimeKey]; ok {
+		entry.Time = p.parseTime(raw)
+		delete(fields, p.TimeKey)
+	}
+	if raw, ok := fields[p.LevelKey].(string); ok {
+		entry.Level = raw
+		delete(fields, p.LevelKey)
+	}
+	if raw, ok := fields[p.MessageKey].(string); ok {
+		entry.Message = raw
+		delete(fields, p.MessageKey)
+	}
+	return entry, nil
+}
+
+// parseTime 解析字符串时间或 unix 时间戳（秒，带小数），无法识别时返回零值
+func (p *Parser) parseTime(raw interface{}) time.Time {
+	switch v := raw.(type) {
+	case string:
+		for _, layout := range p.TimeLayouts {
+			if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+				return t
+			}
+		}
+	case float64:
+		// 大于 1e12 的值视为纳秒时间戳
+		if v > 1e12 {
+			return time.Unix(0, int64(v))
+		}
+		sec := int64(v)
+		return time.Unix(sec, int64((v-float64(sec))*1e9))
+	}
+	return time.Time{}
+}
+
+// value 返回用于分组的字符串值
+func (e LogEntry) value(key string) string {
+	switch key {
+	case "time":
+		return e.Time.Format(time.RFC3339Nano)
+	case "level":
+		return e.Level
+	case "msg":
+		return e.Message
+	}
+	v, ok := e.Fields[key]
+	if !ok {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// toMap 转换为 JSON 输出用的 map
+func (e LogEntry) toMap() map[string]interface{} {
+	m := make(map[string]interface{}, len(e.Fields)+3)
+	for k, v := range e.Fields {
+		m[k] = v
+	}
+	if !e.Time.IsZero() {
+		m["time"] = e.Time.Format(time.RFC3339Nano)
+	}
+	m["level"] = e.Level
+	m["msg"] = e.Message
+	return m
+}
+
+// writeConsole 按 "时间\t级别\t消息\t{字段}" 的格式写出一条日志，字段按键排序
+func writeConsole(w io.Writer, e LogEntry) error {
+	var b strings.Builder
+	if !e.Time.IsZero() {
+		b.WriteString(e.Time.Format(defaultTimeLayouts[0]))
+		b.WriteByte('\t')
+	}
+	b.WriteString(strings.ToUpper(e.Level))
+	b.WriteByte('\t')
+	b.WriteString(e.Message)
+	if len(e.Fields) > 0 {
+		keys := make([]string, 0, len(e.Fields))
+		for k := range e.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString("\t{")
+		for i, k := range keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			value, err := json.Marshal(e.Fields[k])
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(&b, "%q: %s", k, value)
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte('\n')
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,138 @@
+package logparser
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeSampleLog 写出 n 条日志：level 依次为 info、warn、error，service 在 api、worker 间交替
+func writeSampleLog(t *testing.T, n int) string {
+	t.Helper()
+	var b strings.Builder
+	levels := []string{"info", "warn", "error"}
+	services := []string{"api", "worker"}
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local)
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, `{"level":%q,"time":%q,"msg":"request %d","service":%q,"latency_ms":%d}`+"\n",
+			levels[i%3], start.Add(time.Duration(i)*time.Millisecond).Format("2006-01-02 15:04:05.000"), i, services[i%2], i)
+	}
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// collect 读完 entries，并检查错误通道没有错误
+func collect(t *testing.T, entries <-chan LogEntry, errs <-chan error) []LogEntry {
+	t.Helper()
+	var out []LogEntry
+	for entry := range entries {
+		out = append(out, entry)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return out
+}
+
+func TestParseAndFilter(t *testing.T) {
+	f, err := os.Open(writeSampleLog(t, 1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	entries, errs := Parse(f)
+	errorEntries := Filter(entries, func(e LogEntry) bool { return e.Level == "error" })
+	got := collect(t, errorEntries, errs)
+	// 1000 条中下标为 3 的倍数加 2 的共 333 条
+	if len(got) != 333 {
+		t.Fatalf("Filter kept %d entries, want 333", len(got))
+	}
+	first := got[0]
+	if first.Message != "request 2" || first.Fields["service"] != "api" || first.Fields["latency_ms"] != float64(2) {
+		t.Errorf("first entry = %+v", first)
+	}
+	if want := time.Date(2024, 5, 1, 12, 0, 0, 2e6, time.Local); !first.Time.Equal(want) {
+		t.Errorf("Time = %v, want %v", first.Time, want)
+	}
+	if _, ok := first.Fields["level"]; ok {
+		t.Error("level left in Fields")
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	f, err := os.Open(writeSampleLog(t, 1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	entries, _ := Parse(f)
+	groups := GroupBy(entries, "service")
+	if len(groups) != 2 || len(groups["api"]) != 500 || len(groups["worker"]) != 500 {
+		t.Errorf("groups = api %d, worker %d (%d groups)", len(groups["api"]), len(groups["worker"]), len(groups))
+	}
+
+	entries, _ = Parse(strings.NewReader(`{"level":"info","msg":"a"}` + "\n" + `{"level":"warn","msg":"b","service":"api"}`))
+	groups = GroupBy(entries, "level")
+	if len(groups["info"]) != 1 || len(groups["warn"]) != 1 {
+		t.Errorf("groups by level = %v", groups)
+	}
+}
+
+func TestParseErrorReportsLine(t *testing.T) {
+	input := `{"level":"info","msg":"ok"}` + "\n\n" + `not json` + "\n" + `{"level":"info","msg":"after"}`
+	entries, errs := Parse(strings.NewReader(input))
+	var n int
+	for range entries {
+		n++
+	}
+	err := <-errs
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("err = %v, want a line 3 error", err)
+	}
+	if n != 1 {
+		t.Errorf("got %d entries before the error, want 1", n)
+	}
+}
+
+func TestWriteTo(t *testing.T) {
+	input := `{"level":"info","time":"2024-05-01 12:00:00.000","msg":"hello","user":"alice","n":1}`
+	entries, errs := Parse(strings.NewReader(input))
+	var buf bytes.Buffer
+	if err := WriteTo(entries, &buf, FormatConsole); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	if want := "2024-05-01 12:00:00.000\tINFO\thello\t{\"n\": 1, \"user\": \"alice\"}\n"; buf.String() != want {
+		t.Errorf("console = %q, want %q", buf.String(), want)
+	}
+
+	// JSON 输出可以再次解析为相同的日志
+	entries, _ = Parse(strings.NewReader(input))
+	buf.Reset()
+	if err := WriteTo(entries, &buf, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	entries, errs = Parse(&buf)
+	got := collect(t, entries, errs)
+	if len(got) != 1 || got[0].Message != "hello" || got[0].Fields["user"] != "alice" || got[0].Time.IsZero() {
+		t.Errorf("round trip = %+v", got)
+	}
+
+	entries, _ = Parse(strings.NewReader(input))
+	if err := WriteTo(entries, &buf, "xml"); err == nil {
+		t.Error("WriteTo accepted an unknown format")
+	}
+	for range entries {
+	}
+}
@@ -0,0 +1,22 @@
+package metrics
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Register 向 registry 注册 collector，已存在同名且类型一致的指标时返回已注册的实例，
+// 这样多次构造同一组件可以共用一个 registry；同名但类型或标签不一致时返回注册错误
+func Register[C prometheus.Collector](registry prometheus.Registerer, c C) (C, error) {
+	if err := registry.Register(c); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if errors.As(err, &already) {
+			if existing, ok := already.ExistingCollector.(C); ok {
+				return existing, nil
+			}
+		}
+		return c, err
+	}
+	return c, nil
+}
@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestRegister(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	opts := prometheus.CounterOpts{Name: "jobs_total", Help: "Total number of jobs."}
+
+	first, err := Register(registry, prometheus.NewCounter(opts))
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	// 同名同类型的指标复用已注册的实例
+	second, err := Register(registry, prometheus.NewCounter(opts))
+	if err != nil || second != first {
+		t.Fatalf("second Register = %v, %v, want the first collector", second, err)
+	}
+
+	// 同名但标签不一致时返回错误
+	if _, err := Register(registry, prometheus.NewCounterVec(opts, []string{"type"})); err == nil {
+		t.Error("conflicting registration returned nil error")
+	}
+}
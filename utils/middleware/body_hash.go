@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/kobyt2/common-services/utils"
+)
+
+// DigestHeader RFC 3230 定义的请求体摘要头
+const DigestHeader = "Digest"
+
+// defaultMaxBodySize BodyHashMiddleware 默认允许缓冲的最大请求体，10 MB
+const defaultMaxBodySize = 10 << 20
+
+// BodyHashOption BodyHashMiddleware 的可选配置
+type BodyHashOption func(*bodyHashConfig)
+
+type bodyHashConfig struct {
+	required    bool
+	maxBodySize int64
+}
+
+// WithRequired 要求请求必须携带 Digest 头，缺失时返回 400
+func WithRequired() BodyHashOption {
+	return func(c *bodyHashConfig) {
+		c.required = true
+	}
+}
+
+// WithMaxBodySize 设置校验摘要时允许缓冲的最大请求体字节数，超出时返回 413，默认 10 MB
+func WithMaxBodySize(n int64) BodyHashOption {
+	return func(c *bodyHashConfig) {
+		c.maxBodySize = n
+	}
+}
+
+// BodyHashMiddleware 校验请求头 Digest（如 "SHA-256=<base64>"）与请求体摘要是否一致
+// algorithm 支持 "SHA-256" 和 "SHA-512"，其他算法在创建时 panic；摘要不一致时返回 400，
+// 请求体超过 WithMaxBodySize 时返回 413，未携带 Digest 头时默认直接放行。
+// algorithm 来自配置等运行时输入时使用 NewBodyHash
+func BodyHashMiddleware(algorithm string, opts ...BodyHashOption) func(http.Handler) http.Handler {
+	mw, err := NewBodyHash(algorithm, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return mw
+}
+
+// NewBodyHash 与 BodyHashMiddleware 相同，但不支持的算法返回 error 而不是 panic
+func NewBodyHash(algorithm string, opts ...BodyHashOption) (func(http.Handler) http.Handler, error) {
+	newHash, ok := digestAlgorithm(algorithm)
+	if !ok {
+		return nil, fmt.Errorf("middleware: unsupported digest algorithm %q", algorithm)
+	}
+	cfg := bodyHashConfig{maxBodySize: defaultMaxBodySize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			expected, found := parseDigest(r.Header.Get(DigestHeader), algorithm)
+			if !found {
+				if cfg.required {
+					http.Error(w, "missing "+algorithm+" digest", http.StatusBadRequest)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = http.MaxBytesReader(w, r.Body, cfg.maxBodySize)
+			}
+			body, err := readAndRestoreBody(r)
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			if err != nil {
+				http.Error(w, "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			h := newHash()
+			h.Write(body)
+			actual := base64.StdEncoding.EncodeToString(h.Sum(nil))
+			if !utils.ConstantTimeEqual(actual, expected) {
+				http.Error(w, "digest mismatch", http.StatusBadRequest)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// SetBodyHash 供客户端使用，计算请求体的 SHA-256 摘要并写入 Digest 头
+func SetBodyHash(req *http.Request) error {
+	body, err := readAndRestoreBody(req)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(body)
+	req.Header.Set(DigestHeader, "SHA-256="+base64.StdEncoding.EncodeToString(sum[:]))
+	return nil
+}
+
+// digestAlgorithm 返回算法名对应的 hash 构造函数
+func digestAlgorithm(algorithm string) (func() hash.Hash, bool) {
+	switch strings.ToUpper(algorithm) {
+	case "SHA-256":
+		return sha256.New, true
+	case "SHA-512":
+		return sha512.New, true
+	default:
+		return nil, false
+	}
+}
+
+// parseDigest 从 Digest 头中取出指定算法的摘要值，头中可能包含以逗号分隔的多个摘要
+func parseDigest(header, algorithm string) (string, bool) {
+	for _, part := range strings.Split(header, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if ok && strings.EqualFold(name, algorithm) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// readAndRestoreBody 读取完整请求体后重新放回，保证后续处理仍可读取
+func readAndRestoreBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return body, nil
+}
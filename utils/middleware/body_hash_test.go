@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func echoBodyHandler(t *testing.T) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("handler read body: %v", err)
+		}
+		w.Write(body)
+	})
+}
+
+func TestBodyHashMiddleware(t *testing.T) {
+	signed := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		if err := SetBodyHash(req); err != nil {
+			t.Fatalf("SetBodyHash: %v", err)
+		}
+		return req
+	}
+	tampered := signed(`{"amount":100}`)
+	tampered.Body = io.NopCloser(strings.NewReader(`{"amount":999}`))
+
+	tests := []struct {
+		name     string
+		opts     []BodyHashOption
+		req      *http.Request
+		wantCode int
+		wantBody string
+	}{
+		{"correct hash", nil, signed(`{"amount":100}`), http.StatusOK, `{"amount":100}`},
+		{"tampered body", nil, tampered, http.StatusBadRequest, ""},
+		{"missing header", nil, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("x")), http.StatusOK, "x"},
+		{"missing header required", []BodyHashOption{WithRequired()}, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("x")), http.StatusBadRequest, ""},
+		{"body too large", []BodyHashOption{WithMaxBodySize(4)}, signed("12345"), http.StatusRequestEntityTooLarge, ""},
+		{"body at limit", []BodyHashOption{WithMaxBodySize(5)}, signed("12345"), http.StatusOK, "12345"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mw := BodyHashMiddleware("SHA-256", tt.opts...)
+			rec := httptest.NewRecorder()
+			mw(echoBodyHandler(t)).ServeHTTP(rec, tt.req)
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusOK && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestBodyHashMiddlewareUnsupportedAlgorithm(t *testing.T) {
+	if mw, err := NewBodyHash("MD5"); err == nil || mw != nil {
+		t.Fatalf("NewBodyHash(MD5) error = %v, middleware nil = %v; want an error and nil", err, mw == nil)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("BodyHashMiddleware(MD5) did not panic")
+		}
+	}()
+	BodyHashMiddleware("MD5")
+}
+
+func TestNewBodyHash(t *testing.T) {
+	for _, algorithm := range []string{"SHA-256", "sha-512"} {
+		mw, err := NewBodyHash(algorithm, WithRequired())
+		if err != nil {
+			t.Fatalf("NewBodyHash(%s): %v", algorithm, err)
+		}
+		rec := httptest.NewRecorder()
+		mw(echoBodyHandler(t)).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("x")))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s without digest: status = %d, want 400", algorithm, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/kobyt2/common-services/logger"
+	"github.com/kobyt2/common-services/utils/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// CancelTracker 在处理函数返回后检查请求 context：客户端提前断开（context.Canceled）计入
+// http_requests_cancelled_total{method,path}，服务端超时（context.DeadlineExceeded）计入
+// http_requests_timeout_total{method,path}，两种情况都以 Debug 级别写入 logger.L()。
+// 与 MetricsMiddleware 一样可以用 WithMaxCardinality 限制 path 标签的取值数量；
+// 指标同名但类型或标签不一致时返回注册错误
+func CancelTracker(registry prometheus.Registerer, opts ...MetricsOption) (func(http.Handler) http.Handler, error) {
+	var cfg metricsConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cancelled, err := metrics.Register(registry, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_cancelled_total",
+		Help: "Total number of HTTP requests cancelled because the client disconnected.",
+	}, []string{"method", "path"}))
+	if err != nil {
+		return nil, err
+	}
+	timedOut, err := metrics.Register(registry, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_timeout_total",
+		Help: "Total number of HTTP requests whose context deadline was exceeded.",
+	}, []string{"method", "path"}))
+	if err != nil {
+		return nil, err
+	}
+	limiter, err := newCardinalityLimiter(registry, cfg.maxCardinality)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+
+			var (
+				reason  string
+				counter *prometheus.CounterVec
+			)
+			switch err := r.Context().Err(); {
+			case errors.Is(err, context.Canceled):
+				reason, counter = "client disconnected", cancelled
+			case errors.Is(err, context.DeadlineExceeded):
+				reason, counter = "deadline exceeded", timedOut
+			default:
+				return
+			}
+			path := r.URL.Path
+			if limiter != nil {
+				path = limiter.label(path)
+			}
+			counter.WithLabelValues(r.Method, path).Inc()
+			logger.L().Debug("request cancelled",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("reason", reason),
+			)
+		})
+	}, nil
+}
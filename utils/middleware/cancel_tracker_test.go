@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kobyt2/common-services/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// counterValue 返回 registry 中名为 name 的计数器所有序列之和
+func counterValue(t *testing.T, registry *prometheus.Registry, name string) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var total float64
+	for _, mf := range families {
+		if mf.GetName() != name {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			total += m.GetCounter().GetValue()
+		}
+	}
+	return total
+}
+
+func TestCancelTracker(t *testing.T) {
+	logs := logger.ObserveGlobal(t, zapcore.DebugLevel)
+
+	registry := prometheus.NewRegistry()
+	track, err := CancelTracker(registry)
+	if err != nil {
+		t.Fatalf("CancelTracker: %v", err)
+	}
+
+	// 客户端在处理函数写出响应前断开
+	ctx, cancel := context.WithCancel(context.Background())
+	h := track(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+		w.WriteHeader(http.StatusOK)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil).WithContext(ctx))
+
+	if got := counterValue(t, registry, "http_requests_cancelled_total"); got != 1 {
+		t.Errorf("http_requests_cancelled_total = %v, want 1", got)
+	}
+	if got := counterValue(t, registry, "http_requests_timeout_total"); got != 0 {
+		t.Errorf("http_requests_timeout_total = %v, want 0", got)
+	}
+	entries := logs.FilterMessage("request cancelled").All()
+	if len(entries) != 1 || entries[0].Level != zapcore.DebugLevel || entries[0].ContextMap()["reason"] != "client disconnected" {
+		t.Fatalf("log entries = %v", logs.All())
+	}
+	if entries[0].ContextMap()["path"] != "/slow" {
+		t.Errorf("fields = %v", entries[0].ContextMap())
+	}
+
+	// 服务端超时
+	ctx, cancelTimeout := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancelTimeout()
+	h = track(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil).WithContext(ctx))
+	if got := counterValue(t, registry, "http_requests_timeout_total"); got != 1 {
+		t.Errorf("http_requests_timeout_total = %v, want 1", got)
+	}
+	if entries := logs.FilterField(zap.String("reason", "deadline exceeded")).All(); len(entries) != 1 {
+		t.Errorf("no deadline exceeded entry in %v", logs.All())
+	}
+
+	// 正常完成的请求不计数
+	track(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if logs.Len() != 2 || counterValue(t, registry, "http_requests_cancelled_total") != 1 {
+		t.Errorf("completed request was counted as cancelled")
+	}
+}
+
+func TestCancelTrackerMaxCardinality(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	track, err := CancelTracker(registry, WithMaxCardinality(2))
+	if err != nil {
+		t.Fatalf("CancelTracker: %v", err)
+	}
+	h := track(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	// 每个请求路径都带用户 ID，超过上限后统一记为 OverflowLabel
+	for i := 0; i < 5; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, fmt.Sprintf("/users/%d", i), nil).WithContext(ctx))
+	}
+
+	if n := testutil.CollectAndCount(registry, "http_requests_cancelled_total"); n != 3 {
+		t.Errorf("http_requests_cancelled_total has %d series, want 2 paths plus the overflow label", n)
+	}
+	if got := counterValue(t, registry, "http_metric_overflow_total"); got != 3 {
+		t.Errorf("http_metric_overflow_total = %v, want 3", got)
+	}
+}
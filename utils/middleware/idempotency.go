@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/kobyt2/common-services/utils/cache"
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultIdempotencyHeader IdempotencyMiddleware 默认读取的请求头
+const DefaultIdempotencyHeader = "Idempotency-Key"
+
+// IdempotencyMiddleware 按请求头 keyHeader 中的幂等 key 去重：首次请求执行处理函数并缓存响应，
+// ttl 内相同 key 的请求直接重放缓存的状态码、响应头和响应体。相同 key 的并发请求通过
+// singleflight 合并，处理函数只执行一次。没有该请求头的请求直接放行；5xx 响应不缓存，便于客户端重试。
+// 缓存的响应同时记录首个请求的请求体 SHA-256，相同 key 但请求体不同的请求返回 422，不会重放其他请求的结果
+func IdempotencyMiddleware(store cache.Store, ttl time.Duration, keyHeader string) func(http.Handler) http.Handler {
+	if keyHeader == "" {
+		keyHeader = DefaultIdempotencyHeader
+	}
+	var group singleflight.Group
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idempotencyKey := r.Header.Get(keyHeader)
+			if idempotencyKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key := "idempotency " + r.Method + " " + r.URL.Path + " " + idempotencyKey
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			sum := sha256.Sum256(body)
+			bodyHash := hex.EncodeToString(sum[:])
+
+			result, _, _ := group.Do(key, func() (interface{}, error) {
+				if data, ok, err := store.Get(r.Context(), key); err == nil && ok {
+					if cached, err := unmarshalCachedResponse(data); err == nil {
+						return cached, nil
+					}
+				}
+				buffered := newBufferedResponse()
+				next.ServeHTTP(buffered, r)
+				resp := buffered.snapshot()
+				resp.BodyHash = bodyHash
+				if resp.Status < 500 {
+					if data, err := resp.marshal(); err == nil {
+						_ = store.Set(r.Context(), key, data, ttl)
+					}
+				}
+				return resp, nil
+			})
+			// 缓存命中或并发合并时，结果可能来自请求体不同的另一个请求
+			resp := result.(cachedResponse)
+			if resp.BodyHash != bodyHash {
+				http.Error(w, "idempotency key reused with a different request body", http.StatusUnprocessableEntity)
+				return
+			}
+			resp.writeTo(w)
+		})
+	}
+}
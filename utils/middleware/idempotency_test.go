@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kobyt2/common-services/utils/cache"
+)
+
+// paymentHandler 记录调用次数，返回 201 和带调用序号的响应体
+func paymentHandler(calls *int32, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Payment", "p-"+string(rune('0'+n)))
+		w.WriteHeader(status)
+		w.Write(append([]byte("charged "), body...))
+	})
+}
+
+func postWithKey(h http.Handler, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(DefaultIdempotencyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	var calls int32
+	h := IdempotencyMiddleware(cache.NewMemoryStore(), time.Minute, "")(paymentHandler(&calls, http.StatusCreated))
+
+	first := postWithKey(h, "k-1", `{"amount":100}`)
+	second := postWithKey(h, "k-1", `{"amount":100}`)
+
+	if calls != 1 {
+		t.Fatalf("handler called %d times for the same key, want 1", calls)
+	}
+	for _, rec := range []*httptest.ResponseRecorder{first, second} {
+		if rec.Code != http.StatusCreated || rec.Body.String() != `charged {"amount":100}` || rec.Header().Get("X-Payment") != "p-1" {
+			t.Errorf("response = %d %q %v, want the first response", rec.Code, rec.Body.String(), rec.Header())
+		}
+	}
+
+	postWithKey(h, "k-2", `{"amount":100}`)
+	postWithKey(h, "", `{"amount":100}`)
+	postWithKey(h, "", `{"amount":100}`)
+	if calls != 4 {
+		t.Errorf("handler called %d times, want new keys and requests without a key to run", calls)
+	}
+}
+
+func TestIdempotencyConcurrentSameKey(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		w.Write([]byte("done"))
+	})
+	h := IdempotencyMiddleware(cache.NewMemoryStore(), time.Minute, "X-Key")(slow)
+
+	const requests = 10
+	codes := make([]int, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader("same"))
+			req.Header.Set("X-Key", "k-1")
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			codes[i] = rec.Code
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("handler called %d times for concurrent requests with the same key, want 1", n)
+	}
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("request %d status = %d, want 200", i, code)
+		}
+	}
+}
+
+func TestIdempotencyBodyMismatch(t *testing.T) {
+	var calls int32
+	h := IdempotencyMiddleware(cache.NewMemoryStore(), time.Minute, "")(paymentHandler(&calls, http.StatusCreated))
+
+	postWithKey(h, "k-1", `{"amount":100}`)
+	rec := postWithKey(h, "k-1", `{"amount":999}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want 422 for a reused key with another body", rec.Code)
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+}
+
+func TestIdempotencyDoesNotCache5xx(t *testing.T) {
+	var calls int32
+	h := IdempotencyMiddleware(cache.NewMemoryStore(), time.Minute, "")(paymentHandler(&calls, http.StatusBadGateway))
+
+	postWithKey(h, "k-1", "x")
+	if rec := postWithKey(h, "k-1", "x"); rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want 502", rec.Code)
+	}
+	if calls != 2 {
+		t.Errorf("handler called %d times, want a retry after a 5xx to run again", calls)
+	}
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kobyt2/common-services/utils/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// OverflowLabel 超出基数上限后使用的 path 标签值
+const OverflowLabel = "__overflow__"
+
+// MetricsOption MetricsMiddleware 的可选配置
+type MetricsOption func(*metricsConfig)
+
+type metricsConfig struct {
+	maxCardinality int
+}
+
+// WithMaxCardinality 限制 path 标签最多出现 n 个不同的值，之后的新值统一记为 "__overflow__"，
+// 避免 URL 中的用户 ID 等导致指标数量失控
+func WithMaxCardinality(n int) MetricsOption {
+	return func(c *metricsConfig) {
+		c.maxCardinality = n
+	}
+}
+
+// MetricsMiddleware 记录 http_requests_total{method,path,status} 与 http_request_duration_seconds{method,path}。
+// 指标已注册时复用已有的 collector，同名但类型或标签不一致时返回注册错误
+func MetricsMiddleware(registry prometheus.Registerer, opts ...MetricsOption) (func(http.Handler) http.Handler, error) {
+	var cfg metricsConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	requests, err := metrics.Register(registry, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests.",
+	}, []string{"method", "path", "status"}))
+	if err != nil {
+		return nil, err
+	}
+	duration, err := metrics.Register(registry, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "http_request_duration_seconds",
+		Help: "Duration of HTTP requests in seconds.",
+	}, []string{"method", "path"}))
+	if err != nil {
+		return nil, err
+	}
+	limiter, err := newCardinalityLimiter(registry, cfg.maxCardinality)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(sw, r)
+
+			path := r.URL.Path
+			if limiter != nil {
+				path = limiter.label(path)
+			}
+			requests.WithLabelValues(r.Method, path, strconv.Itoa(sw.status)).Inc()
+			duration.WithLabelValues(r.Method, path).Observe(time.Since(start).Seconds())
+		})
+	}, nil
+}
+
+// newCardinalityLimiter 创建上限为 max 的 cardinalityLimiter 并注册 http_metric_overflow_total，max <= 0 时返回 nil
+func newCardinalityLimiter(registry prometheus.Registerer, max int) (*cardinalityLimiter, error) {
+	if max <= 0 {
+		return nil, nil
+	}
+	overflow, err := metrics.Register(registry, prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "http_metric_overflow_total",
+		Help: "Total number of requests whose path label was replaced because of the cardinality limit.",
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return &cardinalityLimiter{max: int64(max), overflow: overflow}, nil
+}
+
+// cardinalityLimiter 记录见过的标签值，数量达到上限后新值统一替换为 OverflowLabel。
+// 计数与 sync.Map 分开维护，并发插入时可能略微超过上限，以此避免全局锁
+type cardinalityLimiter struct {
+	seen     sync.Map
+	count    atomic.Int64
+	max      int64
+	overflow prometheus.Counter
+}
+
+// label 返回 value 本身，超出上限时返回 OverflowLabel
+func (l *cardinalityLimiter) label(value string) string {
+	if _, ok := l.seen.Load(value); ok {
+		return value
+	}
+	if l.count.Load() >= l.max {
+		l.overflow.Inc()
+		return OverflowLabel
+	}
+	if _, loaded := l.seen.LoadOrStore(value, struct{}{}); !loaded {
+		l.count.Add(1)
+	}
+	return value
+}
+
+// statusWriter 记录处理函数写出的状态码
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap 供 http.ResponseController 访问底层 ResponseWriter
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// newMetricsMiddleware 创建 MetricsMiddleware，注册失败时测试失败
+func newMetricsMiddleware(t *testing.T, registry prometheus.Registerer, opts ...MetricsOption) func(http.Handler) http.Handler {
+	t.Helper()
+	m, err := MetricsMiddleware(registry, opts...)
+	if err != nil {
+		t.Fatalf("MetricsMiddleware: %v", err)
+	}
+	return m
+}
+
+func TestMetricsMiddlewareMaxCardinality(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	h := newMetricsMiddleware(t, registry, WithMaxCardinality(3))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	get := func(path string) {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	for _, path := range []string{"/users/1", "/users/2", "/missing", "/users/1", "/users/4", "/users/5"} {
+		get(path)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]float64{}
+	var overflow float64
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			switch mf.GetName() {
+			case "http_metric_overflow_total":
+				overflow = m.GetCounter().GetValue()
+			case "http_requests_total":
+				for _, lp := range m.GetLabel() {
+					if lp.GetName() == "path" {
+						counts[lp.GetValue()] += m.GetCounter().GetValue()
+					}
+				}
+			}
+		}
+	}
+	// 第 4 个不同的 path 起统一记为 __overflow__
+	want := map[string]float64{"/users/1": 2, "/users/2": 1, "/missing": 1, OverflowLabel: 2}
+	if fmt.Sprint(counts) != fmt.Sprint(want) {
+		t.Errorf("requests by path = %v, want %v", counts, want)
+	}
+	if overflow != 2 {
+		t.Errorf("http_metric_overflow_total = %v, want 2", overflow)
+	}
+	if n := testutil.CollectAndCount(registry, "http_requests_total"); n != 4 {
+		t.Errorf("http_requests_total has %d series, want 4", n)
+	}
+}
+
+func TestCardinalityLimiterConcurrent(t *testing.T) {
+	l := &cardinalityLimiter{max: 10, overflow: prometheus.NewCounter(prometheus.CounterOpts{Name: "overflow"})}
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l.label(fmt.Sprintf("/p/%d", i))
+		}(i)
+	}
+	wg.Wait()
+
+	// 允许并发插入略微超过上限，但不会无限增长
+	if n := l.count.Load(); n < 10 {
+		t.Fatalf("count = %d", n)
+	}
+	if got := testutil.ToFloat64(l.overflow) + float64(l.count.Load()); got != 100 {
+		t.Errorf("accepted + overflowed = %v, want 100", got)
+	}
+}
+
+func TestMetricsMiddlewareSharedRegistry(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	first := newMetricsMiddleware(t, registry)(http.NotFoundHandler())
+	// 同一 registry 再次创建时复用已注册的指标
+	second := newMetricsMiddleware(t, registry)(http.NotFoundHandler())
+	first.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/a", nil))
+	second.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/a", nil))
+
+	if n := testutil.CollectAndCount(registry, "http_requests_total"); n != 1 {
+		t.Errorf("http_requests_total has %d series, want 1", n)
+	}
+}
+
+func TestMetricsMiddlewareRegistrationConflict(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	// 同名但标签不同的指标已存在
+	registry.MustRegister(prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Conflicting collector.",
+	}, []string{"code"}))
+	if _, err := MetricsMiddleware(registry); err == nil {
+		t.Error("MetricsMiddleware returned nil error for a conflicting collector")
+	}
+
+	registry = prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "http_requests_cancelled_total",
+		Help: "Conflicting collector.",
+	}))
+	if _, err := CancelTracker(registry); err == nil {
+		t.Error("CancelTracker returned nil error for a conflicting collector")
+	}
+}
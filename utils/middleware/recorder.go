@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+// bufferedResponse 完整缓存处理函数写出的响应，之后再统一写给客户端或存入缓存
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: make(http.Header)}
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// statusCode 返回响应状态码，未显式写出时为 200
+func (b *bufferedResponse) statusCode() int {
+	if b.status == 0 {
+		return http.StatusOK
+	}
+	return b.status
+}
+
+// snapshot 转换为可序列化的缓存响应
+func (b *bufferedResponse) snapshot() cachedResponse {
+	return cachedResponse{Status: b.statusCode(), Header: b.header.Clone(), Body: b.body.Bytes()}
+}
+
+// cachedResponse 缓存中保存的响应；BodyHash 为幂等中间件记录的请求体摘要
+type cachedResponse struct {
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+	BodyHash string      `json:"body_hash,omitempty"`
+}
+
+// writeTo 将响应写给客户端
+func (c cachedResponse) writeTo(w http.ResponseWriter) {
+	for k, values := range c.Header {
+		w.Header()[k] = append([]string(nil), values...)
+	}
+	w.WriteHeader(c.Status)
+	w.Write(c.Body)
+}
+
+func (c cachedResponse) marshal() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func unmarshalCachedResponse(data []byte) (cachedResponse, error) {
+	var c cachedResponse
+	err := json.Unmarshal(data, &c)
+	return c, err
+}
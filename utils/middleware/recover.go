@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/google/uuid"
+	"github.com/kobyt2/common-services/logger"
+	"go.uber.org/zap"
+)
+
+// recoverResponse Recover 返回的错误响应
+type recoverResponse struct {
+	Code          string `json:"code"`
+	CorrelationID string `json:"correlation_id"`
+	Message       string `json:"message"`
+}
+
+// Recover 捕获处理函数中的 panic，以 Error 级别写入 logger.L()（包含堆栈），并返回 500 与 JSON 错误体。
+// 日志和响应体中的 correlation_id 相同：优先使用 context 中的请求 ID，其次为关联 ID，都没有时生成 UUID v4。
+// http.ErrAbortHandler 会被重新抛出，由 net/http 中断连接；响应头已写出时只记录日志
+func Recover() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+				id := correlationID(r)
+				logger.L().Error("panic recovered",
+					zap.String("correlation_id", id),
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.String("panic", fmt.Sprint(p)),
+					zap.ByteString("stack", debug.Stack()),
+				)
+				if sw.wroteHeader {
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(recoverResponse{
+					Code:          "INTERNAL_ERROR",
+					CorrelationID: id,
+					Message:       "An unexpected error occurred",
+				})
+			}()
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
+
+// correlationID 返回请求的关联 ID，context 中没有时生成新的 UUID
+func correlationID(r *http.Request) string {
+	if id, ok := logger.GetRequestID(r.Context()); ok && id != "" {
+		return id
+	}
+	if id, ok := logger.GetCorrelationID(r.Context()); ok && id != "" {
+		return id
+	}
+	return uuid.NewString()
+}
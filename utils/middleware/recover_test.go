@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kobyt2/common-services/logger"
+	"go.uber.org/zap/zapcore"
+)
+
+func panicHandler(w http.ResponseWriter, r *http.Request) {
+	panic("boom")
+}
+
+func TestRecoverCorrelationID(t *testing.T) {
+	tests := []struct {
+		name string
+		req  func() *http.Request
+		want string
+	}{
+		{"generated", func() *http.Request { return httptest.NewRequest(http.MethodGet, "/orders", nil) }, ""},
+		{"request id", func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/orders", nil)
+			return r.WithContext(logger.SetRequestID(r.Context(), "req-1"))
+		}, "req-1"},
+		{"correlation id", func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/orders", nil)
+			return r.WithContext(logger.SetCorrelationID(r.Context(), "corr-1"))
+		}, "corr-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := logger.ObserveGlobal(t, zapcore.DebugLevel)
+
+			rec := httptest.NewRecorder()
+			Recover()(http.HandlerFunc(panicHandler)).ServeHTTP(rec, tt.req())
+
+			if rec.Code != http.StatusInternalServerError || rec.Header().Get("Content-Type") != "application/json" {
+				t.Fatalf("response = %d %q", rec.Code, rec.Header().Get("Content-Type"))
+			}
+			var body recoverResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode body %q: %v", rec.Body.String(), err)
+			}
+			if body.Code != "INTERNAL_ERROR" || body.Message != "An unexpected error occurred" {
+				t.Errorf("body = %+v", body)
+			}
+			if tt.want != "" && body.CorrelationID != tt.want {
+				t.Errorf("correlation_id = %q, want %q", body.CorrelationID, tt.want)
+			}
+			if tt.want == "" {
+				if id, err := uuid.Parse(body.CorrelationID); err != nil || id.Version() != 4 {
+					t.Errorf("correlation_id %q is not a UUID v4", body.CorrelationID)
+				}
+			}
+
+			// 日志中的 correlation_id 与响应体一致
+			entries := logs.FilterMessage("panic recovered").All()
+			if len(entries) != 1 {
+				t.Fatalf("log entries = %v", logs.All())
+			}
+			fields := entries[0].ContextMap()
+			if fields["correlation_id"] != body.CorrelationID || fields["panic"] != "boom" || fields["stack"] == "" {
+				t.Errorf("log fields = %v, body correlation_id = %q", fields, body.CorrelationID)
+			}
+			if file := filepath.Base(entries[0].Caller.File); file != "recover.go" {
+				t.Errorf("caller = %s, want recover.go", entries[0].Caller.String())
+			}
+		})
+	}
+}
+
+func TestRecoverAfterHeaderWritten(t *testing.T) {
+	logs := logger.ObserveGlobal(t, zapcore.DebugLevel)
+
+	rec := httptest.NewRecorder()
+	Recover()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		panic("late")
+	})).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// 响应头已写出时只记录日志，不再追加错误体
+	if rec.Code != http.StatusAccepted || rec.Body.Len() != 0 {
+		t.Errorf("response = %d %q", rec.Code, rec.Body.String())
+	}
+	if logs.FilterMessage("panic recovered").Len() != 1 {
+		t.Errorf("log entries = %v", logs.All())
+	}
+}
+
+func TestRecoverRepanicsAbortHandler(t *testing.T) {
+	defer func() {
+		if p := recover(); p != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler", p)
+		}
+	}()
+	Recover()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/kobyt2/common-services/utils/cache"
+)
+
+// ResponseCache 缓存 GET 请求的响应：命中时直接写回缓存的状态码、响应头和响应体，
+// 未命中时执行处理函数并缓存 2xx 响应。keyFn 为 nil 时使用 method + URL + Accept 作为缓存 key
+func ResponseCache(store cache.Store, keyFn func(*http.Request) string, ttl time.Duration) func(http.Handler) http.Handler {
+	if keyFn == nil {
+		keyFn = defaultCacheKey
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := keyFn(r)
+			if data, ok, err := store.Get(r.Context(), key); err == nil && ok {
+				if cached, err := unmarshalCachedResponse(data); err == nil {
+					cached.writeTo(w)
+					return
+				}
+			}
+
+			buffered := newBufferedResponse()
+			next.ServeHTTP(buffered, r)
+			resp := buffered.snapshot()
+			if resp.Status >= 200 && resp.Status < 300 {
+				if data, err := resp.marshal(); err == nil {
+					_ = store.Set(r.Context(), key, data, ttl)
+				}
+			}
+			resp.writeTo(w)
+		})
+	}
+}
+
+// defaultCacheKey 默认的缓存 key：method + URL + Accept
+func defaultCacheKey(r *http.Request) string {
+	return r.Method + " " + r.URL.String() + " " + r.Header.Get("Accept")
+}
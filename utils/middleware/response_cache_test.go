@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// bufferStore 以 bytes.Buffer 保存值的 cache.Store，忽略 ttl
+type bufferStore struct {
+	mu     sync.Mutex
+	values map[string]*bytes.Buffer
+}
+
+func newBufferStore() *bufferStore {
+	return &bufferStore{values: make(map[string]*bytes.Buffer)}
+}
+
+func (s *bufferStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	buf, ok := s.values[key]
+	if !ok {
+		return nil, false, nil
+	}
+	return bytes.Clone(buf.Bytes()), true, nil
+}
+
+func (s *bufferStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = bytes.NewBuffer(bytes.Clone(value))
+	return nil
+}
+
+func (s *bufferStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	return nil
+}
+
+// countingHandler 记录调用次数并按 status 写出响应
+func countingHandler(calls *int, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("X-Call", string(rune('0'+*calls)))
+		w.WriteHeader(status)
+		w.Write([]byte("hello " + r.URL.Path))
+	})
+}
+
+func serve(h http.Handler, method, target string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestResponseCacheHit(t *testing.T) {
+	var calls int
+	h := ResponseCache(newBufferStore(), nil, time.Minute)(countingHandler(&calls, http.StatusCreated))
+
+	first := serve(h, http.MethodGet, "/items?page=1", nil)
+	second := serve(h, http.MethodGet, "/items?page=1", nil)
+
+	if calls != 1 {
+		t.Fatalf("handler called %d times for two identical requests, want 1", calls)
+	}
+	for _, rec := range []*httptest.ResponseRecorder{first, second} {
+		if rec.Code != http.StatusCreated || rec.Body.String() != "hello /items" {
+			t.Errorf("response = %d %q, want 201 \"hello /items\"", rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("X-Call") != "1" || rec.Header().Get("Content-Type") != "text/plain" {
+			t.Errorf("headers = %v, want the headers of the first call", rec.Header())
+		}
+	}
+}
+
+func TestResponseCacheDefaultKey(t *testing.T) {
+	var calls int
+	h := ResponseCache(newBufferStore(), nil, time.Minute)(countingHandler(&calls, http.StatusOK))
+
+	serve(h, http.MethodGet, "/items?page=1", nil)
+	serve(h, http.MethodGet, "/items?page=2", nil)
+	serve(h, http.MethodGet, "/items?page=1", map[string]string{"Accept": "application/json"})
+	if calls != 3 {
+		t.Errorf("handler called %d times, want 3 (URL and Accept are part of the key)", calls)
+	}
+}
+
+func TestResponseCacheCustomKey(t *testing.T) {
+	var calls int
+	keyFn := func(r *http.Request) string { return r.URL.Path }
+	h := ResponseCache(newBufferStore(), keyFn, time.Minute)(countingHandler(&calls, http.StatusOK))
+
+	serve(h, http.MethodGet, "/items?page=1", nil)
+	serve(h, http.MethodGet, "/items?page=2", nil)
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1 with a path-only key", calls)
+	}
+}
+
+func TestResponseCacheSkipsNon2xxAndNonGet(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		status int
+	}{
+		{"not found", http.MethodGet, http.StatusNotFound},
+		{"server error", http.MethodGet, http.StatusInternalServerError},
+		{"post", http.MethodPost, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			store := newBufferStore()
+			h := ResponseCache(store, nil, time.Minute)(countingHandler(&calls, tt.status))
+
+			for i := 0; i < 2; i++ {
+				if rec := serve(h, tt.method, "/items", nil); rec.Code != tt.status {
+					t.Errorf("status = %d, want %d", rec.Code, tt.status)
+				}
+			}
+			if calls != 2 {
+				t.Errorf("handler called %d times, want 2 for uncached responses", calls)
+			}
+			if len(store.values) != 0 {
+				t.Errorf("store holds %d entries, want 0", len(store.values))
+			}
+		})
+	}
+}
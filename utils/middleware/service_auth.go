@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/kobyt2/common-services/utils/jwt"
+)
+
+// claimsKey 请求上下文中保存 JWT claims 的 key
+type claimsKey struct{}
+
+// ServiceAuthMiddleware 校验服务间调用的 Authorization: Bearer <token>，并要求 requiredClaims 中的每个
+// claim 与 token 中的值一致（aud 等数组类型的 claim 只需包含该值）。校验失败返回 401 和 JSON 错误信息，
+// 成功时将 claims 写入请求上下文，可通过 ClaimsFromContext 读取
+func ServiceAuthMiddleware(manager *jwt.Manager, requiredClaims map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				writeUnauthorized(w, "missing bearer token")
+				return
+			}
+			claims, err := manager.Verify(token)
+			if err != nil {
+				writeUnauthorized(w, "invalid token")
+				return
+			}
+			for key, expected := range requiredClaims {
+				if !claimMatches(claims[key], expected) {
+					writeUnauthorized(w, "claim "+key+" does not match")
+					return
+				}
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsKey{}, claims)))
+		})
+	}
+}
+
+// ClaimsFromContext 返回 ServiceAuthMiddleware 写入的 claims
+func ClaimsFromContext(ctx context.Context) (map[string]interface{}, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(map[string]interface{})
+	return claims, ok
+}
+
+// bearerToken 从 Authorization 请求头中取出 token
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// claimMatches 判断 claim 是否等于 expected，数组类型的 claim 包含 expected 即可
+func claimMatches(value interface{}, expected string) bool {
+	switch v := value.(type) {
+	case string:
+		return v == expected
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func writeUnauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnauthorized)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
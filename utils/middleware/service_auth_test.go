@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kobyt2/common-services/utils/jwt"
+)
+
+// signToken 使用 key 签发 claims，ttl 为负数时得到已过期的 token
+func signToken(t *testing.T, key *ecdsa.PrivateKey, ttl time.Duration, claims map[string]interface{}) string {
+	t.Helper()
+	manager, err := jwt.NewManager(key, jwt.WithTTL(ttl))
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := manager.Sign(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestServiceAuthMiddleware(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	verifier, err := jwt.NewVerifier(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := ServiceAuthMiddleware(verifier, map[string]string{"sub": "orders", "aud": "billing"})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := ClaimsFromContext(r.Context())
+			if !ok {
+				http.Error(w, "no claims", http.StatusInternalServerError)
+				return
+			}
+			io.WriteString(w, claims["sub"].(string))
+		}))
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	valid := map[string]interface{}{"sub": "orders", "aud": []string{"billing", "audit"}}
+	tests := []struct {
+		name   string
+		header string
+		status int
+		body   string
+	}{
+		{"valid", "Bearer " + signToken(t, key, time.Minute, valid), http.StatusOK, "orders"},
+		{"missing", "", http.StatusUnauthorized, "missing bearer token"},
+		{"not bearer", "Basic abc", http.StatusUnauthorized, "missing bearer token"},
+		{"expired", "Bearer " + signToken(t, key, -time.Minute, valid), http.StatusUnauthorized, "invalid token"},
+		{"wrong key", "Bearer " + signToken(t, otherKey, time.Minute, valid), http.StatusUnauthorized, "invalid token"},
+		{"wrong claim", "Bearer " + signToken(t, key, time.Minute, map[string]interface{}{"sub": "users", "aud": "billing"}),
+			http.StatusUnauthorized, "claim sub does not match"},
+		{"missing audience", "Bearer " + signToken(t, key, time.Minute, map[string]interface{}{"sub": "orders", "aud": []string{"audit"}}),
+			http.StatusUnauthorized, "claim aud does not match"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d (%s), want %d", resp.StatusCode, body, tt.status)
+			}
+			if tt.status == http.StatusOK {
+				if string(body) != tt.body {
+					t.Errorf("body = %q, want %q", body, tt.body)
+				}
+				return
+			}
+			// 401 返回 JSON 错误信息
+			var errBody map[string]string
+			if err := json.Unmarshal(body, &errBody); err != nil || errBody["error"] != tt.body {
+				t.Errorf("body = %q, want error %q", body, tt.body)
+			}
+			if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+				t.Errorf("Content-Type = %q", ct)
+			}
+		})
+	}
+}
+
+func TestClaimsFromContextWithoutMiddleware(t *testing.T) {
+	if _, ok := ClaimsFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context()); ok {
+		t.Error("ClaimsFromContext reported claims on a plain request")
+	}
+}
@@ -0,0 +1,151 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Status 消息状态
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusDelivered Status = "delivered"
+)
+
+// Message outbox_messages 表中的一条待投递消息
+type Message struct {
+	ID          string `gorm:"primaryKey;size:36"`
+	Topic       string `gorm:"size:255;not null"`
+	Payload     []byte
+	Status      Status `gorm:"size:16;not null;index:idx_outbox_relay,priority:1"`
+	Attempts    int
+	LastError   string
+	CreatedAt   time.Time `gorm:"index:idx_outbox_relay,priority:2"`
+	DeliveredAt *time.Time
+}
+
+// TableName 指定 outbox 表名
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+// MessagePublisher 将消息投递到 Kafka、Redis Pub/Sub、HTTP 等外部系统
+type MessagePublisher interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+}
+
+// PublisherFunc 将普通函数适配为 MessagePublisher
+type PublisherFunc func(ctx context.Context, topic string, payload []byte) error
+
+// Publish 调用 f
+func (f PublisherFunc) Publish(ctx context.Context, topic string, payload []byte) error {
+	return f(ctx, topic, payload)
+}
+
+// Outbox 基于数据库表的事务性 outbox：消息与业务数据在同一事务中写入，
+// 由 Relay 异步投递，保证至少投递一次
+type Outbox struct {
+	db *gorm.DB
+}
+
+// New 创建 Outbox 并自动建表
+func New(db *gorm.DB) (*Outbox, error) {
+	if err := db.AutoMigrate(&Message{}); err != nil {
+		return nil, err
+	}
+	return &Outbox{db: db}, nil
+}
+
+// Publish 在调用方的事务 tx 中写入一条 pending 消息，payload 以 JSON 保存；
+// tx 为 nil 时直接写入，不与其他操作共享事务
+func (o *Outbox) Publish(ctx context.Context, tx *gorm.DB, topic string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	if tx == nil {
+		tx = o.db
+	}
+	return tx.WithContext(ctx).Create(&Message{
+		ID:      uuid.NewString(),
+		Topic:   topic,
+		Payload: data,
+		Status:  StatusPending,
+	}).Error
+}
+
+// Relay 按写入顺序取出最多 batchSize 条 pending 消息并逐条投递，返回投递成功的数量。
+// 投递失败时记录错误并停止本批次，以免打乱后续消息的顺序，失败的消息在下次调用时重试。
+// 支持的数据库使用 SELECT ... FOR UPDATE SKIP LOCKED，多个实例可以同时运行 Relay；
+// 其余数据库（如 SQLite）应只运行一个 Relay，否则消息可能被重复投递。
+//
+// 整个批次的投递状态在同一事务中、全部投递完成后才提交：如果提交失败或进程在此之前退出，
+// 本批次中已经投递的消息仍是 pending，会在下次调用时再次投递。消费方需要按消息 ID 去重，
+// batchSize 越大，可能重复的消息越多
+func (o *Outbox) Relay(ctx context.Context, publisher MessagePublisher, batchSize int) (int, error) {
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	delivered := 0
+	var publishErr error
+	err := o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("status = ?", StatusPending)
+		if supportsSkipLocked(tx) {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+		var messages []Message
+		if err := query.Order("created_at ASC").Limit(batchSize).Find(&messages).Error; err != nil {
+			return err
+		}
+
+		for _, msg := range messages {
+			if err := publisher.Publish(ctx, msg.Topic, msg.Payload); err != nil {
+				publishErr = err
+				return tx.Model(&Message{}).Where("id = ?", msg.ID).Updates(map[string]interface{}{
+					"attempts":   gorm.Expr("attempts + 1"),
+					"last_error": err.Error(),
+				}).Error
+			}
+			now := time.Now()
+			err := tx.Model(&Message{}).Where("id = ?", msg.ID).Updates(map[string]interface{}{
+				"status":       StatusDelivered,
+				"attempts":     gorm.Expr("attempts + 1"),
+				"delivered_at": now,
+			}).Error
+			if err != nil {
+				return err
+			}
+			delivered++
+		}
+		return nil
+	})
+	if err != nil {
+		// 事务回滚，本批次的投递状态都没有保存，已投递的消息会被再次投递
+		return 0, err
+	}
+	return delivered, publishErr
+}
+
+// CleanupDelivered 删除投递时间早于 before 的已投递消息，返回删除的条数
+func (o *Outbox) CleanupDelivered(ctx context.Context, before time.Time) (int64, error) {
+	result := o.db.WithContext(ctx).
+		Where("status = ? AND delivered_at < ?", StatusDelivered, before).
+		Delete(&Message{})
+	return result.RowsAffected, result.Error
+}
+
+// supportsSkipLocked 判断数据库是否支持 SKIP LOCKED
+func supportsSkipLocked(db *gorm.DB) bool {
+	switch db.Dialector.Name() {
+	case "postgres", "mysql":
+		return true
+	default:
+		return false
+	}
+}
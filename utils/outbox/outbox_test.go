@@ -0,0 +1,161 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestOutbox(t *testing.T) (*Outbox, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 内存库每个连接都是独立的数据库，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	o, err := New(db)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return o, db
+}
+
+// mockPublisher 记录投递的消息，fail 返回非 nil 时投递失败
+type mockPublisher struct {
+	published []string
+	fail      func(payload string) error
+}
+
+func (p *mockPublisher) Publish(ctx context.Context, topic string, payload []byte) error {
+	if p.fail != nil {
+		if err := p.fail(string(payload)); err != nil {
+			return err
+		}
+	}
+	p.published = append(p.published, topic+":"+string(payload))
+	return nil
+}
+
+// publishAll 依次写入 payloads，保证 created_at 不同以固定投递顺序
+func publishAll(t *testing.T, o *Outbox, payloads ...string) {
+	t.Helper()
+	for _, payload := range payloads {
+		if err := o.Publish(context.Background(), nil, "orders", payload); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPublishUsesCallerTransaction(t *testing.T) {
+	o, db := newTestOutbox(t)
+	ctx := context.Background()
+
+	db.Transaction(func(tx *gorm.DB) error {
+		o.Publish(ctx, tx, "orders", "committed")
+		return nil
+	})
+	db.Transaction(func(tx *gorm.DB) error {
+		o.Publish(ctx, tx, "orders", "rolled back")
+		return errors.New("business write failed")
+	})
+
+	pub := &mockPublisher{}
+	n, err := o.Relay(ctx, pub, 10)
+	if err != nil || n != 1 {
+		t.Fatalf("Relay = %d, %v, want 1 message", n, err)
+	}
+	if len(pub.published) != 1 || pub.published[0] != `orders:"committed"` {
+		t.Errorf("published = %v", pub.published)
+	}
+}
+
+func TestRelayBatchesInOrder(t *testing.T) {
+	o, db := newTestOutbox(t)
+	ctx := context.Background()
+	publishAll(t, o, "1", "2", "3", "4", "5")
+
+	pub := &mockPublisher{}
+	if n, err := o.Relay(ctx, pub, 3); err != nil || n != 3 {
+		t.Fatalf("first Relay = %d, %v", n, err)
+	}
+	if n, err := o.Relay(ctx, pub, 3); err != nil || n != 2 {
+		t.Fatalf("second Relay = %d, %v", n, err)
+	}
+	if n, _ := o.Relay(ctx, pub, 3); n != 0 {
+		t.Errorf("third Relay delivered %d messages", n)
+	}
+	want := []string{`orders:"1"`, `orders:"2"`, `orders:"3"`, `orders:"4"`, `orders:"5"`}
+	if len(pub.published) != len(want) {
+		t.Fatalf("published = %v", pub.published)
+	}
+	for i := range want {
+		if pub.published[i] != want[i] {
+			t.Fatalf("published = %v, want %v", pub.published, want)
+		}
+	}
+
+	var delivered int64
+	db.Model(&Message{}).Where("status = ? AND delivered_at IS NOT NULL", StatusDelivered).Count(&delivered)
+	if delivered != 5 {
+		t.Errorf("%d messages marked delivered, want 5", delivered)
+	}
+}
+
+func TestRelayStopsBatchOnFailure(t *testing.T) {
+	o, db := newTestOutbox(t)
+	ctx := context.Background()
+	publishAll(t, o, "1", "2", "3")
+
+	broker := errors.New("broker unavailable")
+	pub := &mockPublisher{fail: func(payload string) error {
+		if payload == `"2"` {
+			return broker
+		}
+		return nil
+	}}
+	n, err := o.Relay(ctx, pub, 10)
+	if !errors.Is(err, broker) || n != 1 {
+		t.Fatalf("Relay = %d, %v, want 1 delivered and the publish error", n, err)
+	}
+	// 第三条消息不会越过失败的第二条被投递
+	if len(pub.published) != 1 {
+		t.Errorf("published = %v", pub.published)
+	}
+	var failed Message
+	db.Where("payload = ?", []byte(`"2"`)).First(&failed)
+	if failed.Status != StatusPending || failed.Attempts != 1 || failed.LastError != broker.Error() {
+		t.Errorf("failed message = %+v", failed)
+	}
+
+	pub.fail = nil
+	if n, err := o.Relay(ctx, pub, 10); err != nil || n != 2 {
+		t.Errorf("retry Relay = %d, %v, want 2", n, err)
+	}
+}
+
+func TestCleanupDelivered(t *testing.T) {
+	o, _ := newTestOutbox(t)
+	ctx := context.Background()
+	publishAll(t, o, "1", "2")
+	o.Relay(ctx, &mockPublisher{}, 1)
+
+	if n, err := o.CleanupDelivered(ctx, time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Errorf("cleanup of recent messages = %d, %v", n, err)
+	}
+	// pending 消息不会被删除
+	if n, err := o.CleanupDelivered(ctx, time.Now().Add(time.Second)); err != nil || n != 1 {
+		t.Errorf("CleanupDelivered = %d, %v, want 1", n, err)
+	}
+}
@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// rateLimitWindow rate_limit_windows 表中的一行：某个 key 在一个固定窗口内的请求次数
+type rateLimitWindow struct {
+	Key         string    `gorm:"primaryKey;size:255"`
+	WindowStart time.Time `gorm:"primaryKey;index"`
+	Count       int       `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"not null;index"` // 窗口结束时间，CleanupExpired 据此删除过期的行
+}
+
+// TableName 指定限流窗口表名
+func (rateLimitWindow) TableName() string {
+	return "rate_limit_windows"
+}
+
+// DBWindowLimiter 基于数据库表的固定窗口限流，计数保存在数据库中，适用于每次请求都可能运行在新进程中的 Serverless 环境
+type DBWindowLimiter struct {
+	db *gorm.DB
+}
+
+// NewDBWindowLimiter 创建限流器并自动建表
+func NewDBWindowLimiter(db *gorm.DB) (*DBWindowLimiter, error) {
+	if err := db.AutoMigrate(&rateLimitWindow{}); err != nil {
+		return nil, err
+	}
+	return &DBWindowLimiter{db: db}, nil
+}
+
+// Allow 将 key 在当前窗口内的计数原子地加 1，返回是否允许以及窗口内的剩余次数。
+// 计数通过 INSERT ... ON CONFLICT DO UPDATE 递增，并在同一事务中读取，并发调用时最多 limit 个请求被允许
+func (l *DBWindowLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, int, error) {
+	start := time.Now().Truncate(window)
+	var count int
+	err := l.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		row := rateLimitWindow{Key: key, WindowStart: start, Count: 1, ExpiresAt: start.Add(window)}
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "key"}, {Name: "window_start"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"count": gorm.Expr("? + 1", clause.Column{Table: "rate_limit_windows", Name: "count"}),
+			}),
+		}).Create(&row).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&rateLimitWindow{}).
+			Select("count").
+			Where(clause.Eq{Column: clause.Column{Name: "key"}, Value: key}).
+			Where("window_start = ?", start).
+			Scan(&count).Error
+	})
+	if err != nil {
+		return false, 0, err
+	}
+	if count > limit {
+		return false, 0, nil
+	}
+	return true, limit - count, nil
+}
+
+// CleanupExpired 删除 db 中窗口已经结束的行。每行记录了自己窗口的结束时间，
+// 因此使用不同 window 的调用共用一张表时也只会删除过期的行
+func CleanupExpired(ctx context.Context, db *gorm.DB) error {
+	return db.WithContext(ctx).
+		Where("expires_at < ?", time.Now()).
+		Delete(&rateLimitWindow{}).Error
+}
@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestLimiter(t *testing.T) (*DBWindowLimiter, *gorm.DB) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 内存库每个连接都是独立的数据库，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	limiter, err := NewDBWindowLimiter(db)
+	if err != nil {
+		t.Fatalf("NewDBWindowLimiter: %v", err)
+	}
+	return limiter, db
+}
+
+func TestDBWindowLimiterConcurrent(t *testing.T) {
+	const limit, callers = 10, 50
+	limiter, _ := newTestLimiter(t)
+
+	var (
+		mu        sync.Mutex
+		remaining []int
+		wg        sync.WaitGroup
+	)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			allowed, left, err := limiter.Allow(context.Background(), "user:1", limit, time.Hour)
+			if err != nil {
+				t.Errorf("Allow: %v", err)
+				return
+			}
+			if allowed {
+				mu.Lock()
+				remaining = append(remaining, left)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(remaining) != limit {
+		t.Fatalf("%d of %d calls allowed, want %d", len(remaining), callers, limit)
+	}
+	// 每个被允许的请求看到的计数各不相同
+	sort.Ints(remaining)
+	for i, left := range remaining {
+		if left != i {
+			t.Fatalf("remaining counts = %v, want 0..%d", remaining, limit-1)
+		}
+	}
+
+	// 其他 key 使用独立的计数
+	if allowed, left, _ := limiter.Allow(context.Background(), "user:2", limit, time.Hour); !allowed || left != limit-1 {
+		t.Errorf("other key = %v, %d", allowed, left)
+	}
+}
+
+func TestDBWindowLimiterNewWindow(t *testing.T) {
+	limiter, _ := newTestLimiter(t)
+	ctx := context.Background()
+	const window = 50 * time.Millisecond
+	// 在窗口开始处调用，避免两次调用跨越窗口边界
+	time.Sleep(time.Until(time.Now().Truncate(window).Add(window)))
+
+	if allowed, _, _ := limiter.Allow(ctx, "k", 1, window); !allowed {
+		t.Fatal("first call rejected")
+	}
+	if allowed, _, _ := limiter.Allow(ctx, "k", 1, window); allowed {
+		t.Fatal("second call in the same window allowed")
+	}
+	time.Sleep(window)
+	if allowed, _, _ := limiter.Allow(ctx, "k", 1, window); !allowed {
+		t.Error("call in the next window rejected")
+	}
+}
+
+func TestCleanupExpired(t *testing.T) {
+	limiter, db := newTestLimiter(t)
+	ctx := context.Background()
+	now := time.Now()
+	db.Create(&[]rateLimitWindow{
+		{Key: "old", WindowStart: now.Add(-2 * time.Hour), Count: 3, ExpiresAt: now.Add(-time.Hour)},
+		{Key: "long", WindowStart: now.Add(-2 * time.Hour), Count: 3, ExpiresAt: now.Add(time.Hour)},
+	})
+	limiter.Allow(ctx, "current", 5, time.Minute)
+
+	if err := CleanupExpired(ctx, db); err != nil {
+		t.Fatalf("CleanupExpired: %v", err)
+	}
+	var keys []string
+	db.Model(&rateLimitWindow{}).Order("key").Pluck("key", &keys)
+	if len(keys) != 2 || keys[0] != "current" || keys[1] != "long" {
+		t.Errorf("remaining keys = %v, want [current long]", keys)
+	}
+}
@@ -0,0 +1,75 @@
+package rsa
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+)
+
+// PEM 块类型
+const (
+	pkcs8PrivateKeyType = "PRIVATE KEY"
+	spkiPublicKeyType   = "PUBLIC KEY"
+)
+
+// PrivateKeyToPKCS8PEM 将 RSA 私钥编码为 PKCS#8 格式的 PEM
+func PrivateKeyToPKCS8PEM(priv *rsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := pem.Encode(&buf, &pem.Block{Type: pkcs8PrivateKeyType, Bytes: der}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PKCS8PEMToPrivateKey 解析 PKCS#8 格式的 PEM 私钥，私钥类型不是 RSA 时返回错误
+func PKCS8PEMToPrivateKey(pemBytes []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil || block.Type != pkcs8PrivateKeyType {
+		return nil, errors.New("rsa: invalid PKCS8 PEM block")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	priv, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("rsa: PKCS8 key is not an RSA private key")
+	}
+	return priv, nil
+}
+
+// PublicKeyToSPKIPEM 将 RSA 公钥编码为 SubjectPublicKeyInfo（PKIX）格式的 PEM，便于 Java、Python 等客户端使用
+func PublicKeyToSPKIPEM(pub *rsa.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := pem.Encode(&buf, &pem.Block{Type: spkiPublicKeyType, Bytes: der}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SPKIPEMToPublicKey 解析 SubjectPublicKeyInfo 格式的 PEM 公钥
+func SPKIPEMToPublicKey(pemBytes []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil || block.Type != spkiPublicKeyType {
+		return nil, errors.New("rsa: invalid public key PEM block")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	pub, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("rsa: public key is not an RSA key")
+	}
+	return pub, nil
+}
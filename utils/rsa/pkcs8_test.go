@@ -0,0 +1,82 @@
+package rsa
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+func TestPKCS8RoundTripSignVerify(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privPEM, err := PrivateKeyToPKCS8PEM(priv)
+	if err != nil {
+		t.Fatalf("PrivateKeyToPKCS8PEM: %v", err)
+	}
+	decoded, err := PKCS8PEMToPrivateKey(privPEM)
+	if err != nil {
+		t.Fatalf("PKCS8PEMToPrivateKey: %v", err)
+	}
+	if !decoded.Equal(priv) {
+		t.Fatal("decoded private key differs from the original")
+	}
+
+	pubPEM, err := PublicKeyToSPKIPEM(&priv.PublicKey)
+	if err != nil {
+		t.Fatalf("PublicKeyToSPKIPEM: %v", err)
+	}
+	pub, err := SPKIPEMToPublicKey(pubPEM)
+	if err != nil {
+		t.Fatalf("SPKIPEMToPublicKey: %v", err)
+	}
+
+	digest := sha256.Sum256([]byte("interop message"))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, decoded, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("sign with decoded key: %v", err)
+	}
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig); err != nil {
+		t.Errorf("verify with decoded public key: %v", err)
+	}
+	tampered := sha256.Sum256([]byte("interop message!"))
+	if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, tampered[:], sig); err == nil {
+		t.Error("signature verified for a different message")
+	}
+}
+
+func TestPKCS8PEMToPrivateKeyErrors(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecDER, _ := x509.MarshalPKCS8PrivateKey(ecKey)
+	ecPEM := pem.EncodeToMemory(&pem.Block{Type: pkcs8PrivateKeyType, Bytes: ecDER})
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	pkcs1PEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+
+	tests := map[string][]byte{
+		"not pem":     []byte("garbage"),
+		"pkcs1 block": pkcs1PEM,
+		"corrupt der": pem.EncodeToMemory(&pem.Block{Type: pkcs8PrivateKeyType, Bytes: []byte{1, 2, 3}}),
+		"non-rsa key": ecPEM,
+	}
+	for name, input := range tests {
+		if _, err := PKCS8PEMToPrivateKey(input); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	ecPubDER, _ := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	if _, err := SPKIPEMToPublicKey(pem.EncodeToMemory(&pem.Block{Type: spkiPublicKeyType, Bytes: ecPubDER})); err == nil {
+		t.Error("SPKIPEMToPublicKey accepted an EC public key")
+	}
+}
@@ -0,0 +1,95 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Step saga 中的一个步骤，Compensate 撤销 Execute 的效果，可以为 nil
+type Step struct {
+	Name       string
+	Execute    func(ctx context.Context) error
+	Compensate func(ctx context.Context) error
+}
+
+// Saga 按顺序执行的一组步骤，任一步骤失败时按相反顺序补偿已完成的步骤
+type Saga struct {
+	Steps []Step
+}
+
+// New 创建包含 steps 的 Saga
+func New(steps ...Step) *Saga {
+	return &Saga{Steps: steps}
+}
+
+// SagaError Run 失败时返回的错误，包含失败的步骤、执行错误与补偿过程中的错误
+type SagaError struct {
+	FailedStep         string
+	ExecuteErr         error
+	CompensationErrors []error
+}
+
+// Error 实现 error 接口
+func (e *SagaError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "saga: step %q failed: %v", e.FailedStep, e.ExecuteErr)
+	if len(e.CompensationErrors) > 0 {
+		fmt.Fprintf(&b, "; %d compensation errors:", len(e.CompensationErrors))
+		for i, err := range e.CompensationErrors {
+			if i > 0 {
+				b.WriteString(";")
+			}
+			fmt.Fprintf(&b, " %v", err)
+		}
+	}
+	return b.String()
+}
+
+// Unwrap 返回执行错误，使 errors.Is 可以匹配步骤返回的错误
+func (e *SagaError) Unwrap() error {
+	return e.ExecuteErr
+}
+
+// Run 依次执行各步骤。某一步骤失败（或 ctx 已结束）时，按相反顺序调用所有已完成步骤的 Compensate，
+// 补偿错误会被收集但不会中断回滚，最终返回 *SagaError。
+// 补偿使用不随 ctx 取消的 context，保证请求被取消时回滚仍能完成
+func (s *Saga) Run(ctx context.Context) error {
+	for i, step := range s.Steps {
+		err := ctx.Err()
+		if err == nil {
+			err = step.Execute(ctx)
+		}
+		if err != nil {
+			return &SagaError{
+				FailedStep:         stepName(step, i),
+				ExecuteErr:         err,
+				CompensationErrors: s.compensate(context.WithoutCancel(ctx), i),
+			}
+		}
+	}
+	return nil
+}
+
+// compensate 按相反顺序补偿前 completed 个步骤
+func (s *Saga) compensate(ctx context.Context, completed int) []error {
+	var errs []error
+	for i := completed - 1; i >= 0; i-- {
+		step := s.Steps[i]
+		if step.Compensate == nil {
+			continue
+		}
+		if err := step.Compensate(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("compensate %q: %w", stepName(step, i), err))
+		}
+	}
+	return errs
+}
+
+// stepName 返回步骤名称，未设置时使用序号
+func stepName(step Step, i int) string {
+	if step.Name != "" {
+		return step.Name
+	}
+	return fmt.Sprintf("#%d", i+1)
+}
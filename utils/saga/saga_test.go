@@ -0,0 +1,110 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// recordingStep 返回一个把执行与补偿记录到 calls 的步骤
+func recordingStep(name string, calls *[]string, execErr, compErr error) Step {
+	return Step{
+		Name: name,
+		Execute: func(ctx context.Context) error {
+			*calls = append(*calls, "execute "+name)
+			return execErr
+		},
+		Compensate: func(ctx context.Context) error {
+			*calls = append(*calls, "compensate "+name)
+			return compErr
+		},
+	}
+}
+
+func TestRunCompensatesCompletedSteps(t *testing.T) {
+	errCharge := errors.New("card declined")
+	var calls []string
+	s := New(
+		recordingStep("reserve", &calls, nil, nil),
+		recordingStep("charge", &calls, errCharge, nil),
+		recordingStep("ship", &calls, nil, nil),
+	)
+
+	err := s.Run(context.Background())
+	var sagaErr *SagaError
+	if !errors.As(err, &sagaErr) {
+		t.Fatalf("Run = %v, want *SagaError", err)
+	}
+	if sagaErr.FailedStep != "charge" || !errors.Is(err, errCharge) {
+		t.Errorf("FailedStep = %q, err = %v", sagaErr.FailedStep, err)
+	}
+	if len(sagaErr.CompensationErrors) != 0 {
+		t.Errorf("CompensationErrors = %v", sagaErr.CompensationErrors)
+	}
+	// 失败的步骤与未执行的步骤都不补偿
+	want := []string{"execute reserve", "execute charge", "compensate reserve"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestRunCompensationErrorsDoNotStopRollback(t *testing.T) {
+	errComp := errors.New("refund failed")
+	var calls []string
+	s := New(
+		recordingStep("a", &calls, nil, nil),
+		recordingStep("b", &calls, nil, errComp),
+		recordingStep("c", &calls, errors.New("boom"), nil),
+	)
+
+	var sagaErr *SagaError
+	if !errors.As(s.Run(context.Background()), &sagaErr) {
+		t.Fatal("Run did not return *SagaError")
+	}
+	want := []string{"execute a", "execute b", "execute c", "compensate b", "compensate a"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+	if len(sagaErr.CompensationErrors) != 1 || !errors.Is(sagaErr.CompensationErrors[0], errComp) {
+		t.Errorf("CompensationErrors = %v", sagaErr.CompensationErrors)
+	}
+}
+
+func TestRunSuccess(t *testing.T) {
+	var calls []string
+	s := New(recordingStep("a", &calls, nil, nil), Step{Execute: func(context.Context) error { return nil }})
+	if err := s.Run(context.Background()); err != nil {
+		t.Fatalf("Run = %v", err)
+	}
+	if want := []string{"execute a"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestRunCancelledContext(t *testing.T) {
+	var calls []string
+	ctx, cancel := context.WithCancel(context.Background())
+	s := New(
+		Step{Name: "a", Execute: func(context.Context) error { cancel(); return nil }, Compensate: func(ctx context.Context) error {
+			// 补偿使用的 context 不随请求取消
+			if ctx.Err() != nil {
+				t.Errorf("compensation ctx cancelled: %v", ctx.Err())
+			}
+			calls = append(calls, "compensate a")
+			return nil
+		}},
+		recordingStep("b", &calls, nil, nil),
+	)
+
+	var sagaErr *SagaError
+	if err := s.Run(ctx); !errors.As(err, &sagaErr) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run = %v, want context.Canceled", err)
+	}
+	if sagaErr.FailedStep != "b" {
+		t.Errorf("FailedStep = %q, want b", sagaErr.FailedStep)
+	}
+	if want := []string{"compensate a"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 默认超时配置
+const (
+	DefaultAddr         = ":8080"
+	DefaultReadTimeout  = 15 * time.Second
+	DefaultWriteTimeout = 15 * time.Second
+	DefaultIdleTimeout  = 60 * time.Second
+)
+
+// Server 封装 *http.Server，统一超时和 TLS 配置
+type Server struct {
+	httpServer *http.Server
+	certFile   string
+	keyFile    string
+}
+
+// Option Server 的可选配置
+type Option func(*Server)
+
+// ShutdownRegistrar 关闭协调器需要实现的接口，服务停止时会调用注册的函数
+type ShutdownRegistrar interface {
+	Register(name string, fn func(ctx context.Context) error)
+}
+
+// WithAddr 设置监听地址
+func WithAddr(addr string) Option {
+	return func(s *Server) {
+		s.httpServer.Addr = addr
+	}
+}
+
+// WithReadTimeout 设置读取请求（含请求头）的超时时间
+func WithReadTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.httpServer.ReadTimeout = d
+		s.httpServer.ReadHeaderTimeout = d
+	}
+}
+
+// WithWriteTimeout 设置写响应的超时时间
+func WithWriteTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.httpServer.WriteTimeout = d
+	}
+}
+
+// WithIdleTimeout 设置 keep-alive 连接的空闲超时时间
+func WithIdleTimeout(d time.Duration) Option {
+	return func(s *Server) {
+		s.httpServer.IdleTimeout = d
+	}
+}
+
+// WithTLS 使用证书文件启用 TLS
+func WithTLS(certFile, keyFile string) Option {
+	return func(s *Server) {
+		s.certFile = certFile
+		s.keyFile = keyFile
+	}
+}
+
+// WithTLSConfig 使用自定义的 tls.Config，证书可直接放在 Certificates 中
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(s *Server) {
+		s.httpServer.TLSConfig = cfg
+	}
+}
+
+// New 创建新的 Server 实例
+func New(engine *gin.Engine, opts ...Option) *Server {
+	s := &Server{
+		httpServer: &http.Server{
+			Addr:              DefaultAddr,
+			Handler:           engine,
+			ReadTimeout:       DefaultReadTimeout,
+			ReadHeaderTimeout: DefaultReadTimeout,
+			WriteTimeout:      DefaultWriteTimeout,
+			IdleTimeout:       DefaultIdleTimeout,
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// HTTPServer 返回底层的 *http.Server
+func (s *Server) HTTPServer() *http.Server {
+	return s.httpServer
+}
+
+// Start 启动服务并阻塞，配置了 TLS 时使用 ListenAndServeTLS；通过 Shutdown 正常关闭时返回 nil
+func (s *Server) Start() error {
+	var err error
+	if s.tlsEnabled() {
+		err = s.httpServer.ListenAndServeTLS(s.certFile, s.keyFile)
+	} else {
+		err = s.httpServer.ListenAndServe()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown 优雅关闭服务，等待进行中的请求完成或 ctx 超时
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
+}
+
+// RegisterShutdown 将 Shutdown 注册到关闭协调器
+func (s *Server) RegisterShutdown(coordinator ShutdownRegistrar) {
+	coordinator.Register("http-server", s.Shutdown)
+}
+
+// tlsEnabled 判断是否配置了证书文件或带证书的 tls.Config
+func (s *Server) tlsEnabled() bool {
+	if s.certFile != "" && s.keyFile != "" {
+		return true
+	}
+	cfg := s.httpServer.TLSConfig
+	return cfg != nil && (len(cfg.Certificates) > 0 || cfg.GetCertificate != nil)
+}
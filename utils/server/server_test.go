@@ -0,0 +1,127 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newEngine() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/fast", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	engine.GET("/slow", func(c *gin.Context) {
+		time.Sleep(300 * time.Millisecond)
+		c.String(http.StatusOK, "late")
+	})
+	return engine
+}
+
+// startTestServer 使用 Server 配置好的 *http.Server 启动 httptest 服务
+func startTestServer(t *testing.T, s *Server) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewUnstartedServer(s.HTTPServer().Handler)
+	ts.Config = s.HTTPServer()
+	ts.Start()
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestServerWriteTimeout(t *testing.T) {
+	ts := startTestServer(t, New(newEngine(), WithWriteTimeout(100*time.Millisecond)))
+
+	resp, err := http.Get(ts.URL + "/fast")
+	if err != nil {
+		t.Fatalf("fast request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("fast status = %d", resp.StatusCode)
+	}
+
+	// 处理时间超过 WriteTimeout 时连接被关闭，客户端收不到响应
+	if resp, err := http.Get(ts.URL + "/slow"); err == nil {
+		resp.Body.Close()
+		t.Fatal("slow request succeeded despite write timeout")
+	}
+}
+
+func TestServerReadTimeout(t *testing.T) {
+	ts := startTestServer(t, New(newEngine(), WithReadTimeout(100*time.Millisecond)))
+
+	conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	// 只发送一半请求头，服务端应在 ReadTimeout 后关闭连接
+	if _, err := conn.Write([]byte("GET /fast HTTP/1.1\r\nHost: x\r\n")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	start := time.Now()
+	_, err = bufio.NewReader(conn).ReadByte()
+	if ne, ok := err.(net.Error); ok && ne.Timeout() {
+		t.Fatal("server did not close the connection within the read timeout")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("connection closed after %v", elapsed)
+	}
+}
+
+type fakeRegistrar struct {
+	names []string
+	fns   []func(ctx context.Context) error
+}
+
+func (r *fakeRegistrar) Register(name string, fn func(ctx context.Context) error) {
+	r.names = append(r.names, name)
+	r.fns = append(r.fns, fn)
+}
+
+func TestServerStartAndShutdown(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	s := New(newEngine(), WithAddr(addr))
+	registrar := &fakeRegistrar{}
+	s.RegisterShutdown(registrar)
+	if len(registrar.fns) != 1 {
+		t.Fatalf("registered %d shutdown funcs, want 1", len(registrar.fns))
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.Start() }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		resp, err := http.Get("http://" + addr + "/fast")
+		if err == nil {
+			resp.Body.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server did not start: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := registrar.fns[0](context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("Start returned %v after Shutdown, want nil", err)
+	}
+}
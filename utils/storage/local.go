@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// 预签名 URL 的查询参数
+const (
+	paramKey       = "key"
+	paramOperation = "op"
+	paramExpires   = "expires"
+	paramSignature = "signature"
+)
+
+// LocalStore 基于本地目录的对象存储，预签名 URL 使用 HMAC-SHA256 签名
+type LocalStore struct {
+	root    string
+	baseURL string
+	secret  []byte
+}
+
+// NewLocalStore 创建本地存储，baseURL 为校验预签名 URL 的处理接口地址
+func NewLocalStore(root, baseURL string, secret []byte) *LocalStore {
+	return &LocalStore{root: root, baseURL: baseURL, secret: secret}
+}
+
+// Put 写入对象，必要时创建父目录
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Get 读取对象，调用方负责关闭返回的 ReadCloser
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Delete 删除对象，对象不存在时不报错
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// PresignedURL 生成带 key、操作、过期时间和签名查询参数的 URL
+func (s *LocalStore) PresignedURL(ctx context.Context, key string, ttl time.Duration, operation string) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	if operation != OperationGet && operation != OperationPut {
+		return "", ErrInvalidOperation
+	}
+	u, err := url.Parse(s.baseURL)
+	if err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	query := u.Query()
+	query.Set(paramKey, key)
+	query.Set(paramOperation, operation)
+	query.Set(paramExpires, expires)
+	query.Set(paramSignature, s.sign(key, operation, expires))
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// ValidatePresignedURL 校验预签名 URL 的签名和有效期，返回其中的 key 和操作
+func (s *LocalStore) ValidatePresignedURL(rawURL string) (key string, operation string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+	query := u.Query()
+	key = query.Get(paramKey)
+	operation = query.Get(paramOperation)
+	expires := query.Get(paramExpires)
+	expected := s.sign(key, operation, expires)
+	if !hmac.Equal([]byte(expected), []byte(query.Get(paramSignature))) {
+		return "", "", ErrInvalidSignature
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return "", "", ErrInvalidSignature
+	}
+	if time.Now().Unix() > expiresAt {
+		return "", "", ErrURLExpired
+	}
+	return key, operation, nil
+}
+
+// sign 计算 key、操作和过期时间的 HMAC-SHA256 签名
+func (s *LocalStore) sign(key, operation, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + operation + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path 将 key 转换为存储目录下的文件路径；key 先按绝对路径清理，
+// 因此 "../" 之类的片段无法跳出存储目录
+func (s *LocalStore) path(key string) (string, error) {
+	cleaned := filepath.Clean(string(filepath.Separator) + filepath.FromSlash(key))
+	if cleaned == string(filepath.Separator) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.root, cleaned), nil
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testBaseURL = "https://files.example.com/presigned"
+
+func newTestLocalStore(t *testing.T) *LocalStore {
+	t.Helper()
+	return NewLocalStore(t.TempDir(), testBaseURL, []byte("test-secret"))
+}
+
+func TestLocalStorePutGetDelete(t *testing.T) {
+	store := newTestLocalStore(t)
+	ctx := context.Background()
+
+	if err := store.Put(ctx, "a/b/report.txt", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	rc, err := store.Get(ctx, "a/b/report.txt")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "hello" {
+		t.Errorf("Get = %q, want hello", data)
+	}
+
+	if err := store.Delete(ctx, "a/b/report.txt"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Delete(ctx, "a/b/report.txt"); err != nil {
+		t.Errorf("Delete of a missing object: %v", err)
+	}
+	if _, err := store.Get(ctx, "a/b/report.txt"); !os.IsNotExist(err) {
+		t.Errorf("Get after Delete: err = %v, want not exist", err)
+	}
+}
+
+func TestLocalStoreKeyStaysInRoot(t *testing.T) {
+	store := newTestLocalStore(t)
+	if err := store.Put(context.Background(), "../../escape.txt", strings.NewReader("x")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(store.root, "escape.txt")); err != nil {
+		t.Errorf("traversal key was not written inside the root: %v", err)
+	}
+	if err := store.Put(context.Background(), "/", strings.NewReader("x")); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Put with empty key: err = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestPresignedURLValid(t *testing.T) {
+	store := newTestLocalStore(t)
+	for _, op := range []string{OperationGet, OperationPut} {
+		raw, err := store.PresignedURL(context.Background(), "docs/报告 1.pdf", time.Minute, op)
+		if err != nil {
+			t.Fatalf("PresignedURL(%s): %v", op, err)
+		}
+		if !strings.HasPrefix(raw, testBaseURL+"?") {
+			t.Errorf("URL %q does not start with the base URL", raw)
+		}
+		key, operation, err := store.ValidatePresignedURL(raw)
+		if err != nil {
+			t.Fatalf("ValidatePresignedURL: %v", err)
+		}
+		if key != "docs/报告 1.pdf" || operation != op {
+			t.Errorf("ValidatePresignedURL = %q, %q, want the signed key and %s", key, operation, op)
+		}
+	}
+}
+
+func TestPresignedURLInvalidArgs(t *testing.T) {
+	store := newTestLocalStore(t)
+	if _, err := store.PresignedURL(context.Background(), "k", time.Minute, "delete"); !errors.Is(err, ErrInvalidOperation) {
+		t.Errorf("unknown operation: err = %v, want ErrInvalidOperation", err)
+	}
+	if _, err := store.PresignedURL(context.Background(), "", time.Minute, OperationGet); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("empty key: err = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestPresignedURLExpired(t *testing.T) {
+	store := newTestLocalStore(t)
+	raw, err := store.PresignedURL(context.Background(), "k", -2*time.Second, OperationGet)
+	if err != nil {
+		t.Fatalf("PresignedURL: %v", err)
+	}
+	if _, _, err := store.ValidatePresignedURL(raw); !errors.Is(err, ErrURLExpired) {
+		t.Errorf("expired URL: err = %v, want ErrURLExpired", err)
+	}
+}
+
+func TestPresignedURLTampered(t *testing.T) {
+	store := newTestLocalStore(t)
+	raw, err := store.PresignedURL(context.Background(), "docs/a.pdf", time.Minute, OperationGet)
+	if err != nil {
+		t.Fatalf("PresignedURL: %v", err)
+	}
+
+	tamper := func(param, value string) string {
+		u, _ := url.Parse(raw)
+		q := u.Query()
+		q.Set(param, value)
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	tests := []struct {
+		name string
+		url  string
+	}{
+		{"key", tamper(paramKey, "docs/b.pdf")},
+		{"operation", tamper(paramOperation, OperationPut)},
+		{"expires", tamper(paramExpires, "9999999999")},
+		{"signature", tamper(paramSignature, strings.Repeat("0", 64))},
+		{"missing signature", tamper(paramSignature, "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := store.ValidatePresignedURL(tt.url); !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("err = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+
+	// 其他密钥签出的 URL 同样无效
+	other := NewLocalStore(t.TempDir(), testBaseURL, []byte("other-secret"))
+	if _, _, err := other.ValidatePresignedURL(raw); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("URL signed with another secret: err = %v, want ErrInvalidSignature", err)
+	}
+}
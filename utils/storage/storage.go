@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// 预签名 URL 支持的操作
+const (
+	OperationGet = "get"
+	OperationPut = "put"
+)
+
+var (
+	// ErrInvalidKey 对象 key 不合法（为空或试图访问存储目录之外）
+	ErrInvalidKey = errors.New("storage: invalid key")
+	// ErrInvalidOperation 预签名操作不是 get 或 put
+	ErrInvalidOperation = errors.New("storage: invalid operation")
+	// ErrURLExpired 预签名 URL 已过期
+	ErrURLExpired = errors.New("storage: presigned url expired")
+	// ErrInvalidSignature 预签名 URL 签名不正确或参数被篡改
+	ErrInvalidSignature = errors.New("storage: invalid presigned url signature")
+)
+
+// Store 对象存储接口
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// PresignedURL 生成在 ttl 内有效、允许直接执行 operation（get/put）的临时 URL
+	PresignedURL(ctx context.Context, key string, ttl time.Duration, operation string) (string, error)
+}
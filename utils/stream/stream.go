@@ -0,0 +1,119 @@
+package stream
+
+import (
+	"context"
+	"sync"
+)
+
+// Merge 将多个输入通道合并为一个输出通道，所有输入关闭或 ctx 结束后关闭输出。
+// 输出没有缓冲，下游读取变慢时各输入的读取也随之放慢
+func Merge[T any](ctx context.Context, channels ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(channels))
+	for _, ch := range channels {
+		go func(ch <-chan T) {
+			defer wg.Done()
+			for {
+				select {
+				case v, ok := <-ch:
+					if !ok {
+						return
+					}
+					select {
+					case out <- v:
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(ch)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Split 按 predicate 将输入分到 matched 与 unmatched 两个通道，in 关闭或 ctx 结束后两者都会关闭。
+// 两个输出都需要被读取，任一输出阻塞都会暂停整个分流
+func Split[T any](ctx context.Context, in <-chan T, predicate func(T) bool) (<-chan T, <-chan T) {
+	matched := make(chan T)
+	unmatched := make(chan T)
+	go func() {
+		defer close(matched)
+		defer close(unmatched)
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				out := unmatched
+				if predicate(v) {
+					out = matched
+				}
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return matched, unmatched
+}
+
+// Buffer 在两个处理阶段之间插入容量为 size 的缓冲，上游最多领先下游 size 个元素；in 关闭后输出随之关闭
+func Buffer[T any](in <-chan T, size int) <-chan T {
+	if size < 0 {
+		size = 0
+	}
+	out := make(chan T, size)
+	go func() {
+		defer close(out)
+		for v := range in {
+			out <- v
+		}
+	}()
+	return out
+}
+
+// Tee 将输入中的每个元素广播到 n 个输出通道，in 关闭后所有输出随之关闭。
+// 每个元素送达所有输出后才读取下一个，因此整体速度取决于最慢的读取方
+func Tee[T any](in <-chan T, n int) []<-chan T {
+	if n <= 0 {
+		return nil
+	}
+	outs := make([]chan T, n)
+	result := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T)
+		result[i] = outs[i]
+	}
+	go func() {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+		for v := range in {
+			var wg sync.WaitGroup
+			wg.Add(n)
+			// 并发发送，某个读取方暂时未就绪不会阻塞其他读取方收到当前元素
+			for _, out := range outs {
+				go func(out chan<- T) {
+					defer wg.Done()
+					out <- v
+				}(out)
+			}
+			wg.Wait()
+		}
+	}()
+	return result
+}
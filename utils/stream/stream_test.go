@@ -0,0 +1,121 @@
+package stream
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+// source 返回依次发送 values 后关闭的通道
+func source(values ...int) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for _, v := range values {
+			ch <- v
+		}
+	}()
+	return ch
+}
+
+// collect 读取 ch 直到关闭，超时则报告错误；会在其他 goroutine 中调用，因此不使用 Fatal
+func collect(t *testing.T, ch <-chan int) []int {
+	t.Helper()
+	var got []int
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return got
+			}
+			got = append(got, v)
+		case <-timeout:
+			t.Errorf("channel not closed, got %v so far", got)
+			return got
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	got := collect(t, Merge(context.Background(), source(1, 2, 3), source(4, 5), source()))
+	slices.Sort(got)
+	if want := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge = %v, want %v", got, want)
+	}
+}
+
+func TestMergeStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// 输入永不关闭，只能靠 ctx 结束
+	out := Merge(ctx, make(chan int), make(chan int))
+	cancel()
+	if got := collect(t, out); len(got) != 0 {
+		t.Errorf("Merge after cancel = %v", got)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	matched, unmatched := Split(context.Background(), source(1, 2, 3, 4, 5, 6), func(v int) bool { return v%2 == 0 })
+	done := make(chan []int)
+	go func() { done <- collect(t, unmatched) }()
+	even := collect(t, matched)
+	odd := <-done
+	if want := []int{2, 4, 6}; !reflect.DeepEqual(even, want) {
+		t.Errorf("matched = %v, want %v", even, want)
+	}
+	if want := []int{1, 3, 5}; !reflect.DeepEqual(odd, want) {
+		t.Errorf("unmatched = %v, want %v", odd, want)
+	}
+}
+
+func TestSplitStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	matched, unmatched := Split(ctx, make(chan int), func(int) bool { return true })
+	cancel()
+	collect(t, matched)
+	collect(t, unmatched)
+}
+
+func TestBuffer(t *testing.T) {
+	in := make(chan int)
+	out := Buffer(in, 3)
+	// 下游未读取时上游仍可领先 size 个元素（加上缓冲 goroutine 手中的一个）
+	for i := 0; i < 4; i++ {
+		select {
+		case in <- i:
+		case <-time.After(time.Second):
+			t.Fatalf("send %d blocked with buffer size 3", i)
+		}
+	}
+	select {
+	case in <- 4:
+		t.Fatal("send beyond buffer capacity did not block")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(in)
+	if got, want := collect(t, out), []int{0, 1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Buffer = %v, want %v", got, want)
+	}
+}
+
+func TestTee(t *testing.T) {
+	outs := Tee(source(1, 2, 3), 3)
+	if len(outs) != 3 {
+		t.Fatalf("len(Tee) = %d, want 3", len(outs))
+	}
+	results := make(chan []int, len(outs))
+	for _, out := range outs {
+		go func(out <-chan int) { results <- collect(t, out) }(out)
+	}
+	for range outs {
+		if got, want := <-results, []int{1, 2, 3}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Tee output = %v, want %v", got, want)
+		}
+	}
+	if Tee(source(), 0) != nil {
+		t.Error("Tee with n = 0 should return nil")
+	}
+}
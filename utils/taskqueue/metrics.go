@@ -0,0 +1,82 @@
+package taskqueue
+
+import (
+	"time"
+
+	"github.com/kobyt2/common-services/utils/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// queueMetrics 任务队列的 Prometheus 指标，均以 task_type 为标签
+type queueMetrics struct {
+	enqueued *prometheus.CounterVec
+	complete *prometheus.CounterVec
+	failed   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	depth    *prometheus.GaugeVec
+}
+
+// WithMetrics 向 registry 注册并记录队列指标，注册失败时 New 返回该错误。taskqueue_depth 由当前进程在入队时增加、
+// 完成或失败时减少，多个进程共用一张表时每个进程只反映自己的变化
+func WithMetrics(registry prometheus.Registerer) TaskQueueOption {
+	return func(q *Queue) {
+		q.registry = registry
+	}
+}
+
+// newQueueMetrics 向 registry 注册队列指标，同名指标已注册时复用已有的 collector
+func newQueueMetrics(registry prometheus.Registerer) (*queueMetrics, error) {
+	labels := []string{"task_type"}
+	m := &queueMetrics{}
+	var err error
+	if m.enqueued, err = metrics.Register(registry, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "taskqueue_enqueued_total",
+		Help: "Total number of enqueued tasks.",
+	}, labels)); err != nil {
+		return nil, err
+	}
+	if m.complete, err = metrics.Register(registry, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "taskqueue_completed_total",
+		Help: "Total number of completed tasks.",
+	}, labels)); err != nil {
+		return nil, err
+	}
+	if m.failed, err = metrics.Register(registry, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "taskqueue_failed_total",
+		Help: "Total number of failed tasks.",
+	}, labels)); err != nil {
+		return nil, err
+	}
+	if m.duration, err = metrics.Register(registry, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "taskqueue_processing_duration_seconds",
+		Help: "Time from dequeue to complete or fail in seconds.",
+	}, labels)); err != nil {
+		return nil, err
+	}
+	if m.depth, err = metrics.Register(registry, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "taskqueue_depth",
+		Help: "Number of tasks enqueued and not yet completed or failed.",
+	}, labels)); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// enqueue 记录一次入队
+func (m *queueMetrics) enqueue(taskType string) {
+	m.enqueued.WithLabelValues(taskType).Inc()
+	m.depth.WithLabelValues(taskType).Inc()
+}
+
+// finish 记录任务完成或失败以及处理耗时
+func (m *queueMetrics) finish(task *Task, status Status) {
+	if status == StatusCompleted {
+		m.complete.WithLabelValues(task.Type).Inc()
+	} else {
+		m.failed.WithLabelValues(task.Type).Inc()
+	}
+	if task.StartedAt != nil {
+		m.duration.WithLabelValues(task.Type).Observe(time.Since(*task.StartedAt).Seconds())
+	}
+	m.depth.WithLabelValues(task.Type).Dec()
+}
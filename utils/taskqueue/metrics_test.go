@@ -0,0 +1,112 @@
+package taskqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestQueueMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	q := newTestQueue(t, WithMetrics(registry))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := q.Enqueue(ctx, "email", i); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	if _, err := q.Enqueue(ctx, "sms", nil); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	m := q.metrics
+	if v := testutil.ToFloat64(m.enqueued.WithLabelValues("email")); v != 3 {
+		t.Errorf("enqueued{email} = %v, want 3", v)
+	}
+	if v := testutil.ToFloat64(m.depth.WithLabelValues("email")); v != 3 {
+		t.Errorf("depth{email} = %v, want 3", v)
+	}
+
+	first, _ := q.Dequeue(ctx, "w1", []string{"email"})
+	second, _ := q.Dequeue(ctx, "w1", []string{"email"})
+	if err := q.Complete(ctx, first.ID); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if err := q.Fail(ctx, second.ID, errors.New("bounced")); err != nil {
+		t.Fatalf("Fail: %v", err)
+	}
+
+	if v := testutil.ToFloat64(m.complete.WithLabelValues("email")); v != 1 {
+		t.Errorf("completed{email} = %v, want 1", v)
+	}
+	if v := testutil.ToFloat64(m.failed.WithLabelValues("email")); v != 1 {
+		t.Errorf("failed{email} = %v, want 1", v)
+	}
+	if v := testutil.ToFloat64(m.depth.WithLabelValues("email")); v != 1 {
+		t.Errorf("depth{email} = %v, want 1", v)
+	}
+	if v := testutil.ToFloat64(m.depth.WithLabelValues("sms")); v != 1 {
+		t.Errorf("depth{sms} = %v, want 1", v)
+	}
+
+	// 每次完成或失败各记录一次处理耗时
+	if n := sampleCount(t, registry, "taskqueue_processing_duration_seconds"); n != 2 {
+		t.Errorf("processing duration observations = %d, want 2", n)
+	}
+	if n := testutil.CollectAndCount(m.duration); n != 1 {
+		t.Errorf("processing duration series = %d, want only task_type=email", n)
+	}
+}
+
+// sampleCount 返回 registry 中名为 name 的直方图各标签组合的观测次数之和
+func sampleCount(t *testing.T, registry *prometheus.Registry, name string) uint64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count uint64
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			count += metric.GetHistogram().GetSampleCount()
+		}
+	}
+	return count
+}
+
+func TestWithMetricsRegistrationConflict(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	// 同名但标签不同的指标
+	registry.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "taskqueue_enqueued_total",
+		Help: "Total number of enqueued tasks.",
+	}))
+	if _, err := New(newTestDB(t), WithMetrics(registry)); err == nil {
+		t.Error("New returned nil error for a conflicting collector")
+	}
+}
+
+func TestWithMetricsSharedRegistry(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	db := newTestDB(t)
+	first, err := New(db, WithMetrics(registry))
+	if err != nil {
+		t.Fatalf("first New: %v", err)
+	}
+	second, err := New(db, WithMetrics(registry))
+	if err != nil {
+		t.Fatalf("second New: %v", err)
+	}
+	first.Enqueue(context.Background(), "email", nil)
+	second.Enqueue(context.Background(), "email", nil)
+	if v := testutil.ToFloat64(first.metrics.enqueued.WithLabelValues("email")); v != 2 {
+		t.Errorf("shared enqueued{email} = %v, want 2", v)
+	}
+}
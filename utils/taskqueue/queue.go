@@ -0,0 +1,274 @@
+package taskqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Status 任务状态
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// claimAttempts Dequeue 在并发争抢时重试领取的次数
+const claimAttempts = 3
+
+var (
+	// ErrNoTask 没有可领取的任务
+	ErrNoTask = errors.New("taskqueue: no task available")
+	// ErrTaskNotFound 任务不存在或状态不允许该操作
+	ErrTaskNotFound = errors.New("taskqueue: task not found")
+	// ErrMaxAttemptsExceeded 重试次数已用完，任务被标记为失败
+	ErrMaxAttemptsExceeded = errors.New("taskqueue: max attempts exceeded")
+)
+
+// Task tasks 表中的一条任务
+type Task struct {
+	ID          string `gorm:"primaryKey;size:36"`
+	Type        string `gorm:"size:128;not null;index:idx_tasks_claim,priority:2"`
+	Payload     []byte
+	Status      Status `gorm:"size:16;not null;index:idx_tasks_claim,priority:1"`
+	Attempts    int
+	MaxAttempts int
+	LastError   string
+	WorkerID    string    `gorm:"size:128"`
+	RunAt       time.Time `gorm:"not null;index:idx_tasks_claim,priority:3"`
+	StartedAt   *time.Time
+	FinishedAt  *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// TableName 指定任务表名
+func (Task) TableName() string {
+	return "tasks"
+}
+
+// Decode 将任务的 JSON 负载解码到 v
+func (t *Task) Decode(v interface{}) error {
+	return json.Unmarshal(t.Payload, v)
+}
+
+// TaskOption Enqueue 的可选配置
+type TaskOption func(*Task)
+
+// WithDelay 延迟 d 之后才允许被领取
+func WithDelay(d time.Duration) TaskOption {
+	return func(t *Task) {
+		t.RunAt = t.RunAt.Add(d)
+	}
+}
+
+// WithMaxAttempts 设置最大执行次数，0 表示不限制
+func WithMaxAttempts(n int) TaskOption {
+	return func(t *Task) {
+		t.MaxAttempts = n
+	}
+}
+
+// Queue 基于数据库表的任务队列，进程崩溃重启后任务不会丢失
+type Queue struct {
+	db       *gorm.DB
+	registry prometheus.Registerer
+	metrics  *queueMetrics
+}
+
+// TaskQueueOption Queue 的可选配置
+type TaskQueueOption func(*Queue)
+
+// New 创建任务队列并自动建表
+func New(db *gorm.DB, opts ...TaskQueueOption) (*Queue, error) {
+	if err := db.AutoMigrate(&Task{}); err != nil {
+		return nil, err
+	}
+	q := &Queue{db: db}
+	for _, opt := range opts {
+		opt(q)
+	}
+	if q.registry != nil {
+		metrics, err := newQueueMetrics(q.registry)
+		if err != nil {
+			return nil, err
+		}
+		q.metrics = metrics
+	}
+	return q, nil
+}
+
+// Enqueue 写入一条 pending 状态的任务并返回任务 ID，payload 以 JSON 保存
+func (q *Queue) Enqueue(ctx context.Context, taskType string, payload interface{}, opts ...TaskOption) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	task := Task{
+		ID:      uuid.NewString(),
+		Type:    taskType,
+		Payload: data,
+		Status:  StatusPending,
+		RunAt:   time.Now(),
+	}
+	for _, opt := range opts {
+		opt(&task)
+	}
+	if err := q.db.WithContext(ctx).Create(&task).Error; err != nil {
+		return "", err
+	}
+	if q.metrics != nil {
+		q.metrics.enqueue(task.Type)
+	}
+	return task.ID, nil
+}
+
+// Dequeue 原子地领取一条到期的任务，taskTypes 为空时领取任意类型；没有任务时返回 ErrNoTask
+// 支持的数据库使用 SELECT ... FOR UPDATE SKIP LOCKED，其余数据库依靠带状态条件的 UPDATE 保证只有一个 worker 领取成功
+func (q *Queue) Dequeue(ctx context.Context, workerID string, taskTypes []string) (*Task, error) {
+	for i := 0; i < claimAttempts; i++ {
+		var task Task
+		claimed := false
+		err := q.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			query := tx.Where("status = ? AND run_at <= ?", StatusPending, time.Now())
+			if len(taskTypes) > 0 {
+				query = query.Where("type IN ?", taskTypes)
+			}
+			if supportsSkipLocked(tx) {
+				query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+			}
+			result := query.Order("run_at ASC").Limit(1).Find(&task)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return ErrNoTask
+			}
+
+			now := time.Now()
+			result = tx.Model(&Task{}).
+				Where("id = ? AND status = ?", task.ID, StatusPending).
+				Updates(map[string]interface{}{
+					"status":     StatusRunning,
+					"worker_id":  workerID,
+					"started_at": now,
+					"attempts":   gorm.Expr("attempts + 1"),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 1 {
+				claimed = true
+				task.Status = StatusRunning
+				task.WorkerID = workerID
+				task.StartedAt = &now
+				task.Attempts++
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if claimed {
+			return &task, nil
+		}
+		// 被其他 worker 抢先领取，重新选择
+	}
+	return nil, ErrNoTask
+}
+
+// Complete 将运行中的任务标记为完成
+func (q *Queue) Complete(ctx context.Context, taskID string) error {
+	return q.terminate(ctx, taskID, map[string]interface{}{
+		"status":      StatusCompleted,
+		"finished_at": time.Now(),
+	})
+}
+
+// Fail 将运行中的任务标记为失败并记录错误
+func (q *Queue) Fail(ctx context.Context, taskID string, err error) error {
+	updates := map[string]interface{}{
+		"status":      StatusFailed,
+		"finished_at": time.Now(),
+	}
+	if err != nil {
+		updates["last_error"] = err.Error()
+	}
+	return q.terminate(ctx, taskID, updates)
+}
+
+// Retry 将运行中的任务放回队列，delay 之后可再次领取；已达到最大执行次数时标记为失败并返回 ErrMaxAttemptsExceeded
+func (q *Queue) Retry(ctx context.Context, taskID string, delay time.Duration) error {
+	var task Task
+	if err := q.db.WithContext(ctx).Where("id = ? AND status = ?", taskID, StatusRunning).First(&task).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTaskNotFound
+		}
+		return err
+	}
+	if task.MaxAttempts > 0 && task.Attempts >= task.MaxAttempts {
+		if err := q.Fail(ctx, taskID, ErrMaxAttemptsExceeded); err != nil {
+			return err
+		}
+		return ErrMaxAttemptsExceeded
+	}
+	return q.finish(ctx, taskID, map[string]interface{}{
+		"status":    StatusPending,
+		"worker_id": "",
+		"run_at":    time.Now().Add(delay),
+	})
+}
+
+// terminate 将运行中的任务更新为完成或失败；启用指标时先读取任务类型和开始时间用于记录
+func (q *Queue) terminate(ctx context.Context, taskID string, updates map[string]interface{}) error {
+	if q.metrics == nil {
+		return q.finish(ctx, taskID, updates)
+	}
+	var task Task
+	err := q.db.WithContext(ctx).Select("id", "type", "started_at").
+		Where("id = ? AND status = ?", taskID, StatusRunning).First(&task).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTaskNotFound
+		}
+		return err
+	}
+	if err := q.finish(ctx, taskID, updates); err != nil {
+		return err
+	}
+	q.metrics.finish(&task, updates["status"].(Status))
+	return nil
+}
+
+// finish 更新运行中任务的状态
+func (q *Queue) finish(ctx context.Context, taskID string, updates map[string]interface{}) error {
+	result := q.db.WithContext(ctx).Model(&Task{}).
+		Where("id = ? AND status = ?", taskID, StatusRunning).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTaskNotFound
+	}
+	return nil
+}
+
+// supportsSkipLocked 判断数据库是否支持 FOR UPDATE SKIP LOCKED
+func supportsSkipLocked(db *gorm.DB) bool {
+	switch db.Dialector.Name() {
+	case "postgres", "mysql":
+		return true
+	default:
+		return false
+	}
+}
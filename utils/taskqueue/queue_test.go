@@ -0,0 +1,180 @@
+package taskqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	// 内存库每个连接都是独立的数据库，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func newTestQueue(t *testing.T, opts ...TaskQueueOption) *Queue {
+	t.Helper()
+	q, err := New(newTestDB(t), opts...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return q
+}
+
+func TestEnqueueDequeueComplete(t *testing.T) {
+	q := newTestQueue(t)
+	ctx := context.Background()
+
+	id, err := q.Enqueue(ctx, "email", map[string]string{"to": "a@example.com"})
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if _, err := q.Dequeue(ctx, "w1", []string{"sms"}); !errors.Is(err, ErrNoTask) {
+		t.Fatalf("Dequeue other type: err = %v, want ErrNoTask", err)
+	}
+
+	task, err := q.Dequeue(ctx, "w1", []string{"email"})
+	if err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if task.ID != id || task.Status != StatusRunning || task.WorkerID != "w1" || task.Attempts != 1 {
+		t.Errorf("Dequeue = %+v, want the enqueued task running on w1 with 1 attempt", task)
+	}
+	var payload map[string]string
+	if err := task.Decode(&payload); err != nil || payload["to"] != "a@example.com" {
+		t.Errorf("Decode = %v, %v", payload, err)
+	}
+	if _, err := q.Dequeue(ctx, "w2", nil); !errors.Is(err, ErrNoTask) {
+		t.Errorf("Dequeue of a claimed task: err = %v, want ErrNoTask", err)
+	}
+
+	if err := q.Complete(ctx, id); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if err := q.Complete(ctx, id); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("second Complete: err = %v, want ErrTaskNotFound", err)
+	}
+}
+
+func TestDelayedTaskNotClaimedEarly(t *testing.T) {
+	q := newTestQueue(t)
+	ctx := context.Background()
+
+	if _, err := q.Enqueue(ctx, "report", nil, WithDelay(time.Hour)); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if _, err := q.Dequeue(ctx, "w1", nil); !errors.Is(err, ErrNoTask) {
+		t.Errorf("Dequeue of a delayed task: err = %v, want ErrNoTask", err)
+	}
+}
+
+func TestFailRecordsError(t *testing.T) {
+	q := newTestQueue(t)
+	ctx := context.Background()
+
+	id, _ := q.Enqueue(ctx, "email", nil)
+	if _, err := q.Dequeue(ctx, "w1", nil); err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if err := q.Fail(ctx, id, errors.New("smtp down")); err != nil {
+		t.Fatalf("Fail: %v", err)
+	}
+
+	var task Task
+	if err := q.db.First(&task, "id = ?", id).Error; err != nil {
+		t.Fatalf("load task: %v", err)
+	}
+	if task.Status != StatusFailed || task.LastError != "smtp down" || task.FinishedAt == nil {
+		t.Errorf("failed task = %+v", task)
+	}
+}
+
+func TestRetryUntilMaxAttempts(t *testing.T) {
+	q := newTestQueue(t)
+	ctx := context.Background()
+
+	id, _ := q.Enqueue(ctx, "email", nil, WithMaxAttempts(2))
+	if _, err := q.Dequeue(ctx, "w1", nil); err != nil {
+		t.Fatalf("Dequeue: %v", err)
+	}
+	if err := q.Retry(ctx, id, 0); err != nil {
+		t.Fatalf("first Retry: %v", err)
+	}
+
+	task, err := q.Dequeue(ctx, "w2", nil)
+	if err != nil {
+		t.Fatalf("Dequeue after Retry: %v", err)
+	}
+	if task.ID != id || task.Attempts != 2 {
+		t.Errorf("Dequeue after Retry = %+v, want attempt 2 of %s", task, id)
+	}
+	if err := q.Retry(ctx, id, 0); !errors.Is(err, ErrMaxAttemptsExceeded) {
+		t.Fatalf("second Retry: err = %v, want ErrMaxAttemptsExceeded", err)
+	}
+	if _, err := q.Dequeue(ctx, "w3", nil); !errors.Is(err, ErrNoTask) {
+		t.Errorf("Dequeue after exhausting attempts: err = %v, want ErrNoTask", err)
+	}
+}
+
+func TestConcurrentWorkersClaimEachTaskOnce(t *testing.T) {
+	q := newTestQueue(t)
+	ctx := context.Background()
+
+	const tasks, workers = 50, 8
+	for i := 0; i < tasks; i++ {
+		if _, err := q.Enqueue(ctx, "job", i); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		claimed = make(map[string]int)
+		wg      sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(workerID string) {
+			defer wg.Done()
+			for {
+				task, err := q.Dequeue(ctx, workerID, nil)
+				if errors.Is(err, ErrNoTask) {
+					return
+				}
+				if err != nil {
+					t.Errorf("Dequeue: %v", err)
+					return
+				}
+				mu.Lock()
+				claimed[task.ID]++
+				mu.Unlock()
+				if err := q.Complete(ctx, task.ID); err != nil {
+					t.Errorf("Complete: %v", err)
+				}
+			}
+		}(string(rune('a' + w)))
+	}
+	wg.Wait()
+
+	if len(claimed) != tasks {
+		t.Errorf("%d distinct tasks claimed, want %d", len(claimed), tasks)
+	}
+	for id, n := range claimed {
+		if n != 1 {
+			t.Errorf("task %s claimed %d times", id, n)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package testing
+
+import (
+	"fmt"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// NewTestDB 使用 dialector 打开数据库并为 models 建表，t.Cleanup 中按相反顺序删除这些表并关闭连接。
+// 打开或建表失败时直接 t.Fatal
+func NewTestDB(t testing.TB, dialector gorm.Dialector, models ...interface{}) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		t.Fatalf("testing: open database: %v", err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("testing: auto migrate: %v", err)
+	}
+	t.Cleanup(func() {
+		// 逆序删除，先删除引用其他表的表
+		for i := len(models) - 1; i >= 0; i-- {
+			if err := db.Migrator().DropTable(models[i]); err != nil {
+				t.Errorf("testing: drop table: %v", err)
+			}
+		}
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// WithTx 开启一个事务并在 t.Cleanup 中回滚，测试中通过返回的 *gorm.DB 执行的操作不会影响其他测试
+func WithTx(t testing.TB, db *gorm.DB) *gorm.DB {
+	t.Helper()
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("testing: begin transaction: %v", tx.Error)
+	}
+	t.Cleanup(func() {
+		tx.Rollback()
+	})
+	return tx
+}
+
+// SeedDB 依次插入 entities，每个元素可以是模型指针或模型切片，遇到第一个错误时返回
+func SeedDB(t testing.TB, db *gorm.DB, entities ...interface{}) error {
+	t.Helper()
+	for i, entity := range entities {
+		if err := db.Create(entity).Error; err != nil {
+			return fmt.Errorf("testing: seed entity %d (%T): %w", i, entity, err)
+		}
+	}
+	return nil
+}
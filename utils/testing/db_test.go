@@ -0,0 +1,81 @@
+package testing
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+// sqliteFile 返回临时目录中的 SQLite 文件；事务与外部查询使用不同连接，不能使用内存库
+func sqliteFile(t *testing.T) string {
+	return filepath.Join(t.TempDir(), "test.db")
+}
+
+func TestWithTxIsolatesSubtests(t *testing.T) {
+	db := NewTestDB(t, sqlite.Open(sqliteFile(t)), &widget{})
+
+	for _, name := range []string{"first", "second"} {
+		t.Run(name, func(t *testing.T) {
+			tx := WithTx(t, db)
+			if err := SeedDB(t, tx, &widget{ID: 1, Name: name}, []widget{{ID: 2, Name: name}}); err != nil {
+				t.Fatalf("SeedDB: %v", err)
+			}
+			// 上一个子测试的数据已回滚，主键 1、2 不会冲突
+			var count int64
+			if err := tx.Model(&widget{}).Count(&count).Error; err != nil {
+				t.Fatal(err)
+			}
+			if count != 2 {
+				t.Errorf("count = %d, want 2", count)
+			}
+		})
+	}
+
+	var count int64
+	if err := db.Model(&widget{}).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("count after subtests = %d, want 0", count)
+	}
+}
+
+func TestSeedDBReturnsError(t *testing.T) {
+	db := WithTx(t, NewTestDB(t, sqlite.Open(sqliteFile(t)), &widget{}))
+	err := SeedDB(t, db, &widget{ID: 1}, &widget{ID: 1})
+	if err == nil || !strings.Contains(err.Error(), "seed entity 1") {
+		t.Errorf("SeedDB = %v, want an error for entity 1", err)
+	}
+}
+
+func TestNewTestDBDropsTables(t *testing.T) {
+	path := sqliteFile(t)
+	tb := &recordingTB{}
+	db := NewTestDB(tb, sqlite.Open(path), &widget{})
+	if !db.Migrator().HasTable(&widget{}) {
+		t.Fatal("table not created")
+	}
+	tb.runCleanups()
+	if len(tb.errors) != 0 {
+		t.Fatalf("cleanup errors = %q", tb.errors)
+	}
+
+	reopened, err := gorm.Open(sqlite.Open(path), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, _ := reopened.DB()
+	defer sqlDB.Close()
+	if reopened.Migrator().HasTable(&widget{}) {
+		t.Error("table still exists after cleanup")
+	}
+}
@@ -0,0 +1,145 @@
+package testing
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// orderFile 声明表之间依赖关系的文件名，格式为 "表名: [被依赖的表, ...]"，例如
+//
+//	orders: [users]
+//	users: []
+const orderFile = "_order.yaml"
+
+// fixtureBatchSize 批量插入的每批行数
+const fixtureBatchSize = 100
+
+// LoadFixtures 加载 fixturesDir 下的所有 .yaml 文件，文件名（不含扩展名）即表名，内容为行的列表。
+// 先按依赖的逆序清空所有表，再按 _order.yaml 的依赖顺序批量插入，保证外键引用的行先写入
+func LoadFixtures(db *gorm.DB, fixturesDir string) error {
+	paths, err := filepath.Glob(filepath.Join(fixturesDir, "*.yaml"))
+	if err != nil {
+		return err
+	}
+	rows := make(map[string][]map[string]interface{})
+	for _, path := range paths {
+		if filepath.Base(path) == orderFile {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var tableRows []map[string]interface{}
+		if err := yaml.Unmarshal(data, &tableRows); err != nil {
+			return fmt.Errorf("fixtures: failed to parse %s: %v", path, err)
+		}
+		rows[strings.TrimSuffix(filepath.Base(path), ".yaml")] = tableRows
+	}
+
+	deps, err := readOrder(filepath.Join(fixturesDir, orderFile))
+	if err != nil {
+		return err
+	}
+	tables := make([]string, 0, len(rows))
+	for table := range rows {
+		tables = append(tables, table)
+	}
+	order, err := sortTables(tables, deps)
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for i := len(order) - 1; i >= 0; i-- {
+			if err := cleanTable(tx, order[i]); err != nil {
+				return err
+			}
+		}
+		for _, table := range order {
+			if len(rows[table]) == 0 {
+				continue
+			}
+			if err := tx.Table(table).CreateInBatches(rows[table], fixtureBatchSize).Error; err != nil {
+				return fmt.Errorf("fixtures: failed to insert into %s: %v", table, err)
+			}
+		}
+		return nil
+	})
+}
+
+// CleanFixtures 按给定顺序清空表
+func CleanFixtures(db *gorm.DB, tables ...string) error {
+	for _, table := range tables {
+		if err := cleanTable(db, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanTable 删除表中所有行；使用 DELETE 而不是 TRUNCATE，以兼容 SQLite 和带外键的表
+func cleanTable(db *gorm.DB, table string) error {
+	if err := db.Exec("DELETE FROM ?", clause.Table{Name: table}).Error; err != nil {
+		return fmt.Errorf("fixtures: failed to clean %s: %v", table, err)
+	}
+	return nil
+}
+
+// readOrder 读取依赖关系文件，文件不存在时视为没有依赖
+func readOrder(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var deps map[string][]string
+	if err := yaml.Unmarshal(data, &deps); err != nil {
+		return nil, fmt.Errorf("fixtures: failed to parse %s: %v", path, err)
+	}
+	return deps, nil
+}
+
+// sortTables 拓扑排序，被依赖的表排在前面；没有依赖关系的表按名称排序，出现循环依赖时返回错误
+func sortTables(tables []string, deps map[string][]string) ([]string, error) {
+	sort.Strings(tables)
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	order := make([]string, 0, len(tables))
+	var visit func(table string) error
+	visit = func(table string) error {
+		switch state[table] {
+		case visiting:
+			return fmt.Errorf("fixtures: circular dependency involving %s", table)
+		case visited:
+			return nil
+		}
+		state[table] = visiting
+		for _, dep := range deps[table] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[table] = visited
+		order = append(order, table)
+		return nil
+	}
+	for _, table := range tables {
+		if err := visit(table); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
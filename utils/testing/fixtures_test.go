@@ -0,0 +1,128 @@
+package testing
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newFixtureDB 打开开启外键约束的 SQLite 内存库，orders.user_id 引用 users.id
+func newFixtureDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:?_pragma=foreign_keys(1)"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 内存库每个连接都是独立的数据库，限制为单连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	for _, ddl := range []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)",
+		"CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users(id), amount INTEGER)",
+	} {
+		if err := db.Exec(ddl).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func writeFixtures(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func countRows(t *testing.T, db *gorm.DB, table string) int64 {
+	t.Helper()
+	var n int64
+	if err := db.Table(table).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestLoadFixturesRespectsForeignKeyOrder(t *testing.T) {
+	db := newFixtureDB(t)
+	// 按名称排序 orders 在 users 之前，只有遵循 _order.yaml 才能满足外键约束
+	dir := writeFixtures(t, map[string]string{
+		"_order.yaml": "orders: [users]\nusers: []\n",
+		"users.yaml":  "- {id: 1, name: alice}\n- {id: 2, name: bob}\n",
+		"orders.yaml": "- {id: 10, user_id: 1, amount: 5}\n- {id: 11, user_id: 2, amount: 7}\n",
+	})
+
+	var inserted []string
+	db.Callback().Create().After("gorm:create").Register("test:record_table", func(tx *gorm.DB) {
+		inserted = append(inserted, tx.Statement.Table)
+	})
+	if err := LoadFixtures(db, dir); err != nil {
+		t.Fatalf("LoadFixtures: %v", err)
+	}
+	if want := []string{"users", "orders"}; !reflect.DeepEqual(inserted, want) {
+		t.Errorf("insert order = %v, want %v", inserted, want)
+	}
+	if countRows(t, db, "users") != 2 || countRows(t, db, "orders") != 2 {
+		t.Errorf("rows = %d users, %d orders", countRows(t, db, "users"), countRows(t, db, "orders"))
+	}
+
+	// 再次加载先清空旧数据（先清 orders 再清 users），结果不变
+	if err := LoadFixtures(db, dir); err != nil {
+		t.Fatalf("second LoadFixtures: %v", err)
+	}
+	if countRows(t, db, "users") != 2 || countRows(t, db, "orders") != 2 {
+		t.Errorf("rows after reload = %d users, %d orders", countRows(t, db, "users"), countRows(t, db, "orders"))
+	}
+
+	if err := CleanFixtures(db, "orders", "users"); err != nil {
+		t.Fatalf("CleanFixtures: %v", err)
+	}
+	if countRows(t, db, "users") != 0 || countRows(t, db, "orders") != 0 {
+		t.Error("CleanFixtures left rows behind")
+	}
+}
+
+func TestLoadFixturesWithoutOrderViolatesForeignKey(t *testing.T) {
+	db := newFixtureDB(t)
+	// 没有 _order.yaml 时按名称排序，orders 先插入会违反外键约束，事务整体回滚
+	dir := writeFixtures(t, map[string]string{
+		"users.yaml":  "- {id: 1, name: alice}\n",
+		"orders.yaml": "- {id: 10, user_id: 1, amount: 5}\n",
+	})
+	if err := LoadFixtures(db, dir); err == nil {
+		t.Fatal("LoadFixtures succeeded despite the foreign key")
+	}
+	if countRows(t, db, "users") != 0 {
+		t.Error("failed load was not rolled back")
+	}
+}
+
+func TestSortTables(t *testing.T) {
+	order, err := sortTables([]string{"items", "orders", "users"}, map[string][]string{
+		"items":  {"orders"},
+		"orders": {"users"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"users", "orders", "items"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+
+	if _, err := sortTables([]string{"a", "b"}, map[string][]string{"a": {"b"}, "b": {"a"}}); err == nil {
+		t.Error("sortTables accepted a circular dependency")
+	}
+}
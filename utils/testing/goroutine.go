@@ -0,0 +1,73 @@
+package testing
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// leakCheckAttempts / leakCheckInterval 结束时等待 goroutine 自行退出的重试次数与间隔
+const (
+	leakCheckAttempts = 20
+	leakCheckInterval = 10 * time.Millisecond
+)
+
+// AssertNoLeaks 记录测试开始时的 goroutine，并在 t.Cleanup 中检查测试结束时
+// 是否残留新的、堆栈中包含调用方测试包名的 goroutine，有则报告为泄漏
+func AssertNoLeaks(t testing.TB) {
+	t.Helper()
+	pkg := callerPackage(2)
+	baseline := goroutines()
+	t.Cleanup(func() {
+		var leaked []string
+		for attempt := 0; attempt < leakCheckAttempts; attempt++ {
+			leaked = leaked[:0]
+			for id, stack := range goroutines() {
+				if _, ok := baseline[id]; !ok && strings.Contains(stack, pkg) {
+					leaked = append(leaked, stack)
+				}
+			}
+			if len(leaked) == 0 {
+				return
+			}
+			time.Sleep(leakCheckInterval)
+		}
+		t.Errorf("found %d leaked goroutine(s):\n\n%s", len(leaked), strings.Join(leaked, "\n\n"))
+	})
+}
+
+// goroutines 返回当前所有 goroutine 的堆栈，以 "goroutine N" 为 key
+func goroutines() map[string]string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	result := make(map[string]string)
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		header, _, _ := strings.Cut(stack, " [")
+		if strings.HasPrefix(header, "goroutine ") {
+			result[header] = stack
+		}
+	}
+	return result
+}
+
+// callerPackage 返回调用栈第 skip 层函数所在的包路径，如 github.com/x/y/pkg
+func callerPackage(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return ""
+	}
+	name := runtime.FuncForPC(pc).Name()
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
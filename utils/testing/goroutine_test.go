@@ -0,0 +1,88 @@
+package testing
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// recordingTB 记录错误并由测试手动执行 cleanup 的 testing.TB，用于检验辅助函数本身
+type recordingTB struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Cleanup(fn func()) { r.cleanups = append(r.cleanups, fn) }
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingTB) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+	panic("recordingTB: Fatalf")
+}
+
+// runCleanups 与 testing 包一致，按注册的逆序执行
+func (r *recordingTB) runCleanups() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func TestAssertNoLeaksDetectsLeak(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	tb := &recordingTB{}
+	AssertNoLeaks(tb)
+	go func() { <-release }() // 故意泄漏，直到测试结束才放行
+	tb.runCleanups()
+
+	if len(tb.errors) != 1 {
+		t.Fatalf("errors = %q, want one leak report", tb.errors)
+	}
+	if !strings.Contains(tb.errors[0], "1 leaked goroutine") || !strings.Contains(tb.errors[0], "TestAssertNoLeaksDetectsLeak") {
+		t.Errorf("report = %q, want the leaked goroutine's stack", tb.errors[0])
+	}
+}
+
+func TestAssertNoLeaksPasses(t *testing.T) {
+	tb := &recordingTB{}
+	AssertNoLeaks(tb)
+
+	done := make(chan struct{})
+	go func() { close(done) }()
+	<-done
+	// 在检查的重试时间内退出的 goroutine 也不算泄漏
+	go func() { time.Sleep(3 * leakCheckInterval) }()
+	tb.runCleanups()
+
+	if len(tb.errors) != 0 {
+		t.Errorf("errors = %q, want none", tb.errors)
+	}
+}
+
+func TestAssertNoLeaksIgnoresPreexisting(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	go func() { <-release }()
+
+	tb := &recordingTB{}
+	AssertNoLeaks(tb)
+	tb.runCleanups()
+
+	if len(tb.errors) != 0 {
+		t.Errorf("errors = %q, want goroutines started before the baseline ignored", tb.errors)
+	}
+}
+
+func TestCallerPackage(t *testing.T) {
+	if got, want := callerPackage(1), "github.com/kobyt2/common-services/utils/testing"; got != want {
+		t.Errorf("callerPackage = %q, want %q", got, want)
+	}
+}